@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	_ "time/tzdata" // the timezones of customer service profiles do not depend on the host
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/fx"
@@ -48,11 +49,12 @@ func newGinEngine(cfg *config.Config, log logger.Logger) *gin.Engine {
 	router := gin.New()
 	router.Use(middleware.Logger(log))
 	router.Use(gin.Recovery())
+	router.Use(middleware.ChangeAuthor())
 	return router
 }
 
 // registerRoutes sets up all API routes
-func registerRoutes(router *gin.Engine, db *sql.DB) {
+func registerRoutes(router *gin.Engine, db *sql.DB, cfg *config.Config) error {
 	// API v1 routes
 	apiV1 := router.Group("/api/v1")
 
@@ -66,7 +68,7 @@ func registerRoutes(router *gin.Engine, db *sql.DB) {
 
 	// Register feature routes
 	ota.RegisterOTAHandler(db, apiV1)
-	wms.RegisterWMSHandler(db, apiV1)
+	return wms.RegisterWMSHandler(db, cfg, apiV1)
 }
 
 // startServer starts the HTTP server
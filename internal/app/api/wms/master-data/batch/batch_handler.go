@@ -0,0 +1,247 @@
+package batch
+
+import (
+	"errors"
+	"strconv"
+
+	batchService "ecosystem.garyle/service/internal/app/service/wms/master-data/batch"
+	batchModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/batch"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/conflict"
+	"ecosystem.garyle/service/pkg/utils/filter"
+	"ecosystem.garyle/service/pkg/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+type BatchHandler struct {
+	batchService batchService.BatchService
+}
+
+func NewBatchHandler(batchService batchService.BatchService) *BatchHandler {
+	return &BatchHandler{batchService: batchService}
+}
+
+// create batch
+func (h *BatchHandler) CreateBatch(c *gin.Context) {
+	var batch batchModel.Batch
+	if err := c.ShouldBindJSON(&batch); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	createdBatch, err := h.batchService.Create(c.Request.Context(), &batch)
+	if err != nil {
+		if isValidationBatchError(err) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+
+		if err.Error() == "product not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		var conflictErr *conflict.Error
+		if errors.As(err, &conflictErr) {
+			response.Conflict(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, createdBatch, "Batch created successfully")
+}
+
+func isValidationBatchError(err error) bool {
+	validationErrors := []string{
+		"product_id is required",
+		"product is not batch tracked",
+		"batch_no is required",
+		"status must be active, quarantined or expired",
+		"expire_date must not be before manufacture_date",
+	}
+
+	for _, validationError := range validationErrors {
+		if err.Error() == validationError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// get list batches
+func (h *BatchHandler) GetListBatches(c *gin.Context) {
+	limit, page, params, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+
+	batches, err := h.batchService.List(c.Request.Context(), limit, page, params)
+	if err != nil {
+		response.Server(c, err.Error())
+		return
+	}
+
+	total, err := h.batchService.Count(c.Request.Context(), params)
+	if err != nil {
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPagination(c, batches, "Batches retrieved successfully", page, limit, total)
+}
+
+// get batches nearing expiry, ?days=30 is the window in days from today
+func (h *BatchHandler) GetExpiringBatches(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil {
+		response.BadRequest(c, "Invalid days value")
+		return
+	}
+
+	limit, page, params, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+
+	batches, total, err := h.batchService.ListExpiring(c.Request.Context(), days, limit, page, params)
+	if err != nil {
+		if err.Error() == "days must be between 0 and 3650" {
+			response.BadRequest(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPagination(c, batches, "Expiring batches retrieved successfully", page, limit, total)
+}
+
+// parseListQuery reads limit, page and the list filters, it writes the
+// error response itself and returns false when the filters are invalid
+func parseListQuery(c *gin.Context) (int, int, *filter.Params, bool) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+
+	params, err := filter.Parse(c.Request.URL.Query(), batchModel.QuerySpec)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return 0, 0, nil, false
+	}
+
+	return limit, page, params, true
+}
+
+// get batch by id
+func (h *BatchHandler) GetBatchByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid batch ID")
+		return
+	}
+
+	batch, err := h.batchService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		response.Server(c, err.Error())
+		return
+	}
+
+	if batch == nil {
+		response.NotFound(c, "Batch not found")
+		return
+	}
+
+	response.Success(c, batch, "Batch retrieved successfully")
+}
+
+// update batch by id
+func (h *BatchHandler) UpdateBatchByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid batch ID")
+		return
+	}
+
+	var batch batchModel.Batch
+	if err := c.ShouldBindJSON(&batch); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.batchService.UpdateByID(c.Request.Context(), &batch, id); err != nil {
+		if isValidationBatchError(err) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+
+		if err.Error() == "batch not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		var conflictErr *conflict.Error
+		if errors.As(err, &conflictErr) {
+			response.Conflict(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, batch, "Batch updated successfully")
+}
+
+// delete batch by id
+func (h *BatchHandler) DeleteBatchByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid batch ID")
+		return
+	}
+
+	if err := h.batchService.DeleteByID(c.Request.Context(), id); err != nil {
+		if err.Error() == "batch not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil, "Batch deleted successfully")
+}
+
+func (h *BatchHandler) RegisterBatchRoutes(router *gin.RouterGroup) {
+	batchRoutes := router.Group("/batch")
+	{
+		batchRoutes.POST("", h.CreateBatch)
+		batchRoutes.GET("", h.GetListBatches)
+		batchRoutes.GET("/expiring", h.GetExpiringBatches)
+		batchRoutes.GET("/:id", h.GetBatchByID)
+		batchRoutes.PUT("/:id", h.UpdateBatchByID)
+		batchRoutes.DELETE("/:id", h.DeleteBatchByID)
+	}
+}
@@ -3,19 +3,24 @@ package category
 import (
 	"strconv"
 
+	exportHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/exporter"
+	importHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/importer"
 	categoryService "ecosystem.garyle/service/internal/app/service/wms/master-data/category"
 	"ecosystem.garyle/service/internal/domain/model/wms/master-data/category"
+	"ecosystem.garyle/service/pkg/utils/filter"
 	"ecosystem.garyle/service/pkg/utils/response"
 	"github.com/gin-gonic/gin"
 )
 
 type categoryHandler struct {
 	categoryService categoryService.CategoryService
+	importer        *importHandler.Handler
 }
 
-func NewCategoryHandler(categoryService categoryService.CategoryService) *categoryHandler {
+func NewCategoryHandler(categoryService categoryService.CategoryService, importer *importHandler.Handler) *categoryHandler {
 	return &categoryHandler{
 		categoryService: categoryService,
+		importer:        importer,
 	}
 }
 
@@ -32,21 +37,9 @@ func (h *categoryHandler) CreateNewCategory(c *gin.Context) {
 		return
 	}
 
-	// check existing category
-	existingCategory, err := h.categoryService.GetByID(c.Request.Context(), category.ID)
-	if err != nil {
-		response.Server(c, err.Error())
-		return
-	}
-
-	if existingCategory != nil {
-		response.BadRequest(c, "category already exists")
-		return
-	}
-
 	createdCategory, err := h.categoryService.Create(c.Request.Context(), &category)
 	if err != nil {
-		if validateCreateOrUpdateCategory(err) {
+		if validateCreateOrUpdateCategory(err) || validateMoveCategory(err) {
 			response.BadRequest(c, err.Error())
 			return
 		}
@@ -69,13 +62,19 @@ func (h *categoryHandler) GetAllCategories(c *gin.Context) {
 		page = 1
 	}
 
-	categories, err := h.categoryService.List(c.Request.Context(), limit, page)
+	params, err := filter.Parse(c.Request.URL.Query(), category.QuerySpec)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	categories, err := h.categoryService.List(c.Request.Context(), limit, page, params)
 	if err != nil {
 		response.Server(c, err.Error())
 		return
 	}
 
-	total, err := h.categoryService.Count(c.Request.Context())
+	total, err := h.categoryService.Count(c.Request.Context(), params)
 	if err != nil {
 		response.Server(c, err.Error())
 		return
@@ -129,7 +128,7 @@ func (h *categoryHandler) UpdateCategory(c *gin.Context) {
 
 	updatedCategory, err := h.categoryService.Update(c.Request.Context(), &category, id)
 	if err != nil {
-		if validateCreateOrUpdateCategory(err) {
+		if validateCreateOrUpdateCategory(err) || validateMoveCategory(err) {
 			response.BadRequest(c, err.Error())
 			return
 		}
@@ -153,13 +152,19 @@ func (h *categoryHandler) DeleteCategory(c *gin.Context) {
 		return
 	}
 
-	err = h.categoryService.Delete(c.Request.Context(), id)
+	policy := category.DeletePolicy(c.Query("policy"))
+	err = h.categoryService.Delete(c.Request.Context(), id, policy)
 	if err != nil {
 		if err.Error() == "category not found" {
 			response.NotFound(c, err.Error())
 			return
 		}
 
+		if err.Error() == "policy must be one of block, cascade or reparent" || err.Error() == "category still has child categories" {
+			response.BadRequest(c, err.Error())
+			return
+		}
+
 		response.Server(c, err.Error())
 		return
 	}
@@ -170,6 +175,156 @@ func (h *categoryHandler) DeleteCategory(c *gin.Context) {
 func validateCreateOrUpdateCategory(err error) bool {
 	validations := []string{
 		"category name is required",
+		"name is required",
+		"category already exists",
+		"attribute name must start with a lowercase letter and contain only lowercase letters, digits or underscores",
+		"attribute names must be unique",
+		"attribute type must be one of string, number, enum or boolean",
+		"unit is only allowed on number attributes",
+		"values are only allowed on enum attributes",
+		"enum attributes need at least one value",
+		"enum values must be unique and not empty",
+	}
+
+	for _, validation := range validations {
+		if err.Error() == validation {
+			return true
+		}
+	}
+	return false
+}
+
+// Export Categories as CSV, XLSX or NDJSON
+func (h *categoryHandler) ExportCategories(c *gin.Context) {
+	exportHandler.HandleExport(c, "categories", category.QuerySpec, category.ExportColumns, h.categoryService.Export)
+}
+
+// Import Categories from a CSV or XLSX file
+func (h *categoryHandler) ImportCategories(c *gin.Context) {
+	h.importer.HandleImport(c, "category", h.categoryService.Import)
+}
+
+// GetCategoryTree returns every category nested under its parent
+func (h *categoryHandler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.categoryService.GetTree(c.Request.Context())
+	if err != nil {
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, tree, "Category tree retrieved successfully")
+}
+
+// GetCategorySubtree returns a category with its descendants nested under it
+func (h *categoryHandler) GetCategorySubtree(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid category ID")
+		return
+	}
+
+	subtree, err := h.categoryService.GetSubtree(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "category not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, subtree, "Category subtree retrieved successfully")
+}
+
+// GetCategoryAncestors returns the breadcrumb from the root down to the category
+func (h *categoryHandler) GetCategoryAncestors(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid category ID")
+		return
+	}
+
+	ancestors, err := h.categoryService.GetAncestors(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "category not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, ancestors, "Category ancestors retrieved successfully")
+}
+
+// GetCategoryAttributes returns the product attribute schema of a category,
+// including the attributes inherited from its ancestors
+func (h *categoryHandler) GetCategoryAttributes(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid category ID")
+		return
+	}
+
+	schema, err := h.categoryService.GetSchema(c.Request.Context(), id)
+	if err != nil {
+		if err.Error() == "category not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, schema, "Category attributes retrieved successfully")
+}
+
+// MoveCategory places a category under another parent, rejecting cycles
+func (h *categoryHandler) MoveCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid category ID")
+		return
+	}
+
+	var request category.MoveRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	movedCategory, err := h.categoryService.Move(c.Request.Context(), id, request.ParentID)
+	if err != nil {
+		if err.Error() == "category not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		if validateMoveCategory(err) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, movedCategory, "Category moved successfully")
+}
+
+func validateMoveCategory(err error) bool {
+	validations := []string{
+		"parent_id must be a positive integer",
+		"parent category not found",
+		"category cannot be moved under itself or one of its descendants",
 	}
 
 	for _, validation := range validations {
@@ -185,9 +340,16 @@ func (h *categoryHandler) RegisterCategoryRoutes(router *gin.RouterGroup) {
 	categoryRouter := router.Group("/categories")
 	{
 		categoryRouter.POST("/", h.CreateNewCategory)
+		categoryRouter.POST("/import", h.ImportCategories)
 		categoryRouter.GET("/", h.GetAllCategories)
+		categoryRouter.GET("/export", h.ExportCategories)
+		categoryRouter.GET("/tree", h.GetCategoryTree)
 		categoryRouter.GET("/:id", h.GetCategoryByID)
+		categoryRouter.GET("/:id/subtree", h.GetCategorySubtree)
+		categoryRouter.GET("/:id/ancestors", h.GetCategoryAncestors)
+		categoryRouter.GET("/:id/attributes", h.GetCategoryAttributes)
 		categoryRouter.PUT("/:id", h.UpdateCategory)
+		categoryRouter.PUT("/:id/move", h.MoveCategory)
 		categoryRouter.DELETE("/:id", h.DeleteCategory)
 	}
 }
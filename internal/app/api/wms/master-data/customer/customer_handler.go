@@ -1,20 +1,28 @@
 package customer
 
 import (
+	"errors"
 	"strconv"
 
+	exportHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/exporter"
+	importHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/importer"
+	partyHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/party"
 	customerService "ecosystem.garyle/service/internal/app/service/wms/master-data/customer"
+	partyService "ecosystem.garyle/service/internal/app/service/wms/master-data/party"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/conflict"
 	customerModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/customer"
+	"ecosystem.garyle/service/pkg/utils/filter"
 	"ecosystem.garyle/service/pkg/utils/response"
 	"github.com/gin-gonic/gin"
 )
 
 type customerHandler struct {
 	customerService customerService.CustomerService
+	importer        *importHandler.Handler
 }
 
-func NewCustomerHandler(customerService customerService.CustomerService) *customerHandler {
-	return &customerHandler{customerService: customerService}
+func NewCustomerHandler(customerService customerService.CustomerService, importer *importHandler.Handler) *customerHandler {
+	return &customerHandler{customerService: customerService, importer: importer}
 }
 
 // Create new customer
@@ -43,6 +51,12 @@ func (h *customerHandler) CreateCustomer(c *gin.Context) {
 			return
 		}
 
+		var conflictErr *conflict.Error
+		if errors.As(err, &conflictErr) {
+			response.Conflict(c, err.Error())
+			return
+		}
+
 		response.Server(c, err.Error())
 		return
 	}
@@ -62,7 +76,13 @@ func (h *customerHandler) GetListCustomer(c *gin.Context) {
 		page = 1
 	}
 
-	customers, err := h.customerService.List(c.Request.Context(), limit, page)
+	params, err := filter.Parse(c.Request.URL.Query(), customerModel.QuerySpec)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	customers, err := h.customerService.List(c.Request.Context(), limit, page, params)
 	if err != nil {
 		response.Server(c, err.Error())
 		return
@@ -73,7 +93,7 @@ func (h *customerHandler) GetListCustomer(c *gin.Context) {
 		return
 	}
 
-	totalCustomers, err := h.customerService.Count(c.Request.Context())
+	totalCustomers, err := h.customerService.Count(c.Request.Context(), params)
 	if err != nil {
 		response.Server(c, err.Error())
 		return
@@ -144,6 +164,16 @@ func (h *customerHandler) UpdateCustomerByID(c *gin.Context) {
 			response.NotFound(c, err.Error())
 			return
 		}
+		if err.Error() == "customer has been erased" {
+			response.Conflict(c, err.Error())
+			return
+		}
+
+		var conflictErr *conflict.Error
+		if errors.As(err, &conflictErr) {
+			response.Conflict(c, err.Error())
+			return
+		}
 
 		response.Server(c, err.Error())
 		return
@@ -184,6 +214,7 @@ func validateCreateCustomerError(err error) bool {
 		"name is required",
 		"address is required",
 		"contact is required",
+		partyService.TaxIDTakenError("customer"),
 	}
 
 	for _, validationError := range validationErrors {
@@ -192,7 +223,17 @@ func validateCreateCustomerError(err error) bool {
 		}
 	}
 
-	return false
+	return partyService.IsValidationError(err)
+}
+
+// Export Customers as CSV, XLSX or NDJSON
+func (h *customerHandler) ExportCustomers(c *gin.Context) {
+	exportHandler.HandleExport(c, "customers", customerModel.QuerySpec, customerModel.ExportColumns, h.customerService.Export)
+}
+
+// Import Customers from a CSV or XLSX file
+func (h *customerHandler) ImportCustomers(c *gin.Context) {
+	h.importer.HandleImport(c, "customer", h.customerService.Import)
 }
 
 // Customer Route Groups
@@ -200,9 +241,20 @@ func (h *customerHandler) RegisterCustomerRoutes(router *gin.RouterGroup) {
 	customerRouter := router.Group("/customers")
 	{
 		customerRouter.POST("", h.CreateCustomer)
+		customerRouter.POST("/import", h.ImportCustomers)
 		customerRouter.GET("", h.GetListCustomer)
+		customerRouter.GET("/export", h.ExportCustomers)
 		customerRouter.GET("/:id", h.GetCustomerByID)
 		customerRouter.PUT("/:id", h.UpdateCustomerByID)
 		customerRouter.DELETE("/:id", h.DeleteCustomerByID)
+		customerRouter.GET("/:id/service-profile", h.GetServiceProfile)
+		customerRouter.PUT("/:id/service-profile", h.SaveServiceProfile)
+		customerRouter.DELETE("/:id/service-profile", h.DeleteServiceProfile)
+		customerRouter.POST("/:id/service-profile/check", h.CheckOrder)
+		customerRouter.POST("/:id/erase", h.EraseCustomer)
+		customerRouter.POST("/encryption/rotate", h.RotateKeys)
 	}
+
+	partyHandler.RegisterDetailRoutes(customerRouter, h.customerService, "customer")
+	partyHandler.RegisterDuplicateRoutes(customerRouter, h.customerService, "customer")
 }
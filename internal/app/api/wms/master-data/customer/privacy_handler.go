@@ -0,0 +1,43 @@
+package customer
+
+import (
+	"ecosystem.garyle/service/pkg/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// EraseCustomer anonymizes the personal data of the customer on an erasure
+// request. The customer keeps its id, so historical orders stay valid.
+func (h *customerHandler) EraseCustomer(c *gin.Context) {
+	customerID, ok := parseCustomerID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.customerService.Erase(c.Request.Context(), customerID); err != nil {
+		if err.Error() == "customer not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{"id": customerID, "erased": true}, "Customer erased successfully")
+}
+
+// RotateKeys re-encrypts the customer personal data with the active key
+func (h *customerHandler) RotateKeys(c *gin.Context) {
+	rotated, err := h.customerService.RotateKeys(c.Request.Context())
+	if err != nil {
+		if err.Error() == "customer encryption keys are not configured" {
+			response.BadRequest(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{"rotated": rotated}, "Customer personal data rotated successfully")
+}
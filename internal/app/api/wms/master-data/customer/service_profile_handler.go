@@ -0,0 +1,116 @@
+package customer
+
+import (
+	"strconv"
+
+	customerService "ecosystem.garyle/service/internal/app/service/wms/master-data/customer"
+	customerModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/customer"
+	"ecosystem.garyle/service/pkg/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// GetServiceProfile returns the service-level profile of the customer
+func (h *customerHandler) GetServiceProfile(c *gin.Context) {
+	customerID, ok := parseCustomerID(c)
+	if !ok {
+		return
+	}
+
+	profile, err := h.customerService.GetServiceProfile(c.Request.Context(), customerID)
+	if err != nil {
+		serviceProfileError(c, err)
+		return
+	}
+
+	response.Success(c, profile, "Service profile retrieved successfully")
+}
+
+// SaveServiceProfile sets the service-level profile of the customer
+func (h *customerHandler) SaveServiceProfile(c *gin.Context) {
+	customerID, ok := parseCustomerID(c)
+	if !ok {
+		return
+	}
+
+	var profile customerModel.ServiceProfile
+	if err := c.ShouldBindJSON(&profile); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	saved, err := h.customerService.SaveServiceProfile(c.Request.Context(), customerID, &profile)
+	if err != nil {
+		serviceProfileError(c, err)
+		return
+	}
+
+	response.Success(c, saved, "Service profile saved successfully")
+}
+
+// DeleteServiceProfile puts the customer back on the default profile
+func (h *customerHandler) DeleteServiceProfile(c *gin.Context) {
+	customerID, ok := parseCustomerID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.customerService.DeleteServiceProfile(c.Request.Context(), customerID); err != nil {
+		serviceProfileError(c, err)
+		return
+	}
+
+	response.Success(c, nil, "Service profile deleted successfully")
+}
+
+// CheckOrder tells outbound processing how an order of the customer must be served
+func (h *customerHandler) CheckOrder(c *gin.Context) {
+	customerID, ok := parseCustomerID(c)
+	if !ok {
+		return
+	}
+
+	var check customerModel.OrderCheck
+	if err := c.ShouldBindJSON(&check); err != nil && err.Error() != "EOF" {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	decision, err := h.customerService.CheckOrder(c.Request.Context(), customerID, check)
+	if err != nil {
+		serviceProfileError(c, err)
+		return
+	}
+
+	response.Success(c, decision, "Order checked successfully")
+}
+
+func parseCustomerID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid customer ID")
+		return 0, false
+	}
+
+	return id, true
+}
+
+func serviceProfileError(c *gin.Context, err error) {
+	if err.Error() == "customer not found" || err.Error() == "service profile not found" {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	for _, message := range customerService.ServiceProfileValidationErrors() {
+		if err.Error() == message {
+			response.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	response.Server(c, err.Error())
+}
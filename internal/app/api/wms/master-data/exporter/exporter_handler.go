@@ -0,0 +1,101 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"ecosystem.garyle/service/pkg/utils/filter"
+	"ecosystem.garyle/service/pkg/utils/response"
+	"ecosystem.garyle/service/pkg/utils/spreadsheet"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportFunc is the Export method of a master-data service
+type ExportFunc[T spreadsheet.Valuer] func(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(item T) error) error
+
+// HandleExport streams the rows returned by export to the client.
+//
+// It accepts the same filter and sort parameters as the list endpoint, plus:
+//   - format: csv (default), xlsx or ndjson
+//   - columns: comma separated subset of the export columns
+//   - include_deleted: also export soft-deleted rows
+func HandleExport[T spreadsheet.Valuer](c *gin.Context, entity string, spec filter.Spec, availableColumns []string, export ExportFunc[T]) {
+	params, err := filter.Parse(c.Request.URL.Query(), spec)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	format, err := spreadsheet.ParseFormat(c.DefaultQuery("format", string(spreadsheet.CSV)))
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	columns, err := parseColumns(c.Query("columns"), availableColumns)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	includeDeleted, err := strconv.ParseBool(c.DefaultQuery("include_deleted", "false"))
+	if err != nil {
+		response.BadRequest(c, "Invalid include_deleted value")
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", entity, time.Now().Format("20060102-150405"), format)
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	writer, err := spreadsheet.NewWriter(c.Writer, format, columns)
+	if err != nil {
+		response.Server(c, err.Error())
+		return
+	}
+
+	err = export(c.Request.Context(), params, includeDeleted, func(item T) error {
+		return writer.Write(spreadsheet.Values(item, columns))
+	})
+	if err == nil {
+		err = writer.Close()
+	}
+
+	if err != nil {
+		// once rows are streamed the status is already sent, all we can
+		// do is stop writing
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "")
+			c.Header("Content-Disposition", "")
+			response.Server(c, err.Error())
+			return
+		}
+
+		_ = c.Error(err)
+	}
+}
+
+func parseColumns(raw string, availableColumns []string) ([]string, error) {
+	if raw == "" {
+		return availableColumns, nil
+	}
+
+	allowed := make(map[string]bool, len(availableColumns))
+	for _, column := range availableColumns {
+		allowed[column] = true
+	}
+
+	var columns []string
+	for _, column := range strings.Split(raw, ",") {
+		column = strings.TrimSpace(column)
+		if !allowed[column] {
+			return nil, fmt.Errorf("unknown column %q, available columns: %s", column, strings.Join(availableColumns, ", "))
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
@@ -0,0 +1,134 @@
+package floormap
+
+import (
+	"net/http"
+	"strconv"
+
+	floorMapService "ecosystem.garyle/service/internal/app/service/wms/master-data/floormap"
+	floorMapModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/floormap"
+	"ecosystem.garyle/service/pkg/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+type FloorMapHandler struct {
+	floorMapService floorMapService.FloorMapService
+}
+
+func NewFloorMapHandler(floorMapService floorMapService.FloorMapService) *FloorMapHandler {
+	return &FloorMapHandler{floorMapService: floorMapService}
+}
+
+// get the floor map of a warehouse
+func (h *FloorMapHandler) GetFloorMap(c *gin.Context) {
+	warehouseID, ok := parseWarehouseID(c)
+	if !ok {
+		return
+	}
+
+	floorMap, err := h.floorMapService.Get(c.Request.Context(), warehouseID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, floorMap, "Floor map retrieved successfully")
+}
+
+// import the walkway graph and the location coordinates of a warehouse as JSON
+func (h *FloorMapHandler) ImportFloorMap(c *gin.Context) {
+	warehouseID, ok := parseWarehouseID(c)
+	if !ok {
+		return
+	}
+
+	var floorMap floorMapModel.FloorMap
+	if err := c.ShouldBindJSON(&floorMap); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	imported, err := h.floorMapService.Import(c.Request.Context(), warehouseID, &floorMap)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, imported, "Floor map imported successfully")
+}
+
+// render the floor layout of a warehouse as SVG
+func (h *FloorMapHandler) GetFloorMapSVG(c *gin.Context) {
+	warehouseID, ok := parseWarehouseID(c)
+	if !ok {
+		return
+	}
+
+	svg, err := h.floorMapService.RenderSVG(c.Request.Context(), warehouseID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "image/svg+xml", svg)
+}
+
+// get the walking distance between two locations, ?from=&to= are location ids
+func (h *FloorMapHandler) GetDistance(c *gin.Context) {
+	warehouseID, ok := parseWarehouseID(c)
+	if !ok {
+		return
+	}
+
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from <= 0 {
+		response.BadRequest(c, "Invalid from location ID")
+		return
+	}
+
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil || to <= 0 {
+		response.BadRequest(c, "Invalid to location ID")
+		return
+	}
+
+	route, err := h.floorMapService.Route(c.Request.Context(), warehouseID, from, to)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response.Success(c, route, "Walking distance calculated successfully")
+}
+
+func parseWarehouseID(c *gin.Context) (int, bool) {
+	warehouseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || warehouseID <= 0 {
+		response.BadRequest(c, "Invalid warehouse ID")
+		return 0, false
+	}
+
+	return warehouseID, true
+}
+
+// respondError maps the floor map errors to their response, the graph
+// validation errors name the node or edge so they are all bad requests
+func respondError(c *gin.Context, err error) {
+	if err.Error() == "warehouse not found" {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.BadRequest(c, err.Error())
+}
+
+func (h *FloorMapHandler) RegisterFloorMapRoutes(router *gin.RouterGroup) {
+	router.GET("/warehouse/:id/floor-map", h.GetFloorMap)
+	router.PUT("/warehouse/:id/floor-map", h.ImportFloorMap)
+	router.GET("/warehouse/:id/floor-map/svg", h.GetFloorMapSVG)
+	router.GET("/warehouse/:id/distance", h.GetDistance)
+}
@@ -0,0 +1,143 @@
+package history
+
+import (
+	"strconv"
+	"time"
+
+	historyService "ecosystem.garyle/service/internal/app/service/wms/master-data/history"
+	historyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/history"
+	"ecosystem.garyle/service/pkg/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+type HistoryHandler struct {
+	historyService historyService.HistoryService
+}
+
+func NewHistoryHandler(historyService historyService.HistoryService) *HistoryHandler {
+	return &HistoryHandler{historyService: historyService}
+}
+
+// ListEntities returns the entities that have a change history
+func (h *HistoryHandler) ListEntities(c *gin.Context) {
+	response.Success(c, historyModel.EntityNames(), "History entities retrieved successfully")
+}
+
+// ListVersions returns the versions of a row, newest first
+func (h *HistoryHandler) ListVersions(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+
+	versions, total, err := h.historyService.List(c.Request.Context(), c.Param("entity"), id, limit, page)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.SuccessWithPagination(c, versions, "History retrieved successfully", page, limit, total)
+}
+
+// Diff returns the fields that changed between two versions of a row
+func (h *HistoryHandler) Diff(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	from, err := strconv.Atoi(c.DefaultQuery("from", "0"))
+	if err != nil {
+		response.BadRequest(c, "from and to must be version numbers")
+		return
+	}
+
+	to, err := strconv.Atoi(c.DefaultQuery("to", "0"))
+	if err != nil {
+		response.BadRequest(c, "from and to must be version numbers")
+		return
+	}
+
+	diff, err := h.historyService.Diff(c.Request.Context(), c.Param("entity"), id, from, to)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.Success(c, diff, "Diff retrieved successfully")
+}
+
+// AsOf returns a row as it was at a time
+func (h *HistoryHandler) AsOf(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	at, err := historyModel.ParseAt(c.Query("at"), time.Now())
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	version, err := h.historyService.AsOf(c.Request.Context(), c.Param("entity"), id, at)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.Success(c, version, "Version retrieved successfully")
+}
+
+func parseID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid ID")
+		return 0, false
+	}
+
+	return id, true
+}
+
+func (h *HistoryHandler) fail(c *gin.Context, err error) {
+	switch {
+	case err.Error() == historyService.UnknownEntityError(), err.Error() == "from and to must be version numbers":
+		response.BadRequest(c, err.Error())
+	case isNotFound(err):
+		response.NotFound(c, err.Error())
+	default:
+		response.Server(c, err.Error())
+	}
+}
+
+func isNotFound(err error) bool {
+	for _, entity := range historyModel.Entities {
+		if err.Error() == historyService.NotFoundError(entity) || err.Error() == historyService.VersionNotFoundError(entity) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RegisterHistoryRoutes registers the change history routes
+func (h *HistoryHandler) RegisterHistoryRoutes(router *gin.RouterGroup) {
+	historyRoutes := router.Group("/history")
+	{
+		historyRoutes.GET("", h.ListEntities)
+		historyRoutes.GET("/:entity/:id", h.ListVersions)
+		historyRoutes.GET("/:entity/:id/diff", h.Diff)
+		historyRoutes.GET("/:entity/:id/as-of", h.AsOf)
+	}
+}
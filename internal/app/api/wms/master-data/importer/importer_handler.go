@@ -0,0 +1,134 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	importerService "ecosystem.garyle/service/internal/app/service/wms/master-data/importer"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
+	"ecosystem.garyle/service/pkg/utils/response"
+	"ecosystem.garyle/service/pkg/utils/spreadsheet"
+	"github.com/gin-gonic/gin"
+)
+
+// files with more rows than this are imported as a background job
+const backgroundThreshold = 1000
+
+// RunFunc is the Import method of a master-data service
+type RunFunc func(ctx context.Context, records []spreadsheet.Record, opts imports.Options, progress func(done, total int)) (*imports.Result, error)
+
+// Handler runs the imports of the master-data handlers and serves their
+// background jobs from one job store
+type Handler struct {
+	jobs *importerService.JobStore
+}
+
+func NewImportHandler(jobs *importerService.JobStore) *Handler {
+	return &Handler{jobs: jobs}
+}
+
+// HandleImport reads the uploaded file and imports it with run.
+//
+// Query parameters:
+//   - mode: all_or_nothing (default) or skip_invalid
+//   - dry_run: validate only and return the row errors, nothing is saved
+//   - async: force a background job, large files always run in the background
+func (h *Handler) HandleImport(c *gin.Context, entity string, run RunFunc) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "Missing file. Please upload a .csv or .xlsx file in the \"file\" field.")
+		return
+	}
+
+	format, err := spreadsheet.DetectFormat(fileHeader.Filename)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	opts, err := parseOptions(c)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.Server(c, err.Error())
+		return
+	}
+	defer file.Close()
+
+	records, err := spreadsheet.Read(file, format)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	async, _ := strconv.ParseBool(c.DefaultQuery("async", "false"))
+	if !opts.DryRun && (async || len(records) > backgroundThreshold) {
+		job := h.jobs.Start(entity, len(records), func(ctx context.Context, progress func(done, total int)) (*imports.Result, error) {
+			return run(ctx, records, opts, progress)
+		})
+
+		response.Accepted(c, job, "Import started, check the import job for progress")
+		return
+	}
+
+	result, err := run(c.Request.Context(), records, opts, nil)
+	if err != nil {
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, result, importMessage(result))
+}
+
+func parseOptions(c *gin.Context) (imports.Options, error) {
+	opts := imports.Options{Mode: imports.Mode(c.DefaultQuery("mode", string(imports.AllOrNothing)))}
+	if opts.Mode != imports.AllOrNothing && opts.Mode != imports.SkipInvalid {
+		return opts, fmt.Errorf("invalid mode %q, use %s or %s", opts.Mode, imports.AllOrNothing, imports.SkipInvalid)
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		return opts, fmt.Errorf("invalid dry_run value %q", c.Query("dry_run"))
+	}
+	opts.DryRun = dryRun
+
+	return opts, nil
+}
+
+func importMessage(result *imports.Result) string {
+	switch {
+	case result.DryRun && result.InvalidRows == 0:
+		return "Dry run finished, all rows are valid"
+	case result.DryRun:
+		return fmt.Sprintf("Dry run finished, %d of %d rows are invalid", result.InvalidRows, result.TotalRows)
+	case result.Imported == 0 && result.InvalidRows > 0:
+		return "Import failed, no rows were saved"
+	case result.InvalidRows > 0:
+		return fmt.Sprintf("Imported %d rows, %d invalid rows were skipped", result.Imported, result.InvalidRows)
+	}
+
+	return fmt.Sprintf("Imported %d rows successfully", result.Imported)
+}
+
+// Get Import Job
+func (h *Handler) GetImportJob(c *gin.Context) {
+	job := h.jobs.Get(c.Param("id"))
+	if job == nil {
+		response.NotFound(c, "Import job not found")
+		return
+	}
+
+	response.Success(c, job, "Import job retrieved successfully")
+}
+
+func (h *Handler) RegisterImportJobRoutes(router *gin.RouterGroup) {
+	importJobRoutes := router.Group("/import-jobs")
+	{
+		importJobRoutes.GET("/:id", h.GetImportJob)
+	}
+}
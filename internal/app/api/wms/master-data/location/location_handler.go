@@ -1,20 +1,29 @@
 package location
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"strconv"
 
+	exportHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/exporter"
+	importHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/importer"
 	locationService "ecosystem.garyle/service/internal/app/service/wms/master-data/location"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/conflict"
 	locationModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
+	"ecosystem.garyle/service/pkg/utils/filter"
+	"ecosystem.garyle/service/pkg/utils/label"
 	"ecosystem.garyle/service/pkg/utils/response"
 	"github.com/gin-gonic/gin"
 )
 
 type Handler struct {
 	locationService locationService.LocationService
+	importer        *importHandler.Handler
 }
 
-func NewLocationHandler(locationService locationService.LocationService) *Handler {
-	return &Handler{locationService: locationService}
+func NewLocationHandler(locationService locationService.LocationService, importer *importHandler.Handler) *Handler {
+	return &Handler{locationService: locationService, importer: importer}
 }
 
 // create location
@@ -45,8 +54,14 @@ func (h *Handler) CreateLocation(c *gin.Context) {
 			return
 		}
 
-		if err.Error() == "pq: duplicate key value violates unique constraint \"locations_code_key\"" {
-			response.BadRequest(c, "code already exists, please use another code")
+		if err.Error() == "parent location not found" || err.Error() == "warehouse not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		var conflictErr *conflict.Error
+		if errors.As(err, &conflictErr) {
+			response.Conflict(c, err.Error())
 			return
 		}
 
@@ -63,6 +78,19 @@ func isValidationCreateOrUpdateLocationError(err error) bool {
 		"zone is required",
 		"type is required",
 		"capacity is required",
+		"min_temperature must not be above max_temperature",
+		"hazmat_classes must contain UN hazard classes such as 3 or 2.1",
+		"capacity must not be negative",
+		"level must be zone, aisle, rack, level or bin",
+		"parent location is not part of a hierarchy",
+		"level must be below the level of the parent location",
+		"level is required for a location in a warehouse",
+		"warehouse_id or parent_id is required for a location in a hierarchy",
+		"segment is required for a location in a hierarchy",
+		"segment must not contain '-'",
+		"max_weight must not be negative",
+		"max_volume must not be negative",
+		"max_units must not be negative",
 	}
 
 	for _, validationError := range validationErrors {
@@ -87,13 +115,19 @@ func (h *Handler) GetLocations(c *gin.Context) {
 		page = 1
 	}
 
-	locations, err := h.locationService.List(c.Request.Context(), limit, page)
+	params, err := filter.Parse(c.Request.URL.Query(), locationModel.QuerySpec)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	locations, err := h.locationService.List(c.Request.Context(), limit, page, params)
 	if err != nil {
 		response.BadRequest(c, err.Error())
 		return
 	}
 
-	total, err := h.locationService.Count(c.Request.Context())
+	total, err := h.locationService.Count(c.Request.Context(), params)
 	if err != nil {
 		response.BadRequest(c, err.Error())
 		return
@@ -157,8 +191,9 @@ func (h *Handler) UpdateLocation(c *gin.Context) {
 			return
 		}
 
-		if err.Error() == "pq: duplicate key value violates unique constraint \"locations_code_key\"" {
-			response.BadRequest(c, "code already exists, please use another code")
+		var conflictErr *conflict.Error
+		if errors.As(err, &conflictErr) {
+			response.Conflict(c, err.Error())
 			return
 		}
 
@@ -186,14 +221,210 @@ func (h *Handler) DeleteLocation(c *gin.Context) {
 	response.Success(c, nil, "Location deleted successfully")
 }
 
+// Export Locations as CSV, XLSX or NDJSON
+func (h *Handler) ExportLocations(c *gin.Context) {
+	exportHandler.HandleExport(c, "locations", locationModel.QuerySpec, locationModel.ExportColumns, h.locationService.Export)
+}
+
+// Import Locations from a CSV or XLSX file
+func (h *Handler) ImportLocations(c *gin.Context) {
+	h.importer.HandleImport(c, "location", h.locationService.Import)
+}
+
+// Get Location Subtree with the capacity rolled up from its bins
+func (h *Handler) GetLocationSubtree(c *gin.Context) {
+	idConvert, err := strconv.Atoi(c.Param("id"))
+	if err != nil || idConvert <= 0 {
+		response.BadRequest(c, "Invalid location ID")
+		return
+	}
+
+	subtree, err := h.locationService.GetSubtree(c.Request.Context(), idConvert)
+	if err != nil {
+		if err.Error() == "location not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, subtree, "Location subtree fetched successfully")
+}
+
+// Get Location Tree of a Warehouse
+func (h *Handler) GetWarehouseLocationTree(c *gin.Context) {
+	idConvert, err := strconv.Atoi(c.Param("id"))
+	if err != nil || idConvert <= 0 {
+		response.BadRequest(c, "Invalid warehouse ID")
+		return
+	}
+
+	tree, err := h.locationService.GetWarehouseTree(c.Request.Context(), idConvert)
+	if err != nil {
+		if err.Error() == "warehouse not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, tree, "Warehouse locations fetched successfully")
+}
+
+// Set the Status of a location, e.g. block it for damage or a count
+func (h *Handler) SetLocationStatus(c *gin.Context) {
+	idConvert, err := strconv.Atoi(c.Param("id"))
+	if err != nil || idConvert <= 0 {
+		response.BadRequest(c, "Invalid location ID")
+		return
+	}
+
+	var requestBody locationModel.StatusChange
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	location, err := h.locationService.SetStatus(c.Request.Context(), idConvert, &requestBody)
+	if err != nil {
+		if err.Error() == "location not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	response.Success(c, location, "Location status updated successfully")
+}
+
+// Preview Locations generated from a pattern, nothing is created
+func (h *Handler) PreviewGenerateLocations(c *gin.Context) {
+	h.generateLocations(c, true)
+}
+
+// Generate Locations from a pattern such as A-{01..20}-{L1..L5}-{B01..B10}
+func (h *Handler) GenerateLocations(c *gin.Context) {
+	h.generateLocations(c, false)
+}
+
+func (h *Handler) generateLocations(c *gin.Context, dryRun bool) {
+	var requestBody locationModel.GenerateRequest
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.locationService.Generate(c.Request.Context(), &requestBody, dryRun)
+	if err != nil {
+		if err.Error() == "pattern collides with existing location codes" {
+			response.BadRequest(c, "pattern collides with existing location codes, preview the pattern or set skip_existing")
+			return
+		}
+
+		if err.Error() == "parent location not found" || err.Error() == "warehouse not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if dryRun {
+		response.Success(c, result, "Location codes previewed successfully")
+		return
+	}
+
+	response.Created(c, result, "Locations generated successfully")
+}
+
+// Print Labels of a list of locations or a whole zone as ZPL or PDF
+func (h *Handler) PrintLocationLabels(c *gin.Context) {
+	var requestBody locationModel.LabelRequest
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	h.renderLabels(c, &requestBody)
+}
+
+// Print the Label of one location, ?format=zpl|pdf&template= pick the output
+func (h *Handler) PrintLocationLabel(c *gin.Context) {
+	idConvert, err := strconv.Atoi(c.Param("id"))
+	if err != nil || idConvert <= 0 {
+		response.BadRequest(c, "Invalid location ID")
+		return
+	}
+
+	h.renderLabels(c, &locationModel.LabelRequest{
+		LocationIDs: []int{idConvert},
+		Format:      label.Format(c.DefaultQuery("format", string(label.ZPL))),
+		Template:    c.Query("template"),
+	})
+}
+
+func (h *Handler) renderLabels(c *gin.Context, request *locationModel.LabelRequest) {
+	output, err := h.locationService.RenderLabels(c.Request.Context(), request)
+	if err != nil {
+		if err.Error() == "location not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=location-labels.%s", request.Format))
+	c.Data(http.StatusOK, request.Format.ContentType(), output)
+}
+
+// Get the predefined Label Templates
+func (h *Handler) GetLabelTemplates(c *gin.Context) {
+	response.Success(c, label.ListTemplates(), "Label templates fetched successfully")
+}
+
 // location routes
 func (h *Handler) RegisterLocationRoutes(router *gin.RouterGroup) {
 	locationRouter := router.Group("/location")
 	{
 		locationRouter.POST("", h.CreateLocation)
+		locationRouter.POST("/import", h.ImportLocations)
+		locationRouter.POST("/generate", h.GenerateLocations)
+		locationRouter.POST("/generate/preview", h.PreviewGenerateLocations)
+		locationRouter.POST("/labels", h.PrintLocationLabels)
 		locationRouter.GET("", h.GetLocations)
+		locationRouter.GET("/export", h.ExportLocations)
+		locationRouter.GET("/labels/templates", h.GetLabelTemplates)
 		locationRouter.GET("/:id", h.GetLocationByID)
+		locationRouter.GET("/:id/subtree", h.GetLocationSubtree)
+		locationRouter.GET("/:id/label", h.PrintLocationLabel)
 		locationRouter.PATCH("/:id", h.UpdateLocation)
+		locationRouter.PUT("/:id/status", h.SetLocationStatus)
 		locationRouter.DELETE("/:id", h.DeleteLocation)
 	}
+
+	router.GET("/warehouse/:id/locations", h.GetWarehouseLocationTree)
 }
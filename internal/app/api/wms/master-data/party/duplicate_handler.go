@@ -0,0 +1,147 @@
+package party
+
+import (
+	"strconv"
+
+	partyService "ecosystem.garyle/service/internal/app/service/wms/master-data/party"
+	partyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
+	"ecosystem.garyle/service/pkg/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// DuplicateHandler serves the duplicate report and merges of a party
+type DuplicateHandler struct {
+	duplicateService partyService.DuplicateService
+	entity           string // "supplier" or "customer"
+}
+
+// RegisterDuplicateRoutes adds the duplicate and merge routes under a party
+// route group, e.g. /supplier/duplicates
+func RegisterDuplicateRoutes(router *gin.RouterGroup, duplicateService partyService.DuplicateService, entity string) {
+	h := &DuplicateHandler{duplicateService: duplicateService, entity: entity}
+
+	router.GET("/duplicates", h.ListDuplicates)
+	router.GET("/:id/duplicates", h.FindDuplicates)
+	router.POST("/:id/merge", h.Merge)
+	router.GET("/:id/merges", h.ListMerges)
+}
+
+// ListDuplicates returns the pairs of parties that are likely the same
+func (h *DuplicateHandler) ListDuplicates(c *gin.Context) {
+	threshold, ok := parseThreshold(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 {
+		limit = 50
+	}
+
+	pairs, err := h.duplicateService.ListDuplicates(c.Request.Context(), threshold, limit)
+	if err != nil {
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, pairs, "Duplicate candidates retrieved successfully")
+}
+
+// FindDuplicates returns the likely duplicates of one party
+func (h *DuplicateHandler) FindDuplicates(c *gin.Context) {
+	id, ok := h.partyID(c)
+	if !ok {
+		return
+	}
+
+	threshold, ok := parseThreshold(c)
+	if !ok {
+		return
+	}
+
+	matches, err := h.duplicateService.FindDuplicates(c.Request.Context(), id, threshold)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.Success(c, matches, "Duplicate candidates retrieved successfully")
+}
+
+// Merge folds a duplicate into the party of the path, which survives
+func (h *DuplicateHandler) Merge(c *gin.Context) {
+	id, ok := h.partyID(c)
+	if !ok {
+		return
+	}
+
+	var request partyModel.MergeRequest
+	if !bind(c, &request) {
+		return
+	}
+
+	record, err := h.duplicateService.Merge(c.Request.Context(), id, &request)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.Success(c, record, "Merge completed successfully")
+}
+
+// ListMerges returns the merges into a party
+func (h *DuplicateHandler) ListMerges(c *gin.Context) {
+	id, ok := h.partyID(c)
+	if !ok {
+		return
+	}
+
+	records, err := h.duplicateService.ListMerges(c.Request.Context(), id)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.Success(c, records, "Merges retrieved successfully")
+}
+
+func (h *DuplicateHandler) partyID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid "+h.entity+" ID")
+		return 0, false
+	}
+
+	return id, true
+}
+
+func (h *DuplicateHandler) fail(c *gin.Context, err error) {
+	if err.Error() == h.entity+" not found" {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	for _, message := range partyService.MergeValidationErrors(h.entity) {
+		if err.Error() == message {
+			response.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	response.Server(c, err.Error())
+}
+
+func parseThreshold(c *gin.Context) (float64, bool) {
+	value := c.Query("threshold")
+	if value == "" {
+		return partyModel.DefaultDuplicateThreshold, true
+	}
+
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || threshold <= 0 || threshold > 1 {
+		response.BadRequest(c, "threshold must be between 0 and 1")
+		return 0, false
+	}
+
+	return threshold, true
+}
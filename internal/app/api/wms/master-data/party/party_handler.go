@@ -0,0 +1,238 @@
+package party
+
+import (
+	"strconv"
+
+	partyService "ecosystem.garyle/service/internal/app/service/wms/master-data/party"
+	partyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
+	"ecosystem.garyle/service/pkg/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// DetailHandler serves the addresses and contacts of a party
+type DetailHandler struct {
+	detailService partyService.DetailService
+	entity        string // "supplier" or "customer"
+}
+
+// RegisterDetailRoutes adds the address and contact routes under a party
+// route group, e.g. /supplier/:id/addresses
+func RegisterDetailRoutes(router *gin.RouterGroup, detailService partyService.DetailService, entity string) {
+	h := &DetailHandler{detailService: detailService, entity: entity}
+
+	router.GET("/:id/addresses", h.ListAddresses)
+	router.POST("/:id/addresses", h.CreateAddress)
+	router.PUT("/:id/addresses/:address_id", h.UpdateAddress)
+	router.DELETE("/:id/addresses/:address_id", h.DeleteAddress)
+	router.GET("/:id/contacts", h.ListContacts)
+	router.POST("/:id/contacts", h.CreateContact)
+	router.PUT("/:id/contacts/:contact_id", h.UpdateContact)
+	router.DELETE("/:id/contacts/:contact_id", h.DeleteContact)
+}
+
+// ListAddresses returns the addresses of a party
+func (h *DetailHandler) ListAddresses(c *gin.Context) {
+	ownerID, ok := h.ownerID(c)
+	if !ok {
+		return
+	}
+
+	addresses, err := h.detailService.ListAddresses(c.Request.Context(), ownerID)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.Success(c, addresses, "Addresses retrieved successfully")
+}
+
+// CreateAddress adds an address to a party
+func (h *DetailHandler) CreateAddress(c *gin.Context) {
+	ownerID, ok := h.ownerID(c)
+	if !ok {
+		return
+	}
+
+	var address partyModel.Address
+	if !bind(c, &address) {
+		return
+	}
+
+	createdAddress, err := h.detailService.CreateAddress(c.Request.Context(), ownerID, &address)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.Created(c, createdAddress, "Address created successfully")
+}
+
+// UpdateAddress replaces an address of a party
+func (h *DetailHandler) UpdateAddress(c *gin.Context) {
+	ownerID, ok := h.ownerID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("address_id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid address ID")
+		return
+	}
+
+	var address partyModel.Address
+	if !bind(c, &address) {
+		return
+	}
+
+	updatedAddress, err := h.detailService.UpdateAddress(c.Request.Context(), ownerID, id, &address)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.Success(c, updatedAddress, "Address updated successfully")
+}
+
+// DeleteAddress removes an address from a party
+func (h *DetailHandler) DeleteAddress(c *gin.Context) {
+	ownerID, ok := h.ownerID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("address_id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid address ID")
+		return
+	}
+
+	if err := h.detailService.DeleteAddress(c.Request.Context(), ownerID, id); err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.Success(c, nil, "Address deleted successfully")
+}
+
+// ListContacts returns the contact persons of a party
+func (h *DetailHandler) ListContacts(c *gin.Context) {
+	ownerID, ok := h.ownerID(c)
+	if !ok {
+		return
+	}
+
+	contacts, err := h.detailService.ListContacts(c.Request.Context(), ownerID)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.Success(c, contacts, "Contacts retrieved successfully")
+}
+
+// CreateContact adds a contact person to a party
+func (h *DetailHandler) CreateContact(c *gin.Context) {
+	ownerID, ok := h.ownerID(c)
+	if !ok {
+		return
+	}
+
+	var contact partyModel.Contact
+	if !bind(c, &contact) {
+		return
+	}
+
+	createdContact, err := h.detailService.CreateContact(c.Request.Context(), ownerID, &contact)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.Created(c, createdContact, "Contact created successfully")
+}
+
+// UpdateContact replaces a contact person of a party
+func (h *DetailHandler) UpdateContact(c *gin.Context) {
+	ownerID, ok := h.ownerID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("contact_id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid contact ID")
+		return
+	}
+
+	var contact partyModel.Contact
+	if !bind(c, &contact) {
+		return
+	}
+
+	updatedContact, err := h.detailService.UpdateContact(c.Request.Context(), ownerID, id, &contact)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.Success(c, updatedContact, "Contact updated successfully")
+}
+
+// DeleteContact removes a contact person from a party
+func (h *DetailHandler) DeleteContact(c *gin.Context) {
+	ownerID, ok := h.ownerID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("contact_id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid contact ID")
+		return
+	}
+
+	if err := h.detailService.DeleteContact(c.Request.Context(), ownerID, id); err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.Success(c, nil, "Contact deleted successfully")
+}
+
+func (h *DetailHandler) ownerID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid "+h.entity+" ID")
+		return 0, false
+	}
+
+	return id, true
+}
+
+func (h *DetailHandler) fail(c *gin.Context, err error) {
+	switch {
+	case err.Error() == h.entity+" not found", err.Error() == "address not found", err.Error() == "contact not found":
+		response.NotFound(c, err.Error())
+	case err.Error() == h.entity+" has been erased":
+		response.Conflict(c, err.Error())
+	case partyService.IsValidationError(err):
+		response.BadRequest(c, err.Error())
+	default:
+		response.Server(c, err.Error())
+	}
+}
+
+func bind(c *gin.Context, dest interface{}) bool {
+	if err := c.ShouldBindJSON(dest); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return false
+		}
+
+		response.BadRequest(c, err.Error())
+		return false
+	}
+
+	return true
+}
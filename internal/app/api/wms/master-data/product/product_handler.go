@@ -1,21 +1,29 @@
 package product
 
 import (
+	"errors"
 	"strconv"
 
+	exportHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/exporter"
+	importHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/importer"
 	productService "ecosystem.garyle/service/internal/app/service/wms/master-data/product"
+	categoryModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/category"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/conflict"
 	productModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/product"
+	"ecosystem.garyle/service/pkg/utils/filter"
 	"ecosystem.garyle/service/pkg/utils/response"
 	"github.com/gin-gonic/gin"
 )
 
 type Handler struct {
 	productService productService.ProductService
+	importer       *importHandler.Handler
 }
 
-func NewProductHandler(productService productService.ProductService) *Handler {
+func NewProductHandler(productService productService.ProductService, importer *importHandler.Handler) *Handler {
 	return &Handler{
 		productService: productService,
+		importer:       importer,
 	}
 }
 
@@ -38,8 +46,9 @@ func (h *Handler) CreateProduct(c *gin.Context) {
 			return
 		}
 
-		if err.Error() == "pq: duplicate key value violates unique constraint \"products_sku_key\"" {
-			response.BadRequest(c, "Product with this SKU already exists, please use another SKU")
+		var conflictErr *conflict.Error
+		if errors.As(err, &conflictErr) {
+			response.Conflict(c, err.Error())
 			return
 		}
 
@@ -57,6 +66,21 @@ func isValidationCreateOrUpdateProductError(err error) bool {
 		"unit is required",
 		"weight is required",
 		"dimension is required",
+		"parent product not found",
+		"parent product is a variant",
+		"min_temperature must not be above max_temperature",
+		"hazmat_class must be a UN hazard class such as 3 or 2.1",
+		"un_number must be UN followed by 4 digits",
+		"un_number is required for hazardous products",
+		"hazmat_class is required when un_number is set",
+		"attributes require a category",
+		"category not found",
+	}
+
+	// attribute errors name the attribute, e.g. "attribute voltage is required"
+	var attributeErr *categoryModel.AttributeError
+	if errors.As(err, &attributeErr) {
+		return true
 	}
 
 	for _, validationError := range validationErrors {
@@ -81,13 +105,32 @@ func (h *Handler) GetListProducts(c *gin.Context) {
 		page = 1
 	}
 
-	products, err := h.productService.List(c.Request.Context(), limit, page)
+	params, err := filter.Parse(c.Request.URL.Query(), productModel.QuerySpec)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	// group_variants lists parent products only, each with its variants
+	groupVariants, _ := strconv.ParseBool(c.DefaultQuery("group_variants", "false"))
+	if groupVariants {
+		params.Conditions = append(params.Conditions, filter.Condition{Column: "parent_id", Operator: filter.Null, Values: []interface{}{true}})
+	}
+
+	products, err := h.productService.List(c.Request.Context(), limit, page, params)
 	if err != nil {
 		response.Server(c, err.Error())
 		return
 	}
 
-	total, err := h.productService.Count(c.Request.Context())
+	if groupVariants {
+		if err := h.productService.AttachVariants(c.Request.Context(), products); err != nil {
+			response.Server(c, err.Error())
+			return
+		}
+	}
+
+	total, err := h.productService.Count(c.Request.Context(), params)
 	if err != nil {
 		response.Server(c, err.Error())
 		return
@@ -162,8 +205,9 @@ func (h *Handler) UpdateProductByID(c *gin.Context) {
 			return
 		}
 
-		if err.Error() == "pq: duplicate key value violates unique constraint \"products_sku_key\"" {
-			response.BadRequest(c, "Product with this SKU already exists, please use another SKU")
+		var conflictErr *conflict.Error
+		if errors.As(err, &conflictErr) {
+			response.Conflict(c, err.Error())
 			return
 		}
 
@@ -202,12 +246,124 @@ func (h *Handler) DeleteProductByID(c *gin.Context) {
 	response.Success(c, nil, "Product deleted successfully")
 }
 
+// Export Products as CSV, XLSX or NDJSON
+func (h *Handler) ExportProducts(c *gin.Context) {
+	exportHandler.HandleExport(c, "products", productModel.QuerySpec, productModel.ExportColumns, h.productService.Export)
+}
+
+// Import Products from a CSV or XLSX file
+func (h *Handler) ImportProducts(c *gin.Context) {
+	h.importer.HandleImport(c, "product", h.productService.Import)
+}
+
+// Get Variants of a Product
+func (h *Handler) GetProductVariants(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid product ID")
+		return
+	}
+
+	variants, err := h.productService.ListVariants(c.Request.Context(), productID)
+	if err != nil {
+		if err.Error() == "invalid product id" {
+			response.BadRequest(c, "Invalid product ID")
+			return
+		}
+
+		if err.Error() == "product not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, variants, "Product variants retrieved successfully")
+}
+
+type generateVariantsRequest struct {
+	Attributes productModel.VariantAttributes `json:"attributes"`
+}
+
+// Generate Variants of a Product from its attribute combinations
+func (h *Handler) GenerateProductVariants(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid product ID")
+		return
+	}
+
+	var request generateVariantsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	variants, err := h.productService.GenerateVariants(c.Request.Context(), productID, request.Attributes)
+	if err != nil {
+		if isValidationGenerateVariantsError(err) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+
+		if err.Error() == "product not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		var conflictErr *conflict.Error
+		if errors.As(err, &conflictErr) {
+			response.Conflict(c, "A generated variant SKU already exists, please check the existing products")
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, variants, "Product variants generated successfully")
+}
+
+func isValidationGenerateVariantsError(err error) bool {
+	validationErrors := []string{
+		"invalid product id",
+		"variants cannot have variants",
+		"at least one attribute is required",
+		"attribute name is required",
+		"attribute names must be unique",
+		"attribute values are required",
+		"attribute values must contain letters or digits",
+		"attribute values must be unique",
+		"too many variant combinations",
+		"attribute names cannot change once variants exist",
+	}
+
+	for _, validationError := range validationErrors {
+		if err.Error() == validationError {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (h *Handler) RegisterProductRoutes(router *gin.RouterGroup) {
 	productRoutes := router.Group("/product")
 	{
 		productRoutes.POST("", h.CreateProduct)
+		productRoutes.POST("/import", h.ImportProducts)
 		productRoutes.GET("", h.GetListProducts)
+		productRoutes.GET("/export", h.ExportProducts)
 		productRoutes.GET("/:id", h.GetProductByID)
+		productRoutes.GET("/:id/variants", h.GetProductVariants)
+		productRoutes.POST("/:id/variants", h.GenerateProductVariants)
 		productRoutes.PUT("/:id", h.UpdateProductByID)
 		productRoutes.DELETE("/:id", h.DeleteProductByID)
 	}
@@ -0,0 +1,49 @@
+package slotting
+
+import (
+	"strconv"
+
+	slottingService "ecosystem.garyle/service/internal/app/service/wms/master-data/slotting"
+	"ecosystem.garyle/service/pkg/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+type SlottingHandler struct {
+	slottingService slottingService.SlottingService
+}
+
+func NewSlottingHandler(slottingService slottingService.SlottingService) *SlottingHandler {
+	return &SlottingHandler{slottingService: slottingService}
+}
+
+// check whether a product may be stored in a location, ?product_id= names the product
+func (h *SlottingHandler) CheckCompatibility(c *gin.Context) {
+	locationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || locationID <= 0 {
+		response.BadRequest(c, "Invalid location ID")
+		return
+	}
+
+	productID, err := strconv.Atoi(c.Query("product_id"))
+	if err != nil || productID <= 0 {
+		response.BadRequest(c, "Invalid product ID")
+		return
+	}
+
+	compatibility, err := h.slottingService.CheckCompatibility(c.Request.Context(), productID, locationID)
+	if err != nil {
+		if err.Error() == "product not found" || err.Error() == "location not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, compatibility, "Compatibility checked successfully")
+}
+
+func (h *SlottingHandler) RegisterSlottingRoutes(router *gin.RouterGroup) {
+	router.GET("/location/:id/compatibility", h.CheckCompatibility)
+}
@@ -0,0 +1,248 @@
+package sourcing
+
+import (
+	"errors"
+	"strconv"
+
+	sourcingService "ecosystem.garyle/service/internal/app/service/wms/master-data/sourcing"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/conflict"
+	sourcingModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/sourcing"
+	"ecosystem.garyle/service/pkg/utils/filter"
+	"ecosystem.garyle/service/pkg/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+type SourcingHandler struct {
+	sourcingService sourcingService.SourcingService
+}
+
+func NewSourcingHandler(sourcingService sourcingService.SourcingService) *SourcingHandler {
+	return &SourcingHandler{sourcingService: sourcingService}
+}
+
+// create product supplier
+func (h *SourcingHandler) CreateProductSupplier(c *gin.Context) {
+	var productSupplier sourcingModel.ProductSupplier
+	if err := c.ShouldBindJSON(&productSupplier); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	createdProductSupplier, err := h.sourcingService.Create(c.Request.Context(), &productSupplier)
+	if err != nil {
+		if isValidationProductSupplierError(err) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+
+		if err.Error() == "product not found" || err.Error() == "supplier not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		var conflictErr *conflict.Error
+		if errors.As(err, &conflictErr) {
+			response.Conflict(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, createdProductSupplier, "Product supplier created successfully")
+}
+
+func isValidationProductSupplierError(err error) bool {
+	validationErrors := []string{
+		"product_id is required",
+		"supplier_id is required",
+		"unit_cost must not be negative",
+		"currency must be a 3-letter ISO 4217 code",
+		"min_order_qty must not be negative",
+		"lead_time_days must not be negative",
+	}
+
+	for _, validationError := range validationErrors {
+		if err.Error() == validationError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// get product supplier by id
+func (h *SourcingHandler) GetProductSupplierByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid product supplier ID")
+		return
+	}
+
+	productSupplier, err := h.sourcingService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		response.Server(c, err.Error())
+		return
+	}
+
+	if productSupplier == nil {
+		response.NotFound(c, "Product supplier not found")
+		return
+	}
+
+	response.Success(c, productSupplier, "Product supplier retrieved successfully")
+}
+
+// update product supplier by id
+func (h *SourcingHandler) UpdateProductSupplierByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid product supplier ID")
+		return
+	}
+
+	var productSupplier sourcingModel.ProductSupplier
+	if err := c.ShouldBindJSON(&productSupplier); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.sourcingService.UpdateByID(c.Request.Context(), &productSupplier, id); err != nil {
+		if isValidationProductSupplierError(err) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+
+		if err.Error() == "product supplier not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, productSupplier, "Product supplier updated successfully")
+}
+
+// delete product supplier by id
+func (h *SourcingHandler) DeleteProductSupplierByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid product supplier ID")
+		return
+	}
+
+	if err := h.sourcingService.DeleteByID(c.Request.Context(), id); err != nil {
+		if err.Error() == "product supplier not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil, "Product supplier deleted successfully")
+}
+
+// get the suppliers of a product, the preferred supplier comes first
+func (h *SourcingHandler) GetProductSources(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid product ID")
+		return
+	}
+
+	limit, page, params, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+
+	productSuppliers, total, err := h.sourcingService.ListProductSources(c.Request.Context(), productID, limit, page, params)
+	if err != nil {
+		if err.Error() == "product not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPagination(c, productSuppliers, "Product sources retrieved successfully", page, limit, total)
+}
+
+// get the catalog of a supplier
+func (h *SourcingHandler) GetSupplierCatalog(c *gin.Context) {
+	supplierID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid supplier ID")
+		return
+	}
+
+	limit, page, params, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+
+	productSuppliers, total, err := h.sourcingService.ListSupplierCatalog(c.Request.Context(), supplierID, limit, page, params)
+	if err != nil {
+		if err.Error() == "supplier not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPagination(c, productSuppliers, "Supplier catalog retrieved successfully", page, limit, total)
+}
+
+// parseListQuery reads limit, page and the list filters, it writes the
+// error response itself and returns false when the filters are invalid
+func parseListQuery(c *gin.Context) (int, int, *filter.Params, bool) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+
+	params, err := filter.Parse(c.Request.URL.Query(), sourcingModel.QuerySpec)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return 0, 0, nil, false
+	}
+
+	return limit, page, params, true
+}
+
+func (h *SourcingHandler) RegisterSourcingRoutes(router *gin.RouterGroup) {
+	sourcingRoutes := router.Group("/product-supplier")
+	{
+		sourcingRoutes.POST("", h.CreateProductSupplier)
+		sourcingRoutes.GET("/:id", h.GetProductSupplierByID)
+		sourcingRoutes.PUT("/:id", h.UpdateProductSupplierByID)
+		sourcingRoutes.DELETE("/:id", h.DeleteProductSupplierByID)
+	}
+
+	router.GET("/product/:id/suppliers", h.GetProductSources)
+	router.GET("/supplier/:id/products", h.GetSupplierCatalog)
+}
@@ -0,0 +1,205 @@
+package stock
+
+import (
+	"context"
+	"strconv"
+
+	stockService "ecosystem.garyle/service/internal/app/service/wms/master-data/stock"
+	stockModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/stock"
+	"ecosystem.garyle/service/pkg/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// maxRoomLimit caps the number of locations returned by the room query
+const maxRoomLimit = 100
+
+type StockHandler struct {
+	stockService stockService.StockService
+}
+
+func NewStockHandler(stockService stockService.StockService) *StockHandler {
+	return &StockHandler{stockService: stockService}
+}
+
+// get the stock stored in a location
+func (h *StockHandler) GetLocationStock(c *gin.Context) {
+	locationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || locationID <= 0 {
+		response.BadRequest(c, "Invalid location ID")
+		return
+	}
+
+	stocks, err := h.stockService.ListByLocation(c.Request.Context(), locationID)
+	if err != nil {
+		if err.Error() == "location not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, stocks, "Location stock retrieved successfully")
+}
+
+// put stock into a location
+func (h *StockHandler) PutStock(c *gin.Context) {
+	h.moveStock(c, h.stockService.Put, "Stock put into location successfully")
+}
+
+// take stock out of a location
+func (h *StockHandler) TakeStock(c *gin.Context) {
+	h.moveStock(c, h.stockService.Take, "Stock taken from location successfully")
+}
+
+func (h *StockHandler) moveStock(c *gin.Context, move func(ctx context.Context, locationID int, movement *stockModel.Movement) (*stockModel.Stock, error), message string) {
+	locationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || locationID <= 0 {
+		response.BadRequest(c, "Invalid location ID")
+		return
+	}
+
+	var movement stockModel.Movement
+	if err := c.ShouldBindJSON(&movement); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	stock, err := move(c.Request.Context(), locationID, &movement)
+	if err != nil {
+		if isValidationStockError(err) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+
+		if err.Error() == "location not found" || err.Error() == "product not found" || err.Error() == "batch not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, stock, message)
+}
+
+func isValidationStockError(err error) bool {
+	validationErrors := []string{
+		"product_id is required",
+		"quantity must be greater than 0",
+		"batch_id is required for a batch tracked product",
+		"product is not batch tracked",
+		"batch does not belong to the product",
+		"product is not compatible with the location",
+		"location does not have room for the quantity",
+		"not enough stock in the location",
+		"location is blocked for inbound",
+		"location is blocked for outbound",
+	}
+
+	for _, validationError := range validationErrors {
+		if err.Error() == validationError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// get the utilization of a location
+func (h *StockHandler) GetLocationUtilization(c *gin.Context) {
+	locationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || locationID <= 0 {
+		response.BadRequest(c, "Invalid location ID")
+		return
+	}
+
+	utilization, err := h.stockService.GetUtilization(c.Request.Context(), locationID)
+	if err != nil {
+		if err.Error() == "location not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, utilization, "Location utilization retrieved successfully")
+}
+
+// get the utilization of every location
+func (h *StockHandler) GetUtilization(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+
+	utilizations, total, err := h.stockService.ListUtilization(c.Request.Context(), limit, page)
+	if err != nil {
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPagination(c, utilizations, "Location utilization retrieved successfully", page, limit, total)
+}
+
+// get locations with room for a product, ?product_id=&quantity= name the put-away
+func (h *StockHandler) GetLocationsWithRoom(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Query("product_id"))
+	if err != nil || productID <= 0 {
+		response.BadRequest(c, "Invalid product ID")
+		return
+	}
+
+	quantity, err := strconv.ParseFloat(c.Query("quantity"), 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid quantity")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit <= 0 || limit > maxRoomLimit {
+		limit = 10
+	}
+
+	locations, err := h.stockService.FindLocationsWithRoom(c.Request.Context(), productID, quantity, limit)
+	if err != nil {
+		if err.Error() == "quantity must be greater than 0" {
+			response.BadRequest(c, err.Error())
+			return
+		}
+
+		if err.Error() == "product not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, locations, "Locations with room retrieved successfully")
+}
+
+func (h *StockHandler) RegisterStockRoutes(router *gin.RouterGroup) {
+	router.GET("/location/utilization", h.GetUtilization)
+	router.GET("/location/available", h.GetLocationsWithRoom)
+	router.GET("/location/:id/stock", h.GetLocationStock)
+	router.POST("/location/:id/stock/put", h.PutStock)
+	router.POST("/location/:id/stock/take", h.TakeStock)
+	router.GET("/location/:id/utilization", h.GetLocationUtilization)
+}
@@ -0,0 +1,160 @@
+package supplier
+
+import (
+	"strconv"
+	"time"
+
+	supplierService "ecosystem.garyle/service/internal/app/service/wms/master-data/supplier"
+	supplierModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/supplier"
+	"ecosystem.garyle/service/pkg/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// RecordReceipt records a received order line of the supplier
+func (h *SupplierHandler) RecordReceipt(c *gin.Context) {
+	supplierID, ok := parseSupplierID(c)
+	if !ok {
+		return
+	}
+
+	var receipt supplierModel.Receipt
+	if err := c.ShouldBindJSON(&receipt); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	created, err := h.supplierService.RecordReceipt(c.Request.Context(), supplierID, &receipt)
+	if err != nil {
+		scorecardError(c, err)
+		return
+	}
+
+	response.Created(c, created, "Receipt recorded successfully")
+}
+
+// ListReceipts returns the receipts of the supplier in a period, newest first
+func (h *SupplierHandler) ListReceipts(c *gin.Context) {
+	supplierID, ok := parseSupplierID(c)
+	if !ok {
+		return
+	}
+
+	period, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+
+	receipts, err := h.supplierService.ListReceipts(c.Request.Context(), supplierID, period, limit, page)
+	if err != nil {
+		scorecardError(c, err)
+		return
+	}
+
+	response.Success(c, receipts, "Receipts retrieved successfully")
+}
+
+// GetScorecard returns the scorecard of the supplier over a period
+func (h *SupplierHandler) GetScorecard(c *gin.Context) {
+	supplierID, ok := parseSupplierID(c)
+	if !ok {
+		return
+	}
+
+	period, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+
+	scorecard, err := h.supplierService.Scorecard(c.Request.Context(), supplierID, period)
+	if err != nil {
+		scorecardError(c, err)
+		return
+	}
+
+	response.Success(c, scorecard, "Scorecard retrieved successfully")
+}
+
+// RankScorecards returns the scorecards of all suppliers, best first
+func (h *SupplierHandler) RankScorecards(c *gin.Context) {
+	period, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 {
+		limit = 50
+	}
+
+	minReceipts, _ := strconv.Atoi(c.DefaultQuery("min_receipts", "1"))
+
+	scorecards, err := h.supplierService.RankScorecards(c.Request.Context(), supplierModel.RankingRequest{
+		Period:      period,
+		Sort:        c.Query("sort"),
+		MinReceipts: minReceipts,
+		Limit:       limit,
+	})
+	if err != nil {
+		scorecardError(c, err)
+		return
+	}
+
+	response.Success(c, scorecards, "Scorecards retrieved successfully")
+}
+
+func parseSupplierID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid supplier ID")
+		return 0, false
+	}
+
+	return id, true
+}
+
+func parsePeriod(c *gin.Context) (supplierModel.Period, bool) {
+	period, err := supplierModel.ParsePeriod(c.Query("from"), c.Query("to"), time.Now())
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return supplierModel.Period{}, false
+	}
+
+	return period, true
+}
+
+func scorecardError(c *gin.Context, err error) {
+	if err.Error() == "supplier not found" || err.Error() == "product not found" {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	if err.Error() == "sort must be one of score, on_time_rate, fill_rate, rejection_rate or lead_time" {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	for _, message := range supplierService.ReceiptValidationErrors() {
+		if err.Error() == message {
+			response.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	response.Server(c, err.Error())
+}
@@ -1,20 +1,28 @@
 package supplier
 
 import (
+	"errors"
 	"strconv"
 
+	exportHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/exporter"
+	importHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/importer"
+	partyHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/party"
+	partyService "ecosystem.garyle/service/internal/app/service/wms/master-data/party"
 	supplierService "ecosystem.garyle/service/internal/app/service/wms/master-data/supplier"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/conflict"
 	supplierModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/supplier"
+	"ecosystem.garyle/service/pkg/utils/filter"
 	"ecosystem.garyle/service/pkg/utils/response"
 	"github.com/gin-gonic/gin"
 )
 
 type SupplierHandler struct {
 	supplierService supplierService.SupplierService
+	importer        *importHandler.Handler
 }
 
-func NewSupplierHandler(supplierService supplierService.SupplierService) *SupplierHandler {
-	return &SupplierHandler{supplierService: supplierService}
+func NewSupplierHandler(supplierService supplierService.SupplierService, importer *importHandler.Handler) *SupplierHandler {
+	return &SupplierHandler{supplierService: supplierService, importer: importer}
 }
 
 // create supplier
@@ -40,6 +48,12 @@ func (h *SupplierHandler) CreateSupplier(c *gin.Context) {
 			return
 		}
 
+		var conflictErr *conflict.Error
+		if errors.As(err, &conflictErr) {
+			response.Conflict(c, err.Error())
+			return
+		}
+
 		response.Server(c, err.Error())
 		return
 	}
@@ -61,13 +75,19 @@ func (h *SupplierHandler) GetListSupplier(c *gin.Context) {
 		page = 1
 	}
 
-	suppliers, err := h.supplierService.List(c.Request.Context(), limit, page)
+	params, err := filter.Parse(c.Request.URL.Query(), supplierModel.QuerySpec)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	suppliers, err := h.supplierService.List(c.Request.Context(), limit, page, params)
 	if err != nil {
 		response.Server(c, err.Error())
 		return
 	}
 
-	total, err := h.supplierService.Count(c.Request.Context())
+	total, err := h.supplierService.Count(c.Request.Context(), params)
 	if err != nil {
 		response.Server(c, err.Error())
 		return
@@ -136,6 +156,12 @@ func (h *SupplierHandler) UpdateSupplierByID(c *gin.Context) {
 			return
 		}
 
+		var conflictErr *conflict.Error
+		if errors.As(err, &conflictErr) {
+			response.Conflict(c, err.Error())
+			return
+		}
+
 		response.Server(c, err.Error())
 		return
 	}
@@ -172,6 +198,7 @@ func validateCreateOrUpdateSupplierError(err error) bool {
 		"name is required",
 		"address is required",
 		"contact is required",
+		partyService.TaxIDTakenError("supplier"),
 	}
 
 	for _, validationError := range validationErrors {
@@ -180,16 +207,35 @@ func validateCreateOrUpdateSupplierError(err error) bool {
 		}
 	}
 
-	return false
+	return partyService.IsValidationError(err)
+}
+
+// Export Suppliers as CSV, XLSX or NDJSON
+func (h *SupplierHandler) ExportSuppliers(c *gin.Context) {
+	exportHandler.HandleExport(c, "suppliers", supplierModel.QuerySpec, supplierModel.ExportColumns, h.supplierService.Export)
+}
+
+// Import Suppliers from a CSV or XLSX file
+func (h *SupplierHandler) ImportSuppliers(c *gin.Context) {
+	h.importer.HandleImport(c, "supplier", h.supplierService.Import)
 }
 
 func (h *SupplierHandler) RegisterSupplierRoutes(router *gin.RouterGroup) {
 	supplierRoutes := router.Group("/supplier")
 	{
 		supplierRoutes.POST("", h.CreateSupplier)
+		supplierRoutes.POST("/import", h.ImportSuppliers)
 		supplierRoutes.GET("", h.GetListSupplier)
+		supplierRoutes.GET("/export", h.ExportSuppliers)
+		supplierRoutes.GET("/scorecards", h.RankScorecards)
 		supplierRoutes.GET("/:id", h.GetSupplierByID)
 		supplierRoutes.PUT("/:id", h.UpdateSupplierByID)
 		supplierRoutes.DELETE("/:id", h.DeleteSupplierByID)
+		supplierRoutes.POST("/:id/receipts", h.RecordReceipt)
+		supplierRoutes.GET("/:id/receipts", h.ListReceipts)
+		supplierRoutes.GET("/:id/scorecard", h.GetScorecard)
 	}
+
+	partyHandler.RegisterDetailRoutes(supplierRoutes, h.supplierService, "supplier")
+	partyHandler.RegisterDuplicateRoutes(supplierRoutes, h.supplierService, "supplier")
 }
@@ -0,0 +1,150 @@
+package trash
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	trashService "ecosystem.garyle/service/internal/app/service/wms/master-data/trash"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/conflict"
+	trashModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/trash"
+	"ecosystem.garyle/service/pkg/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	trashService trashService.TrashService
+}
+
+func NewTrashHandler(trashService trashService.TrashService) *TrashHandler {
+	return &TrashHandler{trashService: trashService}
+}
+
+// ListEntities returns the entities that have a trash
+func (h *TrashHandler) ListEntities(c *gin.Context) {
+	response.Success(c, trashModel.EntityNames(), "Trash entities retrieved successfully")
+}
+
+// ListTrash returns the deleted rows of an entity, most recently deleted first
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+
+	items, total, err := h.trashService.List(c.Request.Context(), c.Param("entity"), limit, page)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.SuccessWithPagination(c, items, "Trash retrieved successfully", page, limit, total)
+}
+
+// Restore undeletes a row of an entity
+func (h *TrashHandler) Restore(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid ID")
+		return
+	}
+
+	item, err := h.trashService.Restore(c.Request.Context(), c.Param("entity"), id)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.Success(c, item, "Restored successfully")
+}
+
+// Purge permanently removes a deleted row of an entity
+func (h *TrashHandler) Purge(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid ID")
+		return
+	}
+
+	if err := h.trashService.Purge(c.Request.Context(), c.Param("entity"), id); err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.Success(c, nil, "Purged successfully")
+}
+
+// PurgeExpired runs the retention purge on demand
+func (h *TrashHandler) PurgeExpired(c *gin.Context) {
+	var request trashModel.PurgeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if request.OlderThanDays == nil || *request.OlderThanDays < 0 {
+		response.BadRequest(c, "older_than_days must be 0 or more")
+		return
+	}
+
+	deletedBefore := time.Now().AddDate(0, 0, -*request.OlderThanDays)
+	report, err := h.trashService.PurgeExpired(c.Request.Context(), request.Entity, deletedBefore)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	response.Success(c, report, "Purge completed successfully")
+}
+
+func (h *TrashHandler) fail(c *gin.Context, err error) {
+	var conflictErr *conflict.Error
+	var parentDeleted *trashModel.ParentDeletedError
+	var referenced *trashModel.ReferencedError
+	var retained *trashModel.RetainedError
+
+	switch {
+	case err.Error() == trashService.UnknownEntityError():
+		response.BadRequest(c, err.Error())
+	case errors.As(err, &conflictErr), errors.As(err, &parentDeleted), errors.As(err, &referenced), errors.As(err, &retained):
+		response.Conflict(c, err.Error())
+	case isNotInTrash(err):
+		response.NotFound(c, err.Error())
+	default:
+		response.Server(c, err.Error())
+	}
+}
+
+func isNotInTrash(err error) bool {
+	for _, entity := range trashModel.Entities {
+		if err.Error() == trashService.NotInTrashError(entity) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RegisterTrashRoutes registers the trash routes. Purging cannot be undone
+// and is meant for administrators.
+func (h *TrashHandler) RegisterTrashRoutes(router *gin.RouterGroup) {
+	trashRoutes := router.Group("/trash")
+	{
+		trashRoutes.GET("", h.ListEntities)
+		trashRoutes.POST("/purge", h.PurgeExpired)
+		trashRoutes.GET("/:entity", h.ListTrash)
+		trashRoutes.POST("/:entity/:id/restore", h.Restore)
+		trashRoutes.DELETE("/:entity/:id", h.Purge)
+	}
+}
@@ -0,0 +1,211 @@
+package warehouse
+
+import (
+	"errors"
+	"strconv"
+
+	warehouseService "ecosystem.garyle/service/internal/app/service/wms/master-data/warehouse"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/conflict"
+	warehouseModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/warehouse"
+	"ecosystem.garyle/service/pkg/utils/filter"
+	"ecosystem.garyle/service/pkg/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+type WarehouseHandler struct {
+	warehouseService warehouseService.WarehouseService
+}
+
+func NewWarehouseHandler(warehouseService warehouseService.WarehouseService) *WarehouseHandler {
+	return &WarehouseHandler{warehouseService: warehouseService}
+}
+
+// create warehouse
+func (h *WarehouseHandler) CreateWarehouse(c *gin.Context) {
+	var warehouse warehouseModel.Warehouse
+	if err := c.ShouldBindJSON(&warehouse); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	createdWarehouse, err := h.warehouseService.Create(c.Request.Context(), &warehouse)
+	if err != nil {
+		if isValidationWarehouseError(err) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+
+		var conflictErr *conflict.Error
+		if errors.As(err, &conflictErr) {
+			response.Conflict(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, createdWarehouse, "Warehouse created successfully")
+}
+
+func isValidationWarehouseError(err error) bool {
+	validationErrors := []string{
+		"code is required",
+		"code must not contain '-'",
+		"name is required",
+	}
+
+	for _, validationError := range validationErrors {
+		if err.Error() == validationError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// get list warehouses
+func (h *WarehouseHandler) GetListWarehouses(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+
+	params, err := filter.Parse(c.Request.URL.Query(), warehouseModel.QuerySpec)
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	warehouses, err := h.warehouseService.List(c.Request.Context(), limit, page, params)
+	if err != nil {
+		response.Server(c, err.Error())
+		return
+	}
+
+	total, err := h.warehouseService.Count(c.Request.Context(), params)
+	if err != nil {
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.SuccessWithPagination(c, warehouses, "Warehouses retrieved successfully", page, limit, total)
+}
+
+// get warehouse by id
+func (h *WarehouseHandler) GetWarehouseByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid warehouse ID")
+		return
+	}
+
+	warehouse, err := h.warehouseService.GetByID(c.Request.Context(), id)
+	if err != nil {
+		response.Server(c, err.Error())
+		return
+	}
+
+	if warehouse == nil {
+		response.NotFound(c, "Warehouse not found")
+		return
+	}
+
+	response.Success(c, warehouse, "Warehouse retrieved successfully")
+}
+
+// update warehouse by id
+func (h *WarehouseHandler) UpdateWarehouseByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid warehouse ID")
+		return
+	}
+
+	var warehouse warehouseModel.Warehouse
+	if err := c.ShouldBindJSON(&warehouse); err != nil {
+		if err.Error() == "EOF" {
+			response.BadRequest(c, "Missing request body. Please provide a valid JSON payload.")
+			return
+		}
+
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.warehouseService.UpdateByID(c.Request.Context(), &warehouse, id); err != nil {
+		if isValidationWarehouseError(err) {
+			response.BadRequest(c, err.Error())
+			return
+		}
+
+		if err.Error() == "warehouse not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		var conflictErr *conflict.Error
+		if errors.As(err, &conflictErr) {
+			if conflictErr.Entity == "location" {
+				response.Conflict(c, "Renaming the warehouse would duplicate existing location codes")
+				return
+			}
+
+			response.Conflict(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, warehouse, "Warehouse updated successfully")
+}
+
+// delete warehouse by id
+func (h *WarehouseHandler) DeleteWarehouseByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid warehouse ID")
+		return
+	}
+
+	if err := h.warehouseService.DeleteByID(c.Request.Context(), id); err != nil {
+		if err.Error() == "warehouse not found" {
+			response.NotFound(c, err.Error())
+			return
+		}
+
+		if err.Error() == "warehouse still has locations" {
+			response.BadRequest(c, err.Error())
+			return
+		}
+
+		response.Server(c, err.Error())
+		return
+	}
+
+	response.Success(c, nil, "Warehouse deleted successfully")
+}
+
+func (h *WarehouseHandler) RegisterWarehouseRoutes(router *gin.RouterGroup) {
+	warehouseRoutes := router.Group("/warehouse")
+	{
+		warehouseRoutes.POST("", h.CreateWarehouse)
+		warehouseRoutes.GET("", h.GetListWarehouses)
+		warehouseRoutes.GET("/:id", h.GetWarehouseByID)
+		warehouseRoutes.PUT("/:id", h.UpdateWarehouseByID)
+		warehouseRoutes.DELETE("/:id", h.DeleteWarehouseByID)
+	}
+}
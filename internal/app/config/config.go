@@ -9,8 +9,10 @@ import (
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	Trash      TrashConfig
+	Encryption EncryptionConfig
 }
 
 // ServerConfig holds server-related configuration
@@ -32,6 +34,19 @@ type DatabaseConfig struct {
 	ConnMaxLifetime time.Duration
 }
 
+// TrashConfig holds the retention of soft-deleted master data
+type TrashConfig struct {
+	RetentionDays int           // rows deleted longer ago are purged, 0 turns the schedule off
+	PurgeInterval time.Duration // how often the retention purge runs
+	PurgeOnStart  bool          // whether the first purge runs at start instead of after one interval
+}
+
+// EncryptionConfig holds the keys of the personal data encrypted at rest
+type EncryptionConfig struct {
+	CustomerKeys      string // key versions, "1:<base64 key>,2:<base64 key>", empty stores plain text
+	CustomerActiveKey int    // the version new values are encrypted with, 0 for the highest
+}
+
 // NewConfig creates a new Config with values from environment variables
 func NewConfig() *Config {
 	return &Config{
@@ -50,6 +65,15 @@ func NewConfig() *Config {
 			MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 25),
 			ConnMaxLifetime: time.Duration(getEnvAsInt("DB_CONN_MAX_LIFETIME", 5)) * time.Minute,
 		},
+		Trash: TrashConfig{
+			RetentionDays: getEnvAsInt("TRASH_RETENTION_DAYS", 0),
+			PurgeInterval: time.Duration(getEnvAsInt("TRASH_PURGE_INTERVAL_HOURS", 24)) * time.Hour,
+			PurgeOnStart:  getEnvAsBool("TRASH_PURGE_ON_START", false),
+		},
+		Encryption: EncryptionConfig{
+			CustomerKeys:      getEnv("CUSTOMER_PII_KEYS", ""),
+			CustomerActiveKey: getEnvAsInt("CUSTOMER_PII_ACTIVE_KEY", 0),
+		},
 	}
 }
 
@@ -76,3 +100,13 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// Helper function to get an environment variable as boolean with a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if valueStr, exists := os.LookupEnv(key); exists {
+		if value, err := strconv.ParseBool(valueStr); err == nil {
+			return value
+		}
+	}
+	return defaultValue
+}
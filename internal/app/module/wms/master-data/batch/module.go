@@ -0,0 +1,30 @@
+package batch
+
+import (
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+
+	batchHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/batch"
+	batchService "ecosystem.garyle/service/internal/app/service/wms/master-data/batch"
+	batchRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/batch"
+	productRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/product"
+)
+
+var Module = fx.Module("batch",
+	fx.Provide(
+		batchRepoPostgres.NewBatchRepository,
+		batchService.NewBatchService,
+		batchHandler.NewBatchHandler,
+	),
+)
+
+func RegisterBatchHandler(db *sql.DB, router *gin.RouterGroup) {
+	repo := batchRepoPostgres.NewBatchRepository(db)
+	productRepo := productRepoPostgres.NewProductRepository(db)
+	service := batchService.NewBatchService(repo, productRepo)
+	handler := batchHandler.NewBatchHandler(service)
+
+	handler.RegisterBatchRoutes(router)
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 
 	categoryHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/category"
+	importHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/importer"
 	categoryService "ecosystem.garyle/service/internal/app/service/wms/master-data/category"
 	categoryRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/category"
 	"github.com/gin-gonic/gin"
@@ -18,10 +19,10 @@ var Module = fx.Module("category",
 	),
 )
 
-func RegisterCategoryHandler(db *sql.DB, router *gin.RouterGroup) {
+func RegisterCategoryHandler(db *sql.DB, importer *importHandler.Handler, router *gin.RouterGroup) {
 	repo := categoryRepoPostgres.NewCategoryRepository(db)
 	service := categoryService.NewCategoryService(repo)
-	handler := categoryHandler.NewCategoryHandler(service)
+	handler := categoryHandler.NewCategoryHandler(service, importer)
 
 	handler.RegisterCategoryRoutes(router)
 }
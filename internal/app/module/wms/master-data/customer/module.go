@@ -4,24 +4,51 @@ import (
 	"database/sql"
 
 	customerHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/customer"
+	importHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/importer"
+	"ecosystem.garyle/service/internal/app/config"
 	customerService "ecosystem.garyle/service/internal/app/service/wms/master-data/customer"
 	customerRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/customer"
+	"ecosystem.garyle/service/pkg/logger"
+	"ecosystem.garyle/service/pkg/utils/fieldcrypt"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/fx"
 )
 
 var Module = fx.Module("customer",
 	fx.Provide(
+		NewCustomerKeyring,
 		customerRepoPostgres.NewCustomerRepository,
 		customerService.NewCustomerService,
 		customerHandler.NewCustomerHandler,
 	),
+	fx.Invoke(warnPlainPersonalData),
 )
 
-func RegisterCustomerHandler(db *sql.DB, router *gin.RouterGroup) {
-	repo := customerRepoPostgres.NewCustomerRepository(db)
+func RegisterCustomerHandler(db *sql.DB, importer *importHandler.Handler, keyring *fieldcrypt.Keyring, router *gin.RouterGroup) {
+	repo := customerRepoPostgres.NewCustomerRepository(db, keyring)
 	service := customerService.NewCustomerService(repo)
-	handler := customerHandler.NewCustomerHandler(service)
+	handler := customerHandler.NewCustomerHandler(service, importer)
 
 	handler.RegisterCustomerRoutes(router)
 }
+
+// NewCustomerKeyring creates the keyring of the customer personal data from
+// the configured keys, nil when no keys are configured
+func NewCustomerKeyring(cfg *config.Config) (*fieldcrypt.Keyring, error) {
+	if cfg.Encryption.CustomerKeys == "" {
+		return nil, nil
+	}
+
+	keys, err := fieldcrypt.ParseKeys(cfg.Encryption.CustomerKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return fieldcrypt.New(keys, cfg.Encryption.CustomerActiveKey)
+}
+
+func warnPlainPersonalData(keyring *fieldcrypt.Keyring, log logger.Logger) {
+	if !keyring.Enabled() {
+		log.Warn("CUSTOMER_PII_KEYS is not set, customer personal data is stored in plain text")
+	}
+}
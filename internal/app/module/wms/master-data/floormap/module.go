@@ -0,0 +1,32 @@
+package floormap
+
+import (
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+
+	floorMapHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/floormap"
+	floorMapService "ecosystem.garyle/service/internal/app/service/wms/master-data/floormap"
+	floorMapRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/floormap"
+	locationRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/location"
+	warehouseRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/warehouse"
+)
+
+var Module = fx.Module("floormap",
+	fx.Provide(
+		floorMapRepoPostgres.NewFloorMapRepository,
+		floorMapService.NewFloorMapService,
+		floorMapHandler.NewFloorMapHandler,
+	),
+)
+
+func RegisterFloorMapHandler(db *sql.DB, router *gin.RouterGroup) {
+	repo := floorMapRepoPostgres.NewFloorMapRepository(db)
+	warehouseRepo := warehouseRepoPostgres.NewWarehouseRepository(db)
+	locationRepo := locationRepoPostgres.NewLocationRepository(db)
+	service := floorMapService.NewFloorMapService(repo, warehouseRepo, locationRepo)
+	handler := floorMapHandler.NewFloorMapHandler(service)
+
+	handler.RegisterFloorMapRoutes(router)
+}
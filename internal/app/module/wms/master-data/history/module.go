@@ -0,0 +1,28 @@
+package history
+
+import (
+	"database/sql"
+
+	historyHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/history"
+	historyService "ecosystem.garyle/service/internal/app/service/wms/master-data/history"
+	historyRepo "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/history"
+	"ecosystem.garyle/service/pkg/utils/fieldcrypt"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module("history",
+	fx.Provide(
+		historyRepo.NewHistoryRepository,
+		historyService.NewHistoryService,
+		historyHandler.NewHistoryHandler,
+	),
+)
+
+func RegisterHistoryHandler(db *sql.DB, keyring *fieldcrypt.Keyring, router *gin.RouterGroup) {
+	repo := historyRepo.NewHistoryRepository(db, keyring)
+	service := historyService.NewHistoryService(repo)
+	handler := historyHandler.NewHistoryHandler(service)
+
+	handler.RegisterHistoryRoutes(router)
+}
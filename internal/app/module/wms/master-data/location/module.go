@@ -3,9 +3,11 @@ package location
 import (
 	"database/sql"
 
+	importHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/importer"
 	locationHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/location"
 	locationService "ecosystem.garyle/service/internal/app/service/wms/master-data/location"
 	locationRepo "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/location"
+	warehouseRepo "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/warehouse"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/fx"
 )
@@ -18,10 +20,10 @@ var Module = fx.Module("location",
 	),
 )
 
-func RegisterLocationHandler(db *sql.DB, router *gin.RouterGroup) {
+func RegisterLocationHandler(db *sql.DB, importer *importHandler.Handler, router *gin.RouterGroup) {
 	repo := locationRepo.NewLocationRepository(db)
-	service := locationService.NewLocationService(repo)
-	handler := locationHandler.NewLocationHandler(service)
+	service := locationService.NewLocationService(repo, warehouseRepo.NewWarehouseRepository(db))
+	handler := locationHandler.NewLocationHandler(service, importer)
 
 	handler.RegisterLocationRoutes(router)
 }
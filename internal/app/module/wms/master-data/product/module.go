@@ -6,8 +6,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/fx"
 
+	importHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/importer"
 	productHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/product"
 	productService "ecosystem.garyle/service/internal/app/service/wms/master-data/product"
+	categoryRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/category"
 	productRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/product"
 )
 
@@ -20,10 +22,10 @@ var Module = fx.Module("product",
 )
 
 // RegisterProductHandler registers product routes with the router group
-func RegisterProductHandler(db *sql.DB, router *gin.RouterGroup) {
+func RegisterProductHandler(db *sql.DB, importer *importHandler.Handler, router *gin.RouterGroup) {
 	repo := productRepoPostgres.NewProductRepository(db)
-	service := productService.NewProductService(repo)
-	handler := productHandler.NewProductHandler(service)
+	service := productService.NewProductService(repo, categoryRepoPostgres.NewCategoryRepository(db))
+	handler := productHandler.NewProductHandler(service, importer)
 
 	handler.RegisterProductRoutes(router)
 }
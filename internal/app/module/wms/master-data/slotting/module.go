@@ -0,0 +1,29 @@
+package slotting
+
+import (
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+
+	slottingHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/slotting"
+	slottingService "ecosystem.garyle/service/internal/app/service/wms/master-data/slotting"
+	locationRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/location"
+	productRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/product"
+)
+
+var Module = fx.Module("slotting",
+	fx.Provide(
+		slottingService.NewSlottingService,
+		slottingHandler.NewSlottingHandler,
+	),
+)
+
+func RegisterSlottingHandler(db *sql.DB, router *gin.RouterGroup) {
+	productRepo := productRepoPostgres.NewProductRepository(db)
+	locationRepo := locationRepoPostgres.NewLocationRepository(db)
+	service := slottingService.NewSlottingService(productRepo, locationRepo)
+	handler := slottingHandler.NewSlottingHandler(service)
+
+	handler.RegisterSlottingRoutes(router)
+}
@@ -0,0 +1,32 @@
+package sourcing
+
+import (
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+
+	sourcingHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/sourcing"
+	sourcingService "ecosystem.garyle/service/internal/app/service/wms/master-data/sourcing"
+	productRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/product"
+	sourcingRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/sourcing"
+	supplierRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/supplier"
+)
+
+var Module = fx.Module("sourcing",
+	fx.Provide(
+		sourcingRepoPostgres.NewSourcingRepository,
+		sourcingService.NewSourcingService,
+		sourcingHandler.NewSourcingHandler,
+	),
+)
+
+func RegisterSourcingHandler(db *sql.DB, router *gin.RouterGroup) {
+	repo := sourcingRepoPostgres.NewSourcingRepository(db)
+	productRepo := productRepoPostgres.NewProductRepository(db)
+	supplierRepo := supplierRepoPostgres.NewSupplierRepository(db)
+	service := sourcingService.NewSourcingService(repo, productRepo, supplierRepo)
+	handler := sourcingHandler.NewSourcingHandler(service)
+
+	handler.RegisterSourcingRoutes(router)
+}
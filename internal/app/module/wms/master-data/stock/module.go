@@ -0,0 +1,34 @@
+package stock
+
+import (
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+
+	stockHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/stock"
+	stockService "ecosystem.garyle/service/internal/app/service/wms/master-data/stock"
+	batchRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/batch"
+	locationRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/location"
+	productRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/product"
+	stockRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/stock"
+)
+
+var Module = fx.Module("stock",
+	fx.Provide(
+		stockRepoPostgres.NewStockRepository,
+		stockService.NewStockService,
+		stockHandler.NewStockHandler,
+	),
+)
+
+func RegisterStockHandler(db *sql.DB, router *gin.RouterGroup) {
+	repo := stockRepoPostgres.NewStockRepository(db)
+	locationRepo := locationRepoPostgres.NewLocationRepository(db)
+	productRepo := productRepoPostgres.NewProductRepository(db)
+	batchRepo := batchRepoPostgres.NewBatchRepository(db)
+	service := stockService.NewStockService(repo, locationRepo, productRepo, batchRepo)
+	handler := stockHandler.NewStockHandler(service)
+
+	handler.RegisterStockRoutes(router)
+}
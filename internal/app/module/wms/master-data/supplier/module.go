@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/fx"
 
+	importHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/importer"
 	supplierHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/supplier"
 	supplierService "ecosystem.garyle/service/internal/app/service/wms/master-data/supplier"
 	supplierRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/supplier"
@@ -19,10 +20,10 @@ var Module = fx.Module("supplier",
 	),
 )
 
-func RegisterSupplierHandler(db *sql.DB, router *gin.RouterGroup) {
+func RegisterSupplierHandler(db *sql.DB, importer *importHandler.Handler, router *gin.RouterGroup) {
 	repo := supplierRepoPostgres.NewSupplierRepository(db)
 	service := supplierService.NewSupplierService(repo)
-	handler := supplierHandler.NewSupplierHandler(service)
+	handler := supplierHandler.NewSupplierHandler(service, importer)
 
 	handler.RegisterSupplierRoutes(router)
 }
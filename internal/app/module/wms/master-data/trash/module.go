@@ -0,0 +1,91 @@
+package trash
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	trashHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/trash"
+	"ecosystem.garyle/service/internal/app/config"
+	trashService "ecosystem.garyle/service/internal/app/service/wms/master-data/trash"
+	trashRepo "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/trash"
+	"ecosystem.garyle/service/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module("trash",
+	fx.Provide(
+		trashService.NewTrashService,
+		trashRepo.NewTrashRepository,
+		trashHandler.NewTrashHandler,
+	),
+	fx.Invoke(RegisterPurgeSchedule),
+)
+
+func RegisterTrashHandler(db *sql.DB, router *gin.RouterGroup) {
+	repo := trashRepo.NewTrashRepository(db)
+	service := trashService.NewTrashService(repo)
+	handler := trashHandler.NewTrashHandler(service)
+
+	handler.RegisterTrashRoutes(router)
+}
+
+// RegisterPurgeSchedule purges the rows deleted longer ago than the configured
+// retention every purge interval. It is off unless a retention is configured,
+// and the first purge only runs at start when TRASH_PURGE_ON_START is set.
+func RegisterPurgeSchedule(lc fx.Lifecycle, cfg *config.Config, service trashService.TrashService, log logger.Logger) {
+	if cfg.Trash.RetentionDays <= 0 || cfg.Trash.PurgeInterval <= 0 {
+		log.Info("Trash retention purge is disabled")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	purge := func() {
+		deletedBefore := time.Now().AddDate(0, 0, -cfg.Trash.RetentionDays)
+		report, err := service.PurgeExpired(ctx, "", deletedBefore)
+		if err != nil {
+			log.Errorf("Trash retention purge failed: %v", err)
+			return
+		}
+
+		log.Infof("Trash retention purge removed %v and skipped %d referenced rows", report.Purged, len(report.Skipped))
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+
+				ticker := time.NewTicker(cfg.Trash.PurgeInterval)
+				defer ticker.Stop()
+
+				if cfg.Trash.PurgeOnStart {
+					purge()
+				}
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						purge()
+					}
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+
+			return nil
+		},
+	})
+}
@@ -0,0 +1,28 @@
+package warehouse
+
+import (
+	"database/sql"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+
+	warehouseHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/warehouse"
+	warehouseService "ecosystem.garyle/service/internal/app/service/wms/master-data/warehouse"
+	warehouseRepoPostgres "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/warehouse"
+)
+
+var Module = fx.Module("warehouse",
+	fx.Provide(
+		warehouseRepoPostgres.NewWarehouseRepository,
+		warehouseService.NewWarehouseService,
+		warehouseHandler.NewWarehouseHandler,
+	),
+)
+
+func RegisterWarehouseHandler(db *sql.DB, router *gin.RouterGroup) {
+	repo := warehouseRepoPostgres.NewWarehouseRepository(db)
+	service := warehouseService.NewWarehouseService(repo)
+	handler := warehouseHandler.NewWarehouseHandler(service)
+
+	handler.RegisterWarehouseRoutes(router)
+}
@@ -6,27 +6,69 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/fx"
 
+	importHandler "ecosystem.garyle/service/internal/app/api/wms/master-data/importer"
+	"ecosystem.garyle/service/internal/app/config"
+	batchModule "ecosystem.garyle/service/internal/app/module/wms/master-data/batch"
 	categoryModule "ecosystem.garyle/service/internal/app/module/wms/master-data/category"
 	customerModule "ecosystem.garyle/service/internal/app/module/wms/master-data/customer"
+	floorMapModule "ecosystem.garyle/service/internal/app/module/wms/master-data/floormap"
+	historyModule "ecosystem.garyle/service/internal/app/module/wms/master-data/history"
 	locationModule "ecosystem.garyle/service/internal/app/module/wms/master-data/location"
 	productModule "ecosystem.garyle/service/internal/app/module/wms/master-data/product"
+	slottingModule "ecosystem.garyle/service/internal/app/module/wms/master-data/slotting"
+	sourcingModule "ecosystem.garyle/service/internal/app/module/wms/master-data/sourcing"
+	stockModule "ecosystem.garyle/service/internal/app/module/wms/master-data/stock"
 	supplierModule "ecosystem.garyle/service/internal/app/module/wms/master-data/supplier"
+	trashModule "ecosystem.garyle/service/internal/app/module/wms/master-data/trash"
+	warehouseModule "ecosystem.garyle/service/internal/app/module/wms/master-data/warehouse"
+	importerService "ecosystem.garyle/service/internal/app/service/wms/master-data/importer"
 )
 
 var Module = fx.Module("wms",
+	fx.Provide(
+		importerService.NewJobStore,
+		importHandler.NewImportHandler,
+	),
 	productModule.Module,
 	locationModule.Module,
 	supplierModule.Module,
 	customerModule.Module,
 	categoryModule.Module,
+	sourcingModule.Module,
+	batchModule.Module,
+	slottingModule.Module,
+	warehouseModule.Module,
+	stockModule.Module,
+	floorMapModule.Module,
+	trashModule.Module,
+	historyModule.Module,
 )
 
-func RegisterWMSHandler(db *sql.DB, router *gin.RouterGroup) {
+func RegisterWMSHandler(db *sql.DB, cfg *config.Config, router *gin.RouterGroup) error {
+	customerKeyring, err := customerModule.NewCustomerKeyring(cfg)
+	if err != nil {
+		return err
+	}
+
+	// the imports of every entity share one job store
+	importer := importHandler.NewImportHandler(importerService.NewJobStore())
+
 	wmsGroup := router.Group("/wms")
 	masterDataGroup := wmsGroup.Group("/master-data")
-	productModule.RegisterProductHandler(db, masterDataGroup)
-	locationModule.RegisterLocationHandler(db, masterDataGroup)
-	supplierModule.RegisterSupplierHandler(db, masterDataGroup)
-	customerModule.RegisterCustomerHandler(db, masterDataGroup)
-	categoryModule.RegisterCategoryHandler(db, masterDataGroup)
+	productModule.RegisterProductHandler(db, importer, masterDataGroup)
+	locationModule.RegisterLocationHandler(db, importer, masterDataGroup)
+	supplierModule.RegisterSupplierHandler(db, importer, masterDataGroup)
+	customerModule.RegisterCustomerHandler(db, importer, customerKeyring, masterDataGroup)
+	categoryModule.RegisterCategoryHandler(db, importer, masterDataGroup)
+	sourcingModule.RegisterSourcingHandler(db, masterDataGroup)
+	batchModule.RegisterBatchHandler(db, masterDataGroup)
+	slottingModule.RegisterSlottingHandler(db, masterDataGroup)
+	warehouseModule.RegisterWarehouseHandler(db, masterDataGroup)
+	stockModule.RegisterStockHandler(db, masterDataGroup)
+	floorMapModule.RegisterFloorMapHandler(db, masterDataGroup)
+	trashModule.RegisterTrashHandler(db, masterDataGroup)
+	historyModule.RegisterHistoryHandler(db, customerKeyring, masterDataGroup)
+	importer.RegisterImportJobRoutes(masterDataGroup)
+
+	return nil
 }
@@ -0,0 +1,201 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	batchModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/batch"
+	batchRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/batch"
+	productRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/product"
+	"ecosystem.garyle/service/pkg/utils/filter"
+)
+
+// maxExpiringDays limits the window of the nearing-expiry query
+const maxExpiringDays = 3650
+
+type BatchService interface {
+	Create(ctx context.Context, batch *batchModel.Batch) (*batchModel.Batch, error)
+	GetByID(ctx context.Context, id int) (*batchModel.Batch, error)
+	List(ctx context.Context, limit, page int, params *filter.Params) ([]*batchModel.Batch, error)
+	Count(ctx context.Context, params *filter.Params) (int, error)
+	ListExpiring(ctx context.Context, days, limit, page int, params *filter.Params) ([]*batchModel.Batch, int, error)
+	UpdateByID(ctx context.Context, batch *batchModel.Batch, id int) error
+	DeleteByID(ctx context.Context, id int) error
+}
+
+type batchService struct {
+	batchRepo   batchRepo.BatchRepository
+	productRepo productRepo.ProductRepository
+}
+
+func NewBatchService(batchRepo batchRepo.BatchRepository, productRepo productRepo.ProductRepository) BatchService {
+	return &batchService{
+		batchRepo:   batchRepo,
+		productRepo: productRepo,
+	}
+}
+
+// Create implements BatchService.
+func (s *batchService) Create(ctx context.Context, batch *batchModel.Batch) (*batchModel.Batch, error) {
+	if batch.ProductID <= 0 {
+		return nil, errors.New("product_id is required")
+	}
+
+	if err := validateBatch(batch); err != nil {
+		return nil, err
+	}
+
+	product, err := s.productRepo.GetByID(ctx, batch.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+
+	if !product.IsBatchTracked {
+		return nil, errors.New("product is not batch tracked")
+	}
+
+	return s.batchRepo.Create(ctx, batch)
+}
+
+// validate batch
+func validateBatch(batch *batchModel.Batch) error {
+	batch.BatchNo = strings.TrimSpace(batch.BatchNo)
+	if batch.BatchNo == "" {
+		return errors.New("batch_no is required")
+	}
+
+	if batch.Status == "" {
+		batch.Status = batchModel.StatusActive
+	}
+
+	if !batch.Status.IsValid() {
+		return errors.New("status must be active, quarantined or expired")
+	}
+
+	if batch.ManufactureDate != nil && batch.ExpireDate != nil && batch.ExpireDate.Before(batch.ManufactureDate.Time) {
+		return errors.New("expire_date must not be before manufacture_date")
+	}
+
+	return nil
+}
+
+// GetByID implements BatchService.
+func (s *batchService) GetByID(ctx context.Context, id int) (*batchModel.Batch, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid batch id")
+	}
+
+	return s.batchRepo.GetByID(ctx, id)
+}
+
+// List implements BatchService.
+func (s *batchService) List(ctx context.Context, limit, page int, params *filter.Params) ([]*batchModel.Batch, error) {
+	return s.batchRepo.List(ctx, limit, page, params)
+}
+
+// Count implements BatchService.
+func (s *batchService) Count(ctx context.Context, params *filter.Params) (int, error) {
+	return s.batchRepo.Count(ctx, params)
+}
+
+// ListExpiring returns the batches that are not expired yet but expire
+// within the next days, the soonest first unless another sort is given
+func (s *batchService) ListExpiring(ctx context.Context, days, limit, page int, params *filter.Params) ([]*batchModel.Batch, int, error) {
+	if days < 0 || days > maxExpiringDays {
+		return nil, 0, errors.New("days must be between 0 and 3650")
+	}
+
+	if params == nil {
+		params = &filter.Params{}
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	params.Conditions = append(params.Conditions,
+		filter.Condition{Column: "expire_date", Operator: filter.Gte, Values: []interface{}{today}},
+		filter.Condition{Column: "expire_date", Operator: filter.Lt, Values: []interface{}{today.AddDate(0, 0, days+1)}},
+		filter.Condition{Column: batchModel.StatusColumn, Operator: filter.In, Values: []interface{}{string(batchModel.StatusActive), string(batchModel.StatusQuarantined)}},
+	)
+
+	if len(params.Sorts) == 0 || isDefaultSort(params.Sorts) {
+		params.Sorts = []filter.Sort{{Column: "expire_date"}}
+	}
+
+	batches, err := s.batchRepo.List(ctx, limit, page, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.batchRepo.Count(ctx, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return batches, total, nil
+}
+
+// isDefaultSort checks if the sorts are the list default, filter.Parse fills
+// it in when no sort was requested
+func isDefaultSort(sorts []filter.Sort) bool {
+	defaults := batchModel.QuerySpec.DefaultSort
+	if len(sorts) != len(defaults) {
+		return false
+	}
+
+	for i := range sorts {
+		if sorts[i] != defaults[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// UpdateByID implements BatchService. The product of a batch cannot change.
+func (s *batchService) UpdateByID(ctx context.Context, batch *batchModel.Batch, id int) error {
+	if id <= 0 {
+		return errors.New("invalid batch id")
+	}
+
+	if err := validateBatch(batch); err != nil {
+		return err
+	}
+
+	existing, err := s.batchRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return errors.New("batch not found")
+	}
+
+	batch.ID = existing.ID
+	batch.ProductID = existing.ProductID
+
+	return s.batchRepo.UpdateByID(ctx, batch, id)
+}
+
+// DeleteByID implements BatchService.
+func (s *batchService) DeleteByID(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid batch id")
+	}
+
+	existing, err := s.batchRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return errors.New("batch not found")
+	}
+
+	return s.batchRepo.DeleteByID(ctx, id)
+}
@@ -0,0 +1,76 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	categoryModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/category"
+)
+
+// GetSchema returns the product attributes of a category together with the
+// ones it inherits from its ancestors
+func (c *categoryService) GetSchema(ctx context.Context, id int) ([]categoryModel.SchemaAttribute, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid category ID")
+	}
+
+	path, err := c.categoryRepository.ListAncestors(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(path) == 0 {
+		return nil, errors.New("category not found")
+	}
+
+	return categoryModel.Schema(path), nil
+}
+
+// validateAttributeDefinitions checks the attribute definitions of a category
+func validateAttributeDefinitions(definitions categoryModel.AttributeDefinitions) error {
+	seen := map[string]bool{}
+	for i := range definitions {
+		definition := &definitions[i]
+		definition.Name = strings.TrimSpace(definition.Name)
+		definition.Unit = strings.TrimSpace(definition.Unit)
+
+		if !categoryModel.AttributeNamePattern.MatchString(definition.Name) {
+			return errors.New("attribute name must start with a lowercase letter and contain only lowercase letters, digits or underscores")
+		}
+
+		if seen[definition.Name] {
+			return errors.New("attribute names must be unique")
+		}
+		seen[definition.Name] = true
+
+		if !definition.Type.IsValid() {
+			return errors.New("attribute type must be one of string, number, enum or boolean")
+		}
+
+		if definition.Unit != "" && definition.Type != categoryModel.AttributeNumber {
+			return errors.New("unit is only allowed on number attributes")
+		}
+
+		if definition.Type != categoryModel.AttributeEnum {
+			if len(definition.Values) > 0 {
+				return errors.New("values are only allowed on enum attributes")
+			}
+			continue
+		}
+
+		if len(definition.Values) == 0 {
+			return errors.New("enum attributes need at least one value")
+		}
+
+		values := map[string]bool{}
+		for _, value := range definition.Values {
+			if value == "" || values[value] {
+				return errors.New("enum values must be unique and not empty")
+			}
+			values[value] = true
+		}
+	}
+
+	return nil
+}
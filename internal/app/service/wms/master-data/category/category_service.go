@@ -3,18 +3,30 @@ package category
 import (
 	"context"
 	"errors"
+	"strconv"
 
+	"ecosystem.garyle/service/internal/app/service/wms/master-data/importer"
 	categoryModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/category"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
 	categoryRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/category"
+	"ecosystem.garyle/service/pkg/utils/filter"
+	"ecosystem.garyle/service/pkg/utils/spreadsheet"
 )
 
 type CategoryService interface {
 	Create(ctx context.Context, category *categoryModel.Category) (*categoryModel.Category, error)
-	List(ctx context.Context, limit, page int) ([]*categoryModel.Category, error)
-	Count(ctx context.Context) (int, error)
+	List(ctx context.Context, limit, page int, params *filter.Params) ([]*categoryModel.Category, error)
+	Count(ctx context.Context, params *filter.Params) (int, error)
 	GetByID(ctx context.Context, id int) (*categoryModel.Category, error)
 	Update(ctx context.Context, category *categoryModel.Category, id int) (*categoryModel.Category, error)
-	Delete(ctx context.Context, id int) error
+	Delete(ctx context.Context, id int, policy categoryModel.DeletePolicy) error
+	Move(ctx context.Context, id int, parentID *int) (*categoryModel.Category, error)
+	Import(ctx context.Context, records []spreadsheet.Record, opts imports.Options, progress func(done, total int)) (*imports.Result, error)
+	Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(category *categoryModel.Category) error) error
+	GetTree(ctx context.Context) ([]*categoryModel.Node, error)
+	GetSubtree(ctx context.Context, id int) (*categoryModel.Node, error)
+	GetAncestors(ctx context.Context, id int) ([]*categoryModel.Node, error)
+	GetSchema(ctx context.Context, id int) ([]categoryModel.SchemaAttribute, error)
 }
 
 type categoryService struct {
@@ -41,6 +53,10 @@ func (c *categoryService) Create(ctx context.Context, category *categoryModel.Ca
 		return nil, errors.New("category already exists")
 	}
 
+	if err := c.validateParent(ctx, 0, category.ParentID); err != nil {
+		return nil, err
+	}
+
 	newCategory, err := c.categoryRepository.Create(ctx, category)
 	if err != nil {
 		return nil, err
@@ -50,22 +66,18 @@ func (c *categoryService) Create(ctx context.Context, category *categoryModel.Ca
 }
 
 // List implements CategoryService.
-func (c *categoryService) List(ctx context.Context, limit int, page int) ([]*categoryModel.Category, error) {
-	categories, err := c.categoryRepository.List(ctx, limit, page)
+func (c *categoryService) List(ctx context.Context, limit, page int, params *filter.Params) ([]*categoryModel.Category, error) {
+	categories, err := c.categoryRepository.List(ctx, limit, page, params)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(categories) == 0 || categories == nil {
-		return nil, errors.New("no categories found")
-	}
-
 	return categories, nil
 }
 
 // Count implements CategoryService.
-func (c *categoryService) Count(ctx context.Context) (int, error) {
-	count, err := c.categoryRepository.Count(ctx)
+func (c *categoryService) Count(ctx context.Context, params *filter.Params) (int, error) {
+	count, err := c.categoryRepository.Count(ctx, params)
 	if err != nil {
 		return 0, err
 	}
@@ -105,7 +117,7 @@ func (c *categoryService) Update(ctx context.Context, category *categoryModel.Ca
 		return nil, err
 	}
 
-	existingCategory, err := c.categoryRepository.GetByID(ctx, category.ID)
+	existingCategory, err := c.categoryRepository.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -113,6 +125,10 @@ func (c *categoryService) Update(ctx context.Context, category *categoryModel.Ca
 		return nil, errors.New("category not found")
 	}
 
+	if err := c.validateParent(ctx, id, category.ParentID); err != nil {
+		return nil, err
+	}
+
 	updatedCategory, err := c.categoryRepository.Update(ctx, category, id)
 	if err != nil {
 		return nil, err
@@ -122,7 +138,7 @@ func (c *categoryService) Update(ctx context.Context, category *categoryModel.Ca
 }
 
 // Delete implements CategoryService.
-func (c *categoryService) Delete(ctx context.Context, id int) error {
+func (c *categoryService) Delete(ctx context.Context, id int, policy categoryModel.DeletePolicy) error {
 	if id <= 0 {
 		return errors.New("invalid category ID")
 	}
@@ -135,7 +151,7 @@ func (c *categoryService) Delete(ctx context.Context, id int) error {
 		return errors.New("category not found")
 	}
 
-	err = c.categoryRepository.Delete(ctx, id)
+	err = c.deleteWithPolicy(ctx, id, policy)
 	if err != nil {
 		return err
 	}
@@ -148,5 +164,43 @@ func validateCreateOrUpdateCategory(category *categoryModel.Category) error {
 		return errors.New("name is required")
 	}
 
-	return nil
+	return validateAttributeDefinitions(category.Attributes)
+}
+
+// Import validates and saves category rows read from an uploaded file
+func (c *categoryService) Import(ctx context.Context, records []spreadsheet.Record, opts imports.Options, progress func(done, total int)) (*imports.Result, error) {
+	return importer.Run(ctx, records, importer.Source[categoryModel.Category]{
+		Entity: "category",
+		Parse:  parseCategoryRow,
+		Validate: func(category *categoryModel.Category) error {
+			if err := validateCreateOrUpdateCategory(category); err != nil {
+				return err
+			}
+
+			return c.validateParent(ctx, 0, category.ParentID)
+		},
+		Save: c.categoryRepository.CreateBatch,
+	}, opts, progress)
+}
+
+// parseCategoryRow maps the columns of an import row to a category
+func parseCategoryRow(values map[string]string) (*categoryModel.Category, error) {
+	category := &categoryModel.Category{
+		Name: values["name"],
+	}
+
+	if values["parent_id"] != "" {
+		parentID, err := strconv.Atoi(values["parent_id"])
+		if err != nil || parentID <= 0 {
+			return nil, errors.New("parent_id must be a positive integer")
+		}
+		category.ParentID = &parentID
+	}
+
+	return category, nil
+}
+
+// Export streams the category rows matching the list filters to fn
+func (c *categoryService) Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(category *categoryModel.Category) error) error {
+	return c.categoryRepository.Export(ctx, params, includeDeleted, fn)
 }
@@ -0,0 +1,87 @@
+package category
+
+import (
+	"context"
+	"errors"
+
+	categoryModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/category"
+)
+
+// Move places a category under a new parent, or at the root when the parent is nil
+func (c *categoryService) Move(ctx context.Context, id int, parentID *int) (*categoryModel.Category, error) {
+	if _, err := c.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if err := c.validateParent(ctx, id, parentID); err != nil {
+		return nil, err
+	}
+
+	if err := c.categoryRepository.Move(ctx, id, parentID); err != nil {
+		return nil, err
+	}
+
+	return c.GetByID(ctx, id)
+}
+
+// validateParent checks that the parent exists and is not the category itself
+// or one of its descendants. An id of 0 is a category that does not exist yet.
+func (c *categoryService) validateParent(ctx context.Context, id int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+
+	if *parentID <= 0 {
+		return errors.New("parent_id must be a positive integer")
+	}
+
+	parent, err := c.categoryRepository.GetByID(ctx, *parentID)
+	if err != nil {
+		return err
+	}
+	if parent == nil {
+		return errors.New("parent category not found")
+	}
+
+	if id == 0 {
+		return nil
+	}
+
+	cycle, err := c.categoryRepository.IsInSubtree(ctx, id, *parentID)
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return errors.New("category cannot be moved under itself or one of its descendants")
+	}
+
+	return nil
+}
+
+// deleteWithPolicy deletes a category and applies the policy to its children
+func (c *categoryService) deleteWithPolicy(ctx context.Context, id int, policy categoryModel.DeletePolicy) error {
+	if policy == "" {
+		policy = categoryModel.DefaultDeletePolicy
+	}
+
+	if !policy.IsValid() {
+		return errors.New("policy must be one of block, cascade or reparent")
+	}
+
+	switch policy {
+	case categoryModel.DeleteCascade:
+		return c.categoryRepository.DeleteSubtree(ctx, id)
+	case categoryModel.DeleteReparent:
+		return c.categoryRepository.DeleteAndReparent(ctx, id)
+	}
+
+	children, err := c.categoryRepository.CountChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return errors.New("category still has child categories")
+	}
+
+	return c.categoryRepository.Delete(ctx, id)
+}
@@ -0,0 +1,55 @@
+package category
+
+import (
+	"context"
+	"errors"
+
+	categoryModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/category"
+)
+
+// GetTree returns every category nested under its parent
+func (c *categoryService) GetTree(ctx context.Context) ([]*categoryModel.Node, error) {
+	nodes, err := c.categoryRepository.ListTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return categoryModel.BuildTree(nodes), nil
+}
+
+// GetSubtree returns the category with its descendants nested under it
+func (c *categoryService) GetSubtree(ctx context.Context, id int) (*categoryModel.Node, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid category ID")
+	}
+
+	nodes, err := c.categoryRepository.GetSubtree(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) == 0 {
+		return nil, errors.New("category not found")
+	}
+
+	return categoryModel.BuildTree(nodes)[0], nil
+}
+
+// GetAncestors returns the breadcrumb of a category, from the root down to
+// the category itself
+func (c *categoryService) GetAncestors(ctx context.Context, id int) ([]*categoryModel.Node, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid category ID")
+	}
+
+	nodes, err := c.categoryRepository.ListAncestors(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) == 0 {
+		return nil, errors.New("category not found")
+	}
+
+	return nodes, nil
+}
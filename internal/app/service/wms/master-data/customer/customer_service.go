@@ -4,25 +4,65 @@ import (
 	"context"
 	"errors"
 
+	"ecosystem.garyle/service/internal/app/service/wms/master-data/importer"
+	partyService "ecosystem.garyle/service/internal/app/service/wms/master-data/party"
 	customerModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/customer"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
+	partyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
 	customerRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/customer"
+	"ecosystem.garyle/service/pkg/utils/filter"
+	"ecosystem.garyle/service/pkg/utils/spreadsheet"
 )
 
 type CustomerService interface {
 	Create(ctx context.Context, customer *customerModel.Customer) (*customerModel.Customer, error)
 	GetByID(ctx context.Context, id int) (*customerModel.Customer, error)
-	List(ctx context.Context, limit int, page int) ([]*customerModel.Customer, error)
+	List(ctx context.Context, limit, page int, params *filter.Params) ([]*customerModel.Customer, error)
 	UpdateByID(ctx context.Context, customer *customerModel.Customer, id int) error
 	DeleteByID(ctx context.Context, id int) error
-	Count(ctx context.Context) (int, error)
+	Count(ctx context.Context, params *filter.Params) (int, error)
+	Import(ctx context.Context, records []spreadsheet.Record, opts imports.Options, progress func(done, total int)) (*imports.Result, error)
+	Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(customer *customerModel.Customer) error) error
+	partyService.DetailService
+	partyService.DuplicateService
+	ServiceProfileService
+	PrivacyService
 }
 
 type customerService struct {
+	partyService.DetailService
+	partyService.DuplicateService
 	customerRepository customerRepo.CustomerRepository
 }
 
 func NewCustomerService(customerRepository customerRepo.CustomerRepository) CustomerService {
-	return &customerService{customerRepository: customerRepository}
+	c := &customerService{customerRepository: customerRepository}
+	c.DetailService = partyService.NewDetailService(customerRepository, c.checkCustomer)
+	c.DuplicateService = partyService.NewDuplicateService(customerRepository, "customer", func(ctx context.Context, id int) (interface{}, error) {
+		return c.GetByID(ctx, id)
+	})
+
+	return c
+}
+
+// checkCustomer returns "customer not found" when the customer does not exist
+// and "customer has been erased" when its personal data was erased, so no
+// addresses or contacts can be added to it again
+func (c *customerService) checkCustomer(ctx context.Context, id int) error {
+	customer, err := c.customerRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if customer == nil {
+		return errors.New("customer not found")
+	}
+
+	if customer.IsErased() {
+		return errors.New("customer has been erased")
+	}
+
+	return nil
 }
 
 // Create implements CustomerService.
@@ -32,7 +72,7 @@ func (c *customerService) Create(ctx context.Context, customer *customerModel.Cu
 		return nil, err
 	}
 
-	existingCustomer, err := c.GetByID(ctx, customer.ID)
+	existingCustomer, err := c.customerRepository.GetByID(ctx, customer.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -41,10 +81,22 @@ func (c *customerService) Create(ctx context.Context, customer *customerModel.Cu
 		return nil, errors.New("customer already exists")
 	}
 
+	if err := partyService.CheckTaxID(ctx, c.customerRepository, "customer", customer.Registration, 0); err != nil {
+		return nil, err
+	}
+
+	// likely duplicates do not block the create, they are returned as a warning
+	profile := partyModel.NewProfile(0, customer.Name, customer.Address, customer.Contact, customer.Addresses, customer.Contacts)
+	duplicates, err := c.MatchProfile(ctx, profile, partyModel.DefaultDuplicateThreshold)
+	if err != nil {
+		return nil, err
+	}
+
 	createdCustomer, err := c.customerRepository.Create(ctx, customer)
 	if err != nil {
 		return nil, err
 	}
+	createdCustomer.PossibleDuplicates = duplicates
 
 	return createdCustomer, nil
 }
@@ -75,10 +127,22 @@ func (c *customerService) GetByID(ctx context.Context, id int) (*customerModel.C
 
 	customer, err := c.customerRepository.GetByID(ctx, id)
 	if err != nil {
-		if err.Error() == "sql: no rows in result set" {
-			return nil, errors.New("customer not found")
-		}
+		return nil, err
+	}
+
+	if customer == nil {
+		return nil, errors.New("customer not found")
+	}
+
+	if customer.Addresses, err = c.customerRepository.ListAddresses(ctx, id); err != nil {
+		return nil, err
+	}
 
+	if customer.Contacts, err = c.customerRepository.ListContacts(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if customer.ServiceProfile, err = c.serviceProfile(ctx, id); err != nil {
 		return nil, err
 	}
 
@@ -86,8 +150,8 @@ func (c *customerService) GetByID(ctx context.Context, id int) (*customerModel.C
 }
 
 // List implements CustomerService.
-func (c *customerService) List(ctx context.Context, limit int, page int) ([]*customerModel.Customer, error) {
-	customers, err := c.customerRepository.List(ctx, limit, page)
+func (c *customerService) List(ctx context.Context, limit, page int, params *filter.Params) ([]*customerModel.Customer, error) {
+	customers, err := c.customerRepository.List(ctx, limit, page, params)
 	if err != nil {
 		return nil, err
 	}
@@ -105,7 +169,20 @@ func (c *customerService) UpdateByID(ctx context.Context, customer *customerMode
 		return errors.New("invalid customer id")
 	}
 
-	err := validateCustomer(customer)
+	// addresses and contacts are changed through their own routes, the
+	// stored ones stand in for the free-text fields
+	addresses, err := c.customerRepository.ListAddresses(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	contacts, err := c.customerRepository.ListContacts(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	customer.Addresses, customer.Contacts = addresses, contacts
+	err = validateCustomer(customer)
 	if err != nil {
 		return err
 	}
@@ -119,12 +196,20 @@ func (c *customerService) UpdateByID(ctx context.Context, customer *customerMode
 		return errors.New("customer not found")
 	}
 
+	if existingCustomer.IsErased() {
+		return errors.New("customer has been erased")
+	}
+
+	if err := partyService.CheckTaxID(ctx, c.customerRepository, "customer", customer.Registration, id); err != nil {
+		return err
+	}
+
 	return c.customerRepository.UpdateByID(ctx, customer, id)
 }
 
 // Count implements CustomerService.
-func (c *customerService) Count(ctx context.Context) (int, error) {
-	return c.customerRepository.Count(ctx)
+func (c *customerService) Count(ctx context.Context, params *filter.Params) (int, error) {
+	return c.customerRepository.Count(ctx, params)
 }
 
 func validateCustomer(customer *customerModel.Customer) error {
@@ -132,13 +217,49 @@ func validateCustomer(customer *customerModel.Customer) error {
 		return errors.New("name is required")
 	}
 
-	if customer.Address == "" {
+	// the free-text fields may be left out when structured details are given
+	if customer.Address == "" && len(customer.Addresses) == 0 {
 		return errors.New("address is required")
 	}
 
-	if customer.Contact == "" {
+	if customer.Contact == "" && len(customer.Contacts) == 0 {
 		return errors.New("contact is required")
 	}
 
-	return nil
+	if err := partyService.ValidateRegistration(&customer.Registration); err != nil {
+		return err
+	}
+
+	return partyService.ValidateDetails(customer.Addresses, customer.Contacts)
+}
+
+// Import validates and saves customer rows read from an uploaded file
+func (c *customerService) Import(ctx context.Context, records []spreadsheet.Record, opts imports.Options, progress func(done, total int)) (*imports.Result, error) {
+	return importer.Run(ctx, records, importer.Source[customerModel.Customer]{
+		Entity:   "customer",
+		Parse:    parseCustomerRow,
+		Validate: validateCustomer,
+		Save:     c.customerRepository.CreateBatch,
+	}, opts, progress)
+}
+
+// parseCustomerRow maps the columns of an import row to a customer
+func parseCustomerRow(values map[string]string) (*customerModel.Customer, error) {
+	return &customerModel.Customer{
+		Name:    values["name"],
+		Address: values["address"],
+		Contact: values["contact"],
+		Registration: partyModel.Registration{
+			TaxCountry:         values["tax_country"],
+			TaxID:              values["tax_id"],
+			RegistrationNumber: values["registration_number"],
+			PaymentTerms:       values["payment_terms"],
+			Currency:           values["currency"],
+		},
+	}, nil
+}
+
+// Export streams the customer rows matching the list filters to fn
+func (c *customerService) Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(customer *customerModel.Customer) error) error {
+	return c.customerRepository.Export(ctx, params, includeDeleted, fn)
 }
@@ -0,0 +1,37 @@
+package customer
+
+import (
+	"context"
+	"errors"
+)
+
+// PrivacyService erases the personal data of customers on request and
+// rotates the key it is encrypted with
+type PrivacyService interface {
+	Erase(ctx context.Context, id int) error
+	RotateKeys(ctx context.Context) (map[string]int, error)
+}
+
+// Erase implements CustomerService. The customer keeps its id and its
+// non-personal data, so historical orders still refer to it.
+func (c *customerService) Erase(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid customer id")
+	}
+
+	erased, err := c.customerRepository.Erase(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !erased {
+		return errors.New("customer not found")
+	}
+
+	return nil
+}
+
+// RotateKeys implements CustomerService.
+func (c *customerService) RotateKeys(ctx context.Context) (map[string]int, error) {
+	return c.customerRepository.RotateKeys(ctx)
+}
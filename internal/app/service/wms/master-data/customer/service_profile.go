@@ -0,0 +1,168 @@
+package customer
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	customerModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/customer"
+)
+
+// ServiceProfileService manages the service-level profiles outbound order
+// processing consults
+type ServiceProfileService interface {
+	GetServiceProfile(ctx context.Context, customerID int) (*customerModel.ServiceProfile, error)
+	SaveServiceProfile(ctx context.Context, customerID int, profile *customerModel.ServiceProfile) (*customerModel.ServiceProfile, error)
+	DeleteServiceProfile(ctx context.Context, customerID int) error
+	CheckOrder(ctx context.Context, customerID int, check customerModel.OrderCheck) (*customerModel.OrderDecision, error)
+}
+
+var (
+	cutoffPattern  = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+	carrierPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9_-]{1,19}$`)
+)
+
+// maxInstructionsLength limits the labeling and packing instructions
+const maxInstructionsLength = 2000
+
+// GetServiceProfile implements CustomerService. A customer without a profile
+// gets the default one.
+func (c *customerService) GetServiceProfile(ctx context.Context, customerID int) (*customerModel.ServiceProfile, error) {
+	if err := c.checkCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	return c.serviceProfile(ctx, customerID)
+}
+
+// SaveServiceProfile implements CustomerService.
+func (c *customerService) SaveServiceProfile(ctx context.Context, customerID int, profile *customerModel.ServiceProfile) (*customerModel.ServiceProfile, error) {
+	if err := c.checkCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	profile.CustomerID = customerID
+	profile.Normalize()
+	if err := validateServiceProfile(profile); err != nil {
+		return nil, err
+	}
+
+	return c.customerRepository.SaveServiceProfile(ctx, profile)
+}
+
+// DeleteServiceProfile implements CustomerService. The customer falls back to
+// the default profile.
+func (c *customerService) DeleteServiceProfile(ctx context.Context, customerID int) error {
+	if err := c.checkCustomer(ctx, customerID); err != nil {
+		return err
+	}
+
+	deleted, err := c.customerRepository.DeleteServiceProfile(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return errors.New("service profile not found")
+	}
+
+	return nil
+}
+
+// CheckOrder implements CustomerService.
+func (c *customerService) CheckOrder(ctx context.Context, customerID int, check customerModel.OrderCheck) (*customerModel.OrderDecision, error) {
+	profile, err := c.GetServiceProfile(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if check.OrderedAt.IsZero() {
+		check.OrderedAt = time.Now()
+	}
+
+	return profile.Decide(check)
+}
+
+func (c *customerService) serviceProfile(ctx context.Context, customerID int) (*customerModel.ServiceProfile, error) {
+	profile, err := c.customerRepository.GetServiceProfile(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile == nil {
+		return customerModel.DefaultServiceProfile(customerID), nil
+	}
+
+	return profile, nil
+}
+
+func validateServiceProfile(profile *customerModel.ServiceProfile) error {
+	if profile.OrderCutoff != "" && !cutoffPattern.MatchString(profile.OrderCutoff) {
+		return errors.New("order_cutoff must use the HH:MM format")
+	}
+
+	if _, err := time.LoadLocation(profile.Timezone); err != nil {
+		return errors.New("timezone must be an IANA timezone, e.g. Asia/Jakarta")
+	}
+
+	if profile.DispatchLeadDays < 0 || profile.DispatchLeadDays > 30 {
+		return errors.New("dispatch_lead_days must be between 0 and 30")
+	}
+
+	days := map[string]bool{}
+	for _, day := range profile.DispatchDays {
+		if _, ok := customerModel.Weekdays[day]; !ok || days[day] {
+			return errors.New("dispatch_days must be distinct days from sun to sat, e.g. mon")
+		}
+		days[day] = true
+	}
+
+	carriers := map[string]bool{}
+	for _, carrier := range profile.AllowedCarriers {
+		if !carrierPattern.MatchString(carrier) || carriers[carrier] {
+			return errors.New("allowed_carriers must be distinct carrier codes of 2 to 20 letters, digits, '-' or '_'")
+		}
+		carriers[carrier] = true
+	}
+
+	if profile.PreferredCarrier != "" {
+		if !carrierPattern.MatchString(profile.PreferredCarrier) {
+			return errors.New("preferred_carrier must be a carrier code of 2 to 20 letters, digits, '-' or '_'")
+		}
+
+		if !profile.AllowsCarrier(profile.PreferredCarrier) {
+			return errors.New("preferred_carrier must be one of allowed_carriers")
+		}
+	}
+
+	if !profile.SplitPolicy.IsValid() {
+		return errors.New("split_policy must be one of allowed, line_complete or ship_complete")
+	}
+
+	if !profile.PriorityTier.IsValid() {
+		return errors.New("priority_tier must be one of low, standard, high or critical")
+	}
+
+	if len(profile.LabelingInstructions) > maxInstructionsLength || len(profile.PackingInstructions) > maxInstructionsLength {
+		return errors.New("labeling_instructions and packing_instructions must not be longer than 2000 characters")
+	}
+
+	return nil
+}
+
+// ServiceProfileValidationErrors are the errors of SaveServiceProfile caused by the request
+func ServiceProfileValidationErrors() []string {
+	return []string{
+		"order_cutoff must use the HH:MM format",
+		"timezone must be an IANA timezone, e.g. Asia/Jakarta",
+		"dispatch_lead_days must be between 0 and 30",
+		"dispatch_days must be distinct days from sun to sat, e.g. mon",
+		"allowed_carriers must be distinct carrier codes of 2 to 20 letters, digits, '-' or '_'",
+		"preferred_carrier must be a carrier code of 2 to 20 letters, digits, '-' or '_'",
+		"preferred_carrier must be one of allowed_carriers",
+		"split_policy must be one of allowed, line_complete or ship_complete",
+		"priority_tier must be one of low, standard, high or critical",
+		"labeling_instructions and packing_instructions must not be longer than 2000 characters",
+	}
+}
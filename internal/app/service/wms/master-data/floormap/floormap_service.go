@@ -0,0 +1,174 @@
+package floormap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	floorMapModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/floormap"
+	locationModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
+	floorMapRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/floormap"
+	locationRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/location"
+	warehouseRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/warehouse"
+)
+
+type FloorMapService interface {
+	Get(ctx context.Context, warehouseID int) (*floorMapModel.FloorMap, error)
+	Import(ctx context.Context, warehouseID int, floorMap *floorMapModel.FloorMap) (*floorMapModel.FloorMap, error)
+	Route(ctx context.Context, warehouseID, fromLocationID, toLocationID int) (*floorMapModel.Route, error)
+	RenderSVG(ctx context.Context, warehouseID int) ([]byte, error)
+}
+
+type floorMapService struct {
+	floorMapRepo  floorMapRepo.FloorMapRepository
+	warehouseRepo warehouseRepo.WarehouseRepository
+	locationRepo  locationRepo.LocationRepository
+}
+
+func NewFloorMapService(floorMapRepo floorMapRepo.FloorMapRepository, warehouseRepo warehouseRepo.WarehouseRepository, locationRepo locationRepo.LocationRepository) FloorMapService {
+	return &floorMapService{
+		floorMapRepo:  floorMapRepo,
+		warehouseRepo: warehouseRepo,
+		locationRepo:  locationRepo,
+	}
+}
+
+// Get implements FloorMapService. A warehouse without a floor map has an
+// empty graph.
+func (f *floorMapService) Get(ctx context.Context, warehouseID int) (*floorMapModel.FloorMap, error) {
+	locations, err := f.warehouseLocations(ctx, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	floorMap, err := f.floorMapRepo.Get(ctx, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if floorMap == nil {
+		floorMap = &floorMapModel.FloorMap{WarehouseID: warehouseID, Graph: floorMapModel.Graph{Nodes: []floorMapModel.Node{}, Edges: []floorMapModel.Edge{}}}
+	}
+
+	floorMap.Locations = []floorMapModel.LocationPosition{}
+	for _, location := range locations {
+		if location.Coordinates != nil {
+			floorMap.Locations = append(floorMap.Locations, floorMapModel.LocationPosition{
+				LocationID:  location.ID,
+				Code:        location.Code,
+				Coordinates: *location.Coordinates,
+			})
+		}
+	}
+
+	return floorMap, nil
+}
+
+// Import implements FloorMapService. The graph replaces the current one, the
+// listed locations are moved and the other locations keep their place.
+func (f *floorMapService) Import(ctx context.Context, warehouseID int, floorMap *floorMapModel.FloorMap) (*floorMapModel.FloorMap, error) {
+	if err := floorMap.Graph.Validate(); err != nil {
+		return nil, err
+	}
+
+	locations, err := f.warehouseLocations(ctx, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*locationModel.Location, len(locations))
+	byCode := make(map[string]*locationModel.Location, len(locations))
+	for _, location := range locations {
+		byID[location.ID] = location
+		byCode[location.Code] = location
+	}
+
+	positions := make([]floorMapModel.LocationPosition, 0, len(floorMap.Locations))
+	for _, position := range floorMap.Locations {
+		location := byID[position.LocationID]
+		if position.LocationID == 0 {
+			location = byCode[position.Code]
+		}
+
+		if location == nil {
+			return nil, errors.New("locations must belong to the warehouse")
+		}
+
+		position.LocationID = location.ID
+		position.Code = location.Code
+		positions = append(positions, position)
+	}
+
+	if err := f.floorMapRepo.Save(ctx, warehouseID, floorMap.Graph, positions); err != nil {
+		return nil, err
+	}
+
+	return f.Get(ctx, warehouseID)
+}
+
+// Route implements FloorMapService.
+func (f *floorMapService) Route(ctx context.Context, warehouseID, fromLocationID, toLocationID int) (*floorMapModel.Route, error) {
+	floorMap, err := f.Get(ctx, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make(map[int]floorMapModel.LocationPosition, len(floorMap.Locations))
+	for _, position := range floorMap.Locations {
+		positions[position.LocationID] = position
+	}
+
+	from, ok := positions[fromLocationID]
+	if !ok {
+		return nil, errors.New("location is not on the floor map of the warehouse")
+	}
+
+	to, ok := positions[toLocationID]
+	if !ok {
+		return nil, errors.New("location is not on the floor map of the warehouse")
+	}
+
+	distance, path, err := floorMap.Graph.ShortestRoute(from.Coordinates, to.Coordinates)
+	if err != nil {
+		return nil, err
+	}
+
+	return &floorMapModel.Route{
+		FromLocationID: fromLocationID,
+		ToLocationID:   toLocationID,
+		Distance:       distance,
+		Path:           path,
+	}, nil
+}
+
+// RenderSVG implements FloorMapService.
+func (f *floorMapService) RenderSVG(ctx context.Context, warehouseID int) ([]byte, error) {
+	floorMap, err := f.Get(ctx, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var output bytes.Buffer
+	if err := floorMap.RenderSVG(&output); err != nil {
+		return nil, err
+	}
+
+	return output.Bytes(), nil
+}
+
+func (f *floorMapService) warehouseLocations(ctx context.Context, warehouseID int) ([]*locationModel.Location, error) {
+	if warehouseID <= 0 {
+		return nil, errors.New("invalid warehouse id")
+	}
+
+	warehouse, err := f.warehouseRepo.GetByID(ctx, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if warehouse == nil {
+		return nil, errors.New("warehouse not found")
+	}
+
+	return f.locationRepo.ListByWarehouse(ctx, warehouseID)
+}
@@ -0,0 +1,152 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	historyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/history"
+	historyRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/history"
+)
+
+type HistoryService interface {
+	List(ctx context.Context, entity string, id, limit, page int) ([]*historyModel.Version, int, error)
+	// Diff compares two versions of a row. to 0 is the latest version and
+	// from 0 the one before to.
+	Diff(ctx context.Context, entity string, id, from, to int) (*historyModel.Diff, error)
+	AsOf(ctx context.Context, entity string, id int, at time.Time) (*historyModel.Version, error)
+}
+
+type historyService struct {
+	historyRepo historyRepo.HistoryRepository
+}
+
+func NewHistoryService(historyRepo historyRepo.HistoryRepository) HistoryService {
+	return &historyService{historyRepo: historyRepo}
+}
+
+// UnknownEntityError is returned for an entity without a history
+func UnknownEntityError() string {
+	return "entity must be one of " + strings.Join(historyModel.EntityNames(), ", ")
+}
+
+// NotFoundError is returned when a row has no versions, e.g. "product not found"
+func NotFoundError(entity historyModel.Entity) string {
+	return entity.Singular + " not found"
+}
+
+// VersionNotFoundError is returned when a requested version does not exist,
+// e.g. "product version not found"
+func VersionNotFoundError(entity historyModel.Entity) string {
+	return entity.Singular + " version not found"
+}
+
+// List implements HistoryService.
+func (s *historyService) List(ctx context.Context, name string, id, limit, page int) ([]*historyModel.Version, int, error) {
+	entity, err := findEntity(name)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.historyRepo.Count(ctx, entity, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if total == 0 {
+		return nil, 0, errors.New(NotFoundError(entity))
+	}
+
+	versions, err := s.historyRepo.List(ctx, entity, id, limit, page)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return versions, total, nil
+}
+
+// Diff implements HistoryService.
+func (s *historyService) Diff(ctx context.Context, name string, id, from, to int) (*historyModel.Diff, error) {
+	entity, err := findEntity(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if from < 0 || to < 0 {
+		return nil, errors.New("from and to must be version numbers")
+	}
+
+	latest, err := s.historyRepo.Count(ctx, entity, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if latest == 0 {
+		return nil, errors.New(NotFoundError(entity))
+	}
+
+	if to == 0 {
+		to = latest
+	}
+
+	if from == 0 {
+		from = to - 1
+		if from == 0 {
+			from = 1
+		}
+	}
+
+	fromVersion, err := s.historyRepo.Get(ctx, entity, id, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toVersion, err := s.historyRepo.Get(ctx, entity, id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromVersion == nil || toVersion == nil {
+		return nil, errors.New(VersionNotFoundError(entity))
+	}
+
+	return historyModel.Compare(fromVersion, toVersion), nil
+}
+
+// AsOf implements HistoryService.
+func (s *historyService) AsOf(ctx context.Context, name string, id int, at time.Time) (*historyModel.Version, error) {
+	entity, err := findEntity(name)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := s.historyRepo.GetAt(ctx, entity, id, at)
+	if err != nil {
+		return nil, err
+	}
+
+	if version != nil {
+		return version, nil
+	}
+
+	total, err := s.historyRepo.Count(ctx, entity, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if total == 0 {
+		return nil, errors.New(NotFoundError(entity))
+	}
+
+	return nil, errors.New(VersionNotFoundError(entity))
+}
+
+func findEntity(name string) (historyModel.Entity, error) {
+	entity, ok := historyModel.FindEntity(name)
+	if !ok {
+		return historyModel.Entity{}, errors.New(UnknownEntityError())
+	}
+
+	return entity, nil
+}
@@ -0,0 +1,99 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
+	"ecosystem.garyle/service/pkg/utils/spreadsheet"
+)
+
+// Source describes how rows of one entity are parsed, validated and saved
+type Source[T any] struct {
+	Entity   string
+	Parse    func(values map[string]string) (*T, error)
+	Validate func(item *T) error
+	Save     func(ctx context.Context, items []*T, opts imports.Options, report func(index int, err error)) error
+}
+
+// Run parses and validates every record, then saves the valid ones with
+// src.Save. progress is called after each saved row with the number of
+// processed rows out of the total.
+func Run[T any](ctx context.Context, records []spreadsheet.Record, src Source[T], opts imports.Options, progress func(done, total int)) (*imports.Result, error) {
+	if opts.Mode == "" {
+		opts.Mode = imports.AllOrNothing
+	}
+
+	result := &imports.Result{
+		Entity:    src.Entity,
+		Mode:      opts.Mode,
+		DryRun:    opts.DryRun,
+		TotalRows: len(records),
+		Errors:    []imports.RowError{},
+	}
+
+	// validate rows, keep the file line of each valid item
+	items := []*T{}
+	lines := []int{}
+	for _, record := range records {
+		item, err := src.Parse(record.Values)
+		if err == nil {
+			err = src.Validate(item)
+		}
+
+		if err != nil {
+			result.Errors = append(result.Errors, imports.RowError{Row: record.Line, Message: err.Error()})
+			continue
+		}
+
+		items = append(items, item)
+		lines = append(lines, record.Line)
+	}
+
+	result.InvalidRows = len(result.Errors)
+	result.ValidRows = len(items)
+	reportProgress(progress, result.InvalidRows, result.TotalRows)
+
+	// nothing would be saved anyway, a dry run still checks the valid rows
+	// against the database
+	if len(items) == 0 || (opts.Mode == imports.AllOrNothing && result.InvalidRows > 0 && !opts.DryRun) {
+		return result, nil
+	}
+
+	saved := 0
+	done := result.InvalidRows
+	err := src.Save(ctx, items, opts, func(index int, err error) {
+		done++
+		reportProgress(progress, done, result.TotalRows)
+
+		if err != nil {
+			result.Errors = append(result.Errors, imports.RowError{Row: lines[index], Message: err.Error()})
+			result.InvalidRows++
+			result.ValidRows--
+			return
+		}
+
+		saved++
+	})
+
+	if err != nil && !errors.Is(err, imports.ErrAborted) {
+		return nil, err
+	}
+
+	if err == nil && !opts.DryRun {
+		result.Imported = saved
+	}
+
+	sort.SliceStable(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Row < result.Errors[j].Row
+	})
+
+	return result, nil
+}
+
+func reportProgress(progress func(done, total int), done, total int) {
+	if progress != nil {
+		progress(done, total)
+	}
+}
@@ -0,0 +1,109 @@
+package importer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
+)
+
+// finished jobs are kept this long so clients can still read the result
+const jobRetention = 24 * time.Hour
+
+// JobStore keeps track of background imports in memory
+type JobStore struct {
+	mu   sync.RWMutex
+	jobs map[string]*imports.Job
+}
+
+func NewJobStore() *JobStore {
+	return &JobStore{jobs: map[string]*imports.Job{}}
+}
+
+// Start runs fn in the background and returns the created job
+func (s *JobStore) Start(entity string, total int, fn func(ctx context.Context, progress func(done, total int)) (*imports.Result, error)) *imports.Job {
+	job := &imports.Job{
+		ID:        newJobID(),
+		Entity:    entity,
+		Status:    imports.JobPending,
+		Total:     total,
+		CreatedAt: time.Now(),
+	}
+
+	s.mu.Lock()
+	s.prune()
+	s.jobs[job.ID] = job
+	snapshot := *job
+	s.mu.Unlock()
+
+	go func() {
+		s.update(job.ID, func(job *imports.Job) {
+			job.Status = imports.JobRunning
+		})
+
+		result, err := fn(context.Background(), func(done, total int) {
+			s.update(job.ID, func(job *imports.Job) {
+				job.Processed = done
+				job.Total = total
+			})
+		})
+
+		s.update(job.ID, func(job *imports.Job) {
+			now := time.Now()
+			job.FinishedAt = &now
+
+			if err != nil {
+				job.Status = imports.JobFailed
+				job.Error = err.Error()
+				return
+			}
+
+			job.Status = imports.JobCompleted
+			job.Processed = job.Total
+			job.Result = result
+		})
+	}()
+
+	return &snapshot
+}
+
+// Get returns a copy of the job, or nil when it does not exist
+func (s *JobStore) Get(id string) *imports.Job {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil
+	}
+
+	snapshot := *job
+	return &snapshot
+}
+
+func (s *JobStore) update(id string, fn func(job *imports.Job)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job, ok := s.jobs[id]; ok {
+		fn(job)
+	}
+}
+
+// prune removes finished jobs past the retention, caller must hold the lock
+func (s *JobStore) prune() {
+	for id, job := range s.jobs {
+		if job.IsFinished() && job.FinishedAt != nil && time.Since(*job.FinishedAt) > jobRetention {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+func newJobID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,66 @@
+package location
+
+import (
+	"errors"
+	"strings"
+
+	locationModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
+	productModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/product"
+)
+
+// validateCapabilities fills in the default capabilities and checks the
+// temperature range and the allowed hazmat classes
+func validateCapabilities(location *locationModel.Location) error {
+	if location.Capabilities == nil {
+		location.Capabilities = &locationModel.StorageCapabilities{}
+	}
+
+	capabilities := location.Capabilities
+	if capabilities.AcceptsFragile == nil {
+		acceptsFragile := true
+		capabilities.AcceptsFragile = &acceptsFragile
+	}
+
+	if capabilities.MinTemperature != nil && capabilities.MaxTemperature != nil && *capabilities.MinTemperature > *capabilities.MaxTemperature {
+		return errors.New("min_temperature must not be above max_temperature")
+	}
+
+	classes := make([]string, 0, len(capabilities.HazmatClasses))
+	seen := map[string]bool{}
+	for _, class := range capabilities.HazmatClasses {
+		class = strings.TrimSpace(class)
+		if !productModel.HazmatClassPattern.MatchString(class) {
+			return errors.New("hazmat_classes must contain UN hazard classes such as 3 or 2.1")
+		}
+
+		if !seen[class] {
+			seen[class] = true
+			classes = append(classes, class)
+		}
+	}
+	capabilities.HazmatClasses = classes
+
+	return nil
+}
+
+// validateLimits checks that the weight, volume and unit limits are not negative
+func validateLimits(location *locationModel.Location) error {
+	if location.Limits == nil {
+		location.Limits = &locationModel.Limits{}
+	}
+
+	limits := location.Limits
+	if limits.MaxWeight != nil && *limits.MaxWeight < 0 {
+		return errors.New("max_weight must not be negative")
+	}
+
+	if limits.MaxVolume != nil && *limits.MaxVolume < 0 {
+		return errors.New("max_volume must not be negative")
+	}
+
+	if limits.MaxUnits != nil && *limits.MaxUnits < 0 {
+		return errors.New("max_units must not be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package location
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
+	locationModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
+)
+
+// Generate expands the pattern of request into location codes and marks the
+// codes that are already taken. Unless dryRun is set the locations are
+// created in one transaction, either all of them or none.
+func (l *locationService) Generate(ctx context.Context, request *locationModel.GenerateRequest, dryRun bool) (*locationModel.GenerateResult, error) {
+	values, err := locationModel.ExpandPattern(request.Pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	// the parent or warehouse is the same for every location, it is resolved once
+	template := newGeneratedLocation(request, values[0])
+	prefix, err := l.hierarchyPrefix(ctx, template)
+	if err != nil {
+		return nil, err
+	}
+
+	generated := make([]*locationModel.Location, len(values))
+	codes := make([]string, len(values))
+	for i, value := range values {
+		location := newGeneratedLocation(request, value)
+		location.WarehouseID = template.WarehouseID
+		if prefix != "" {
+			if err := joinSegment(location, prefix); err != nil {
+				return nil, err
+			}
+		}
+
+		generated[i] = location
+		codes[i] = location.Code
+	}
+
+	// the defaults are checked once, they are the same for every location
+	if err := validatorCreateOrUpdateLocation(generated[0]); err != nil {
+		return nil, err
+	}
+
+	existing, err := l.repo.FindExistingCodes(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	taken := make(map[string]bool, len(existing))
+	for _, code := range existing {
+		taken[code] = true
+	}
+
+	result := &locationModel.GenerateResult{
+		Pattern: request.Pattern,
+		Total:   len(codes),
+		Codes:   make([]locationModel.GeneratedCode, 0, len(codes)),
+	}
+
+	seen := make(map[string]bool, len(codes))
+	locations := make([]*locationModel.Location, 0, len(codes))
+	for _, location := range generated {
+		// a pattern such as {1..2}{1..11} can repeat a code
+		exists := taken[location.Code] || seen[location.Code]
+		seen[location.Code] = true
+
+		result.Codes = append(result.Codes, locationModel.GeneratedCode{Code: location.Code, Exists: exists})
+		if exists {
+			result.Collisions++
+			continue
+		}
+
+		locations = append(locations, location)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	if result.Collisions > 0 && !request.SkipExisting {
+		return nil, errors.New("pattern collides with existing location codes")
+	}
+
+	if len(locations) == 0 {
+		return result, nil
+	}
+
+	var failure error
+	err = l.repo.CreateBatch(ctx, locations, imports.Options{Mode: imports.AllOrNothing}, func(index int, err error) {
+		if err != nil && failure == nil {
+			failure = fmt.Errorf("location %s: %w", locations[index].Code, err)
+		}
+	})
+	if err != nil {
+		if errors.Is(err, imports.ErrAborted) && failure != nil {
+			return nil, failure
+		}
+		return nil, err
+	}
+
+	result.Created = len(locations)
+	return result, nil
+}
+
+// newGeneratedLocation builds a location from an expanded value of the
+// pattern, which is its segment in a hierarchy and its code otherwise
+func newGeneratedLocation(request *locationModel.GenerateRequest, value string) *locationModel.Location {
+	location := &locationModel.Location{
+		WarehouseID:  request.WarehouseID,
+		ParentID:     request.ParentID,
+		Level:        request.Level,
+		Zone:         request.Zone,
+		Type:         request.Type,
+		Capacity:     request.Capacity,
+		Capabilities: request.Capabilities,
+		Limits:       request.Limits,
+	}
+
+	if request.IsHierarchical() {
+		location.Segment = value
+	} else {
+		location.Code = value
+	}
+
+	return location
+}
@@ -0,0 +1,157 @@
+package location
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	locationModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
+)
+
+// resolveHierarchy checks the parent or warehouse of a new location and builds
+// its full-path code from the code of the parent (or warehouse) and its segment
+func (l *locationService) resolveHierarchy(ctx context.Context, location *locationModel.Location) error {
+	prefix, err := l.hierarchyPrefix(ctx, location)
+	if err != nil || prefix == "" {
+		return err
+	}
+
+	return joinSegment(location, prefix)
+}
+
+// hierarchyPrefix checks the level and the parent or warehouse of a new
+// location and returns the code its segment is appended to, empty for a flat
+// location. The warehouse of a child is set to the one of its parent.
+func (l *locationService) hierarchyPrefix(ctx context.Context, location *locationModel.Location) (string, error) {
+	location.Segment = strings.ToUpper(strings.TrimSpace(location.Segment))
+
+	if location.Level != "" && !location.Level.IsValid() {
+		return "", errors.New("level must be zone, aisle, rack, level or bin")
+	}
+
+	prefix := ""
+	switch {
+	case location.ParentID != nil:
+		parent, err := l.repo.GetByID(ctx, *location.ParentID)
+		if err != nil {
+			return "", err
+		}
+
+		if parent == nil {
+			return "", errors.New("parent location not found")
+		}
+
+		if parent.Level == "" || parent.Segment == "" {
+			return "", errors.New("parent location is not part of a hierarchy")
+		}
+
+		if location.Level == "" || !location.Level.IsBelow(parent.Level) {
+			return "", errors.New("level must be below the level of the parent location")
+		}
+
+		// children always belong to the warehouse of their parent
+		location.WarehouseID = parent.WarehouseID
+		prefix = parent.Code
+	case location.WarehouseID != nil:
+		warehouse, err := l.warehouseRepo.GetByID(ctx, *location.WarehouseID)
+		if err != nil {
+			return "", err
+		}
+
+		if warehouse == nil {
+			return "", errors.New("warehouse not found")
+		}
+
+		if location.Level == "" {
+			return "", errors.New("level is required for a location in a warehouse")
+		}
+
+		prefix = warehouse.Code
+	default:
+		if location.Level != "" || location.Segment != "" {
+			return "", errors.New("warehouse_id or parent_id is required for a location in a hierarchy")
+		}
+
+		// a flat location keeps the code it was given
+		return "", nil
+	}
+
+	return prefix, nil
+}
+
+// joinSegment builds the full-path code of a location from the code of its
+// parent (or warehouse) and its segment
+func joinSegment(location *locationModel.Location, prefix string) error {
+	location.Segment = strings.ToUpper(strings.TrimSpace(location.Segment))
+	if location.Segment == "" {
+		return errors.New("segment is required for a location in a hierarchy")
+	}
+
+	if strings.Contains(location.Segment, locationModel.CodeSeparator) {
+		return errors.New("segment must not contain '-'")
+	}
+
+	location.Code = prefix + locationModel.CodeSeparator + location.Segment
+	return nil
+}
+
+// renameSegment rebuilds the code of an existing hierarchical location from a
+// new segment, an empty segment keeps the current one
+func renameSegment(location, existing *locationModel.Location) error {
+	location.Segment = strings.ToUpper(strings.TrimSpace(location.Segment))
+	if location.Segment == "" {
+		location.Segment = existing.Segment
+	}
+
+	if strings.Contains(location.Segment, locationModel.CodeSeparator) {
+		return errors.New("segment must not contain '-'")
+	}
+
+	prefix := strings.TrimSuffix(existing.Code, locationModel.CodeSeparator+existing.Segment)
+	location.Code = prefix + locationModel.CodeSeparator + location.Segment
+	return nil
+}
+
+// GetSubtree returns a location with all of its descendants, each node
+// carries the capacity rolled up from the bins below it
+func (l *locationService) GetSubtree(ctx context.Context, id int) (*locationModel.Node, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid location id")
+	}
+
+	locations, err := l.repo.GetSubtree(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, root := range locationModel.BuildTree(locations) {
+		if root.ID == id {
+			return root, nil
+		}
+	}
+
+	return nil, errors.New("location not found")
+}
+
+// GetWarehouseTree returns the location hierarchy of a warehouse
+func (l *locationService) GetWarehouseTree(ctx context.Context, warehouseID int) ([]*locationModel.Node, error) {
+	if warehouseID <= 0 {
+		return nil, errors.New("invalid warehouse id")
+	}
+
+	warehouse, err := l.warehouseRepo.GetByID(ctx, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if warehouse == nil {
+		return nil, errors.New("warehouse not found")
+	}
+
+	locations, err := l.repo.ListByWarehouse(ctx, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return locationModel.BuildTree(locations), nil
+}
@@ -0,0 +1,60 @@
+package location
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	locationModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
+	"ecosystem.garyle/service/pkg/utils/label"
+)
+
+// RenderLabels renders the labels of the requested locations ordered by code
+func (l *locationService) RenderLabels(ctx context.Context, request *locationModel.LabelRequest) ([]byte, error) {
+	if request.Format == "" {
+		request.Format = label.ZPL
+	}
+
+	if request.Format != label.ZPL && request.Format != label.PDF {
+		return nil, errors.New("format must be zpl or pdf")
+	}
+
+	if len(request.LocationIDs) == 0 && request.Zone == "" {
+		return nil, errors.New("location_ids or zone is required")
+	}
+
+	if len(request.LocationIDs) > locationModel.MaxLabels {
+		return nil, fmt.Errorf("at most %d labels can be printed at once", locationModel.MaxLabels)
+	}
+
+	template, err := label.Resolve(request.Template, request.CustomTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := l.repo.ListForLabels(ctx, request.LocationIDs, request.Zone, locationModel.MaxLabels+1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(locations) == 0 {
+		return nil, errors.New("location not found")
+	}
+
+	if len(locations) > locationModel.MaxLabels {
+		return nil, fmt.Errorf("at most %d labels can be printed at once", locationModel.MaxLabels)
+	}
+
+	labels := make([]label.Label, 0, len(locations))
+	for _, location := range locations {
+		labels = append(labels, location.Label())
+	}
+
+	var output bytes.Buffer
+	if err := label.Render(&output, request.Format, template, labels); err != nil {
+		return nil, err
+	}
+
+	return output.Bytes(), nil
+}
@@ -3,30 +3,52 @@ package location
 import (
 	"context"
 	"errors"
+	"strconv"
 
+	"ecosystem.garyle/service/internal/app/service/wms/master-data/importer"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
 	locationModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
 	locationRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/location"
+	warehouseRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/warehouse"
+	"ecosystem.garyle/service/pkg/utils/filter"
+	"ecosystem.garyle/service/pkg/utils/spreadsheet"
 )
 
 type LocationService interface {
 	Create(ctx context.Context, location *locationModel.Location) (*locationModel.Location, error)
 	GetByID(ctx context.Context, id int) (*locationModel.Location, error)
-	List(ctx context.Context, limit, page int) ([]*locationModel.Location, error)
-	Count(ctx context.Context) (int, error)
+	List(ctx context.Context, limit, page int, params *filter.Params) ([]*locationModel.Location, error)
+	Count(ctx context.Context, params *filter.Params) (int, error)
 	Update(ctx context.Context, location *locationModel.Location, id int) (*locationModel.Location, error)
 	Delete(ctx context.Context, id int) error
+	Import(ctx context.Context, records []spreadsheet.Record, opts imports.Options, progress func(done, total int)) (*imports.Result, error)
+	Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(location *locationModel.Location) error) error
+	GetSubtree(ctx context.Context, id int) (*locationModel.Node, error)
+	GetWarehouseTree(ctx context.Context, warehouseID int) ([]*locationModel.Node, error)
+	Generate(ctx context.Context, request *locationModel.GenerateRequest, dryRun bool) (*locationModel.GenerateResult, error)
+	RenderLabels(ctx context.Context, request *locationModel.LabelRequest) ([]byte, error)
+	SetStatus(ctx context.Context, id int, change *locationModel.StatusChange) (*locationModel.Location, error)
 }
 
 type locationService struct {
-	repo locationRepo.LocationRepository
+	repo          locationRepo.LocationRepository
+	warehouseRepo warehouseRepo.WarehouseRepository
 }
 
-func NewLocationService(repo locationRepo.LocationRepository) LocationService {
-	return &locationService{repo: repo}
+func NewLocationService(repo locationRepo.LocationRepository, warehouseRepo warehouseRepo.WarehouseRepository) LocationService {
+	return &locationService{
+		repo:          repo,
+		warehouseRepo: warehouseRepo,
+	}
 }
 
 // Create implements LocationService.
 func (l *locationService) Create(ctx context.Context, location *locationModel.Location) (*locationModel.Location, error) {
+	// place the location in the hierarchy, this builds the full-path code
+	if err := l.resolveHierarchy(ctx, location); err != nil {
+		return nil, err
+	}
+
 	// validate location
 	err := validatorCreateOrUpdateLocation(location)
 	if err != nil {
@@ -59,21 +81,30 @@ func validatorCreateOrUpdateLocation(location *locationModel.Location) error {
 		return errors.New("type is required")
 	}
 
-	if location.Capacity <= 0 {
+	if location.Capacity < 0 {
+		return errors.New("capacity must not be negative")
+	}
+
+	// zones, aisles, racks and levels may leave their capacity to their bins
+	if location.Capacity == 0 && (location.Level == "" || location.Level == locationModel.LevelBin) {
 		return errors.New("capacity is required")
 	}
 
-	return nil
+	if err := validateLimits(location); err != nil {
+		return err
+	}
+
+	return validateCapabilities(location)
 }
 
 // List implements LocationService.
-func (l *locationService) List(ctx context.Context, limit int, page int) ([]*locationModel.Location, error) {
-	return l.repo.List(ctx, limit, page)
+func (l *locationService) List(ctx context.Context, limit, page int, params *filter.Params) ([]*locationModel.Location, error) {
+	return l.repo.List(ctx, limit, page, params)
 }
 
 // Count implements LocationService.
-func (l *locationService) Count(ctx context.Context) (int, error) {
-	return l.repo.Count(ctx)
+func (l *locationService) Count(ctx context.Context, params *filter.Params) (int, error) {
+	return l.repo.Count(ctx, params)
 }
 
 // GetByID implements LocationService.
@@ -101,6 +132,38 @@ func (l *locationService) Update(ctx context.Context, location *locationModel.Lo
 		return nil, errors.New("location not found")
 	}
 
+	// the place in the hierarchy is fixed, only the own segment of the code can change
+	location.WarehouseID = existingLocation.WarehouseID
+	location.ParentID = existingLocation.ParentID
+	location.Level = existingLocation.Level
+	if existingLocation.Segment != "" {
+		if err := renameSegment(location, existingLocation); err != nil {
+			return nil, err
+		}
+	}
+
+	// capabilities left out of the request stay as they are
+	if location.Capabilities == nil {
+		location.Capabilities = existingLocation.Capabilities
+	}
+
+	// and so do the limits and the place on the floor map
+	if location.Limits == nil {
+		location.Limits = existingLocation.Limits
+	}
+
+	if location.Coordinates == nil {
+		location.Coordinates = existingLocation.Coordinates
+	}
+
+	if err := validateLimits(location); err != nil {
+		return nil, err
+	}
+
+	if err := validateCapabilities(location); err != nil {
+		return nil, err
+	}
+
 	return l.repo.Update(ctx, location, id)
 }
 
@@ -120,5 +183,75 @@ func (l *locationService) Delete(ctx context.Context, id int) error {
 		return errors.New("location not found")
 	}
 
+	children, err := l.repo.CountChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if children > 0 {
+		return errors.New("location still has child locations")
+	}
+
 	return l.repo.Delete(ctx, id)
 }
+
+// Import validates and saves location rows read from an uploaded file
+func (l *locationService) Import(ctx context.Context, records []spreadsheet.Record, opts imports.Options, progress func(done, total int)) (*imports.Result, error) {
+	return importer.Run(ctx, records, importer.Source[locationModel.Location]{
+		Entity:   "location",
+		Parse:    parseLocationRow,
+		Validate: validatorCreateOrUpdateLocation,
+		Save:     l.repo.CreateBatch,
+	}, opts, progress)
+}
+
+// parseLocationRow maps the columns of an import row to a location
+func parseLocationRow(values map[string]string) (*locationModel.Location, error) {
+	location := &locationModel.Location{
+		Code: values["code"],
+		Zone: values["zone"],
+		Type: values["type"],
+	}
+
+	if values["capacity"] != "" {
+		capacity, err := strconv.ParseFloat(values["capacity"], 64)
+		if err != nil {
+			return nil, errors.New("capacity must be a number")
+		}
+		location.Capacity = capacity
+	}
+
+	limits := &locationModel.Limits{}
+	for _, column := range []string{"max_weight", "max_volume"} {
+		if values[column] == "" {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(values[column], 64)
+		if err != nil {
+			return nil, errors.New(column + " must be a number")
+		}
+
+		if column == "max_weight" {
+			limits.MaxWeight = &value
+		} else {
+			limits.MaxVolume = &value
+		}
+	}
+
+	if values["max_units"] != "" {
+		maxUnits, err := strconv.Atoi(values["max_units"])
+		if err != nil {
+			return nil, errors.New("max_units must be a whole number")
+		}
+		limits.MaxUnits = &maxUnits
+	}
+	location.Limits = limits
+
+	return location, nil
+}
+
+// Export streams the location rows matching the list filters to fn
+func (l *locationService) Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(location *locationModel.Location) error) error {
+	return l.repo.Export(ctx, params, includeDeleted, fn)
+}
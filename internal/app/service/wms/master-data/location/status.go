@@ -0,0 +1,68 @@
+package location
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	locationModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
+)
+
+// SetStatus blocks or unblocks a location. Setting it active again clears
+// the reason and expiry of the previous block.
+func (l *locationService) SetStatus(ctx context.Context, id int, change *locationModel.StatusChange) (*locationModel.Location, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid location id")
+	}
+
+	if err := validateStatusChange(change); err != nil {
+		return nil, err
+	}
+
+	location, err := l.repo.UpdateStatus(ctx, id, change)
+	if err != nil {
+		return nil, err
+	}
+
+	if location == nil {
+		return nil, errors.New("location not found")
+	}
+
+	return location, nil
+}
+
+func validateStatusChange(change *locationModel.StatusChange) error {
+	if !change.Status.IsValid() {
+		return errors.New("status must be active, blocked_inbound, blocked_outbound, blocked or under_count")
+	}
+
+	change.SetBy = strings.TrimSpace(change.SetBy)
+	if change.SetBy == "" {
+		return errors.New("set_by is required")
+	}
+
+	now := time.Now()
+	change.SetAt = &now
+
+	if change.Status == locationModel.StatusActive {
+		change.Reason = ""
+		change.Note = ""
+		change.ExpiresAt = nil
+		return nil
+	}
+
+	if change.Reason == "" && change.Status == locationModel.StatusUnderCount {
+		change.Reason = locationModel.ReasonCounting
+	}
+
+	if !change.Reason.IsValid() {
+		return errors.New("reason must be damage, maintenance, counting, quality or other")
+	}
+
+	if change.ExpiresAt != nil && !change.ExpiresAt.After(now) {
+		return errors.New("expires_at must be in the future")
+	}
+
+	return nil
+}
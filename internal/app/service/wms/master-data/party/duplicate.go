@@ -0,0 +1,138 @@
+package party
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	partyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
+	partyRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/party"
+)
+
+// DuplicateService finds and merges duplicate parties. It is embedded by the
+// supplier and customer services.
+type DuplicateService interface {
+	ListDuplicates(ctx context.Context, threshold float64, limit int) ([]partyModel.DuplicatePair, error)
+	FindDuplicates(ctx context.Context, id int, threshold float64) ([]partyModel.DuplicateMatch, error)
+	MatchProfile(ctx context.Context, profile partyModel.Profile, threshold float64) ([]partyModel.DuplicateMatch, error)
+	Merge(ctx context.Context, survivorID int, request *partyModel.MergeRequest) (*partyModel.MergeRecord, error)
+	ListMerges(ctx context.Context, survivorID int) ([]*partyModel.MergeRecord, error)
+}
+
+type duplicateService struct {
+	mergeRepo partyRepo.MergeRepository
+	entity    string
+	load      func(ctx context.Context, id int) (interface{}, error)
+}
+
+// NewDuplicateService creates a duplicate service, load returns a party with
+// its details or an error such as "supplier not found"
+func NewDuplicateService(mergeRepo partyRepo.MergeRepository, entity string, load func(ctx context.Context, id int) (interface{}, error)) DuplicateService {
+	return &duplicateService{mergeRepo: mergeRepo, entity: entity, load: load}
+}
+
+// MergeValidationErrors lists the merge errors of a party entity that are caused by the request
+func MergeValidationErrors(entity string) []string {
+	return []string{
+		"duplicate_id is required",
+		"merged_by is required",
+		"a " + entity + " cannot be merged into itself",
+		"duplicate " + entity + " not found",
+	}
+}
+
+// ListDuplicates implements DuplicateService.
+func (s *duplicateService) ListDuplicates(ctx context.Context, threshold float64, limit int) ([]partyModel.DuplicatePair, error) {
+	profiles, err := s.mergeRepo.ListProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return partyModel.FindPairs(profiles, threshold, limit), nil
+}
+
+// FindDuplicates implements DuplicateService.
+func (s *duplicateService) FindDuplicates(ctx context.Context, id int, threshold float64) ([]partyModel.DuplicateMatch, error) {
+	profiles, err := s.mergeRepo.ListProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profile := range profiles {
+		if profile.ID == id {
+			return partyModel.FindMatches(profile, profiles, threshold), nil
+		}
+	}
+
+	return nil, errors.New(s.entity + " not found")
+}
+
+// MatchProfile implements DuplicateService.
+func (s *duplicateService) MatchProfile(ctx context.Context, profile partyModel.Profile, threshold float64) ([]partyModel.DuplicateMatch, error) {
+	profiles, err := s.mergeRepo.ListProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return partyModel.FindMatches(profile, profiles, threshold), nil
+}
+
+// Merge implements DuplicateService. The survivor keeps its own fields, the
+// duplicate is kept as a snapshot in the audit record.
+func (s *duplicateService) Merge(ctx context.Context, survivorID int, request *partyModel.MergeRequest) (*partyModel.MergeRecord, error) {
+	request.MergedBy = strings.TrimSpace(request.MergedBy)
+	request.Reason = strings.TrimSpace(request.Reason)
+
+	if request.DuplicateID <= 0 {
+		return nil, errors.New("duplicate_id is required")
+	}
+
+	if request.DuplicateID == survivorID {
+		return nil, errors.New("a " + s.entity + " cannot be merged into itself")
+	}
+
+	if request.MergedBy == "" {
+		return nil, errors.New("merged_by is required")
+	}
+
+	if _, err := s.load(ctx, survivorID); err != nil {
+		return nil, err
+	}
+
+	duplicate, err := s.load(ctx, request.DuplicateID)
+	if err != nil {
+		if err.Error() == s.entity+" not found" {
+			return nil, errors.New("duplicate " + s.entity + " not found")
+		}
+		return nil, err
+	}
+
+	snapshot, err := json.Marshal(duplicate)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &partyModel.MergeRecord{
+		SurvivorID:  survivorID,
+		DuplicateID: request.DuplicateID,
+		MergedBy:    request.MergedBy,
+		Reason:      request.Reason,
+		Snapshot:    snapshot,
+	}
+
+	if err := s.mergeRepo.Merge(ctx, record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// ListMerges implements DuplicateService.
+func (s *duplicateService) ListMerges(ctx context.Context, survivorID int) ([]*partyModel.MergeRecord, error) {
+	if _, err := s.load(ctx, survivorID); err != nil {
+		return nil, err
+	}
+
+	return s.mergeRepo.ListMerges(ctx, survivorID)
+}
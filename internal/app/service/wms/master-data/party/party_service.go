@@ -0,0 +1,157 @@
+package party
+
+import (
+	"context"
+	"errors"
+
+	partyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
+	partyRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/party"
+)
+
+// DetailService manages the addresses and contact persons of a party. It is
+// embedded by the supplier and customer services.
+type DetailService interface {
+	ListAddresses(ctx context.Context, ownerID int) ([]*partyModel.Address, error)
+	CreateAddress(ctx context.Context, ownerID int, address *partyModel.Address) (*partyModel.Address, error)
+	UpdateAddress(ctx context.Context, ownerID, id int, address *partyModel.Address) (*partyModel.Address, error)
+	DeleteAddress(ctx context.Context, ownerID, id int) error
+	ListContacts(ctx context.Context, ownerID int) ([]*partyModel.Contact, error)
+	CreateContact(ctx context.Context, ownerID int, contact *partyModel.Contact) (*partyModel.Contact, error)
+	UpdateContact(ctx context.Context, ownerID, id int, contact *partyModel.Contact) (*partyModel.Contact, error)
+	DeleteContact(ctx context.Context, ownerID, id int) error
+}
+
+type detailService struct {
+	detailRepo partyRepo.DetailRepository
+	checkOwner func(ctx context.Context, id int) error
+}
+
+// NewDetailService creates a detail service, checkOwner returns an error such
+// as "supplier not found" when the party does not exist
+func NewDetailService(detailRepo partyRepo.DetailRepository, checkOwner func(ctx context.Context, id int) error) DetailService {
+	return &detailService{detailRepo: detailRepo, checkOwner: checkOwner}
+}
+
+// ListAddresses implements DetailService.
+func (s *detailService) ListAddresses(ctx context.Context, ownerID int) ([]*partyModel.Address, error) {
+	if err := s.checkOwner(ctx, ownerID); err != nil {
+		return nil, err
+	}
+
+	return s.detailRepo.ListAddresses(ctx, ownerID)
+}
+
+// CreateAddress implements DetailService.
+func (s *detailService) CreateAddress(ctx context.Context, ownerID int, address *partyModel.Address) (*partyModel.Address, error) {
+	if err := ValidateAddress(address); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwner(ctx, ownerID); err != nil {
+		return nil, err
+	}
+
+	return s.detailRepo.CreateAddress(ctx, ownerID, address)
+}
+
+// UpdateAddress implements DetailService.
+func (s *detailService) UpdateAddress(ctx context.Context, ownerID, id int, address *partyModel.Address) (*partyModel.Address, error) {
+	if err := ValidateAddress(address); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwner(ctx, ownerID); err != nil {
+		return nil, err
+	}
+
+	updatedAddress, err := s.detailRepo.UpdateAddress(ctx, ownerID, id, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if updatedAddress == nil {
+		return nil, errors.New("address not found")
+	}
+
+	return updatedAddress, nil
+}
+
+// DeleteAddress implements DetailService.
+func (s *detailService) DeleteAddress(ctx context.Context, ownerID, id int) error {
+	if err := s.checkOwner(ctx, ownerID); err != nil {
+		return err
+	}
+
+	address, err := s.detailRepo.GetAddress(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+
+	if address == nil {
+		return errors.New("address not found")
+	}
+
+	return s.detailRepo.DeleteAddress(ctx, ownerID, id)
+}
+
+// ListContacts implements DetailService.
+func (s *detailService) ListContacts(ctx context.Context, ownerID int) ([]*partyModel.Contact, error) {
+	if err := s.checkOwner(ctx, ownerID); err != nil {
+		return nil, err
+	}
+
+	return s.detailRepo.ListContacts(ctx, ownerID)
+}
+
+// CreateContact implements DetailService.
+func (s *detailService) CreateContact(ctx context.Context, ownerID int, contact *partyModel.Contact) (*partyModel.Contact, error) {
+	if err := ValidateContact(contact); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwner(ctx, ownerID); err != nil {
+		return nil, err
+	}
+
+	return s.detailRepo.CreateContact(ctx, ownerID, contact)
+}
+
+// UpdateContact implements DetailService.
+func (s *detailService) UpdateContact(ctx context.Context, ownerID, id int, contact *partyModel.Contact) (*partyModel.Contact, error) {
+	if err := ValidateContact(contact); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwner(ctx, ownerID); err != nil {
+		return nil, err
+	}
+
+	updatedContact, err := s.detailRepo.UpdateContact(ctx, ownerID, id, contact)
+	if err != nil {
+		return nil, err
+	}
+
+	if updatedContact == nil {
+		return nil, errors.New("contact not found")
+	}
+
+	return updatedContact, nil
+}
+
+// DeleteContact implements DetailService.
+func (s *detailService) DeleteContact(ctx context.Context, ownerID, id int) error {
+	if err := s.checkOwner(ctx, ownerID); err != nil {
+		return err
+	}
+
+	contact, err := s.detailRepo.GetContact(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+
+	if contact == nil {
+		return errors.New("contact not found")
+	}
+
+	return s.detailRepo.DeleteContact(ctx, ownerID, id)
+}
@@ -0,0 +1,33 @@
+package party
+
+import (
+	"context"
+	"errors"
+
+	partyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
+	partyRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/party"
+)
+
+// TaxIDTakenError is returned by CheckTaxID, e.g. "tax_id is already used by another supplier"
+func TaxIDTakenError(entity string) string {
+	return "tax_id is already used by another " + entity
+}
+
+// CheckTaxID makes sure no other active party of the entity uses the tax ID.
+// id is the party being updated, or 0 for a new one.
+func CheckTaxID(ctx context.Context, repo partyRepo.RegistrationRepository, entity string, registration partyModel.Registration, id int) error {
+	if registration.TaxID == "" {
+		return nil
+	}
+
+	taken, err := repo.TaxIDTaken(ctx, registration.TaxCountry, registration.TaxID, id)
+	if err != nil {
+		return err
+	}
+
+	if taken {
+		return errors.New(TaxIDTakenError(entity))
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package party
+
+import (
+	"errors"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	partyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
+)
+
+var (
+	// ISO 3166-1 alpha-2 country codes, e.g. ID or SG
+	countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+	// postal codes differ per country, only the characters and length are checked
+	postalCodePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 -]{1,8}[A-Za-z0-9]$`)
+	// an optional leading + followed by digits, spaces, dashes, dots or brackets
+	phonePattern = regexp.MustCompile(`^\+?[0-9 ().-]+$`)
+)
+
+// ValidationErrors lists the errors returned by ValidateAddress, ValidateContact
+// and ValidateRegistration
+var ValidationErrors = []string{
+	"address type must be billing, shipping or pickup",
+	"street is required",
+	"city is required",
+	"country must be an ISO 3166-1 alpha-2 code such as ID",
+	"postal_code must be 3 to 10 letters, digits, spaces or dashes",
+	"latitude and longitude must be set together",
+	"latitude must be between -90 and 90",
+	"longitude must be between -180 and 180",
+	"contact name is required",
+	"contact needs a phone or an email",
+	"phone must be a valid phone number",
+	"email must be a valid email address",
+	"tax_country must be an ISO 3166-1 alpha-2 code such as ID",
+	"tax_id does not match the tax ID format of tax_country",
+	"registration_number must be 3 to 50 letters, digits, spaces, dots, slashes or dashes",
+	"payment_terms must be COD, CIA, EOM or NET followed by the number of days, e.g. NET30",
+	"currency must be an ISO 4217 code such as IDR",
+}
+
+// IsValidationError checks if err is one of ValidationErrors
+func IsValidationError(err error) bool {
+	for _, validationError := range ValidationErrors {
+		if err.Error() == validationError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateAddress trims and checks an address
+func ValidateAddress(address *partyModel.Address) error {
+	address.Label = strings.TrimSpace(address.Label)
+	address.Street = strings.TrimSpace(address.Street)
+	address.City = strings.TrimSpace(address.City)
+	address.Province = strings.TrimSpace(address.Province)
+	address.PostalCode = strings.TrimSpace(address.PostalCode)
+	address.Country = strings.ToUpper(strings.TrimSpace(address.Country))
+
+	if !address.Type.IsValid() {
+		return errors.New("address type must be billing, shipping or pickup")
+	}
+
+	if address.Street == "" {
+		return errors.New("street is required")
+	}
+
+	if address.City == "" {
+		return errors.New("city is required")
+	}
+
+	if !countryPattern.MatchString(address.Country) {
+		return errors.New("country must be an ISO 3166-1 alpha-2 code such as ID")
+	}
+
+	if address.PostalCode != "" && !postalCodePattern.MatchString(address.PostalCode) {
+		return errors.New("postal_code must be 3 to 10 letters, digits, spaces or dashes")
+	}
+
+	if (address.Latitude == nil) != (address.Longitude == nil) {
+		return errors.New("latitude and longitude must be set together")
+	}
+
+	if address.Latitude != nil && (*address.Latitude < -90 || *address.Latitude > 90) {
+		return errors.New("latitude must be between -90 and 90")
+	}
+
+	if address.Longitude != nil && (*address.Longitude < -180 || *address.Longitude > 180) {
+		return errors.New("longitude must be between -180 and 180")
+	}
+
+	return nil
+}
+
+// ValidateContact trims and checks a contact person
+func ValidateContact(contact *partyModel.Contact) error {
+	contact.Name = strings.TrimSpace(contact.Name)
+	contact.Role = strings.TrimSpace(contact.Role)
+	contact.Phone = strings.TrimSpace(contact.Phone)
+	contact.Email = strings.TrimSpace(contact.Email)
+
+	if contact.Name == "" {
+		return errors.New("contact name is required")
+	}
+
+	if contact.Phone == "" && contact.Email == "" {
+		return errors.New("contact needs a phone or an email")
+	}
+
+	if contact.Phone != "" && !isPhone(contact.Phone) {
+		return errors.New("phone must be a valid phone number")
+	}
+
+	if contact.Email != "" && !isEmail(contact.Email) {
+		return errors.New("email must be a valid email address")
+	}
+
+	return nil
+}
+
+// ValidateDetails checks the addresses and contacts sent with a new party
+func ValidateDetails(addresses []*partyModel.Address, contacts []*partyModel.Contact) error {
+	for _, address := range addresses {
+		if err := ValidateAddress(address); err != nil {
+			return err
+		}
+	}
+
+	for _, contact := range contacts {
+		if err := ValidateContact(contact); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateRegistration normalizes and checks the tax and registration data of
+// a party, every field is optional
+func ValidateRegistration(registration *partyModel.Registration) error {
+	registration.TaxCountry = strings.ToUpper(strings.TrimSpace(registration.TaxCountry))
+	registration.TaxID = partyModel.NormalizeTaxID(registration.TaxID)
+	registration.RegistrationNumber = strings.TrimSpace(registration.RegistrationNumber)
+	registration.PaymentTerms = strings.ToUpper(strings.TrimSpace(registration.PaymentTerms))
+	registration.Currency = strings.ToUpper(strings.TrimSpace(registration.Currency))
+
+	if registration.TaxID != "" && registration.TaxCountry == "" {
+		registration.TaxCountry = partyModel.DefaultTaxCountry
+	}
+
+	if registration.TaxCountry != "" && !countryPattern.MatchString(registration.TaxCountry) {
+		return errors.New("tax_country must be an ISO 3166-1 alpha-2 code such as ID")
+	}
+
+	if registration.TaxID != "" && !partyModel.ValidTaxID(registration.TaxCountry, registration.TaxID) {
+		return errors.New("tax_id does not match the tax ID format of tax_country")
+	}
+
+	if registration.RegistrationNumber != "" && !partyModel.RegistrationNumberPattern.MatchString(registration.RegistrationNumber) {
+		return errors.New("registration_number must be 3 to 50 letters, digits, spaces, dots, slashes or dashes")
+	}
+
+	if registration.PaymentTerms != "" && !partyModel.PaymentTermsPattern.MatchString(registration.PaymentTerms) {
+		return errors.New("payment_terms must be COD, CIA, EOM or NET followed by the number of days, e.g. NET30")
+	}
+
+	if registration.Currency != "" && !partyModel.CurrencyPattern.MatchString(registration.Currency) {
+		return errors.New("currency must be an ISO 4217 code such as IDR")
+	}
+
+	return nil
+}
+
+// isPhone accepts 7 to 15 digits as in E.164, with common separators
+func isPhone(phone string) bool {
+	if !phonePattern.MatchString(phone) {
+		return false
+	}
+
+	digits := 0
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+
+	return digits >= 7 && digits <= 15
+}
+
+// isEmail accepts a bare address such as name@example.com
+func isEmail(email string) bool {
+	parsed, err := mail.ParseAddress(email)
+	if err != nil || parsed.Address != email || parsed.Name != "" {
+		return false
+	}
+
+	domain := email[strings.LastIndex(email, "@")+1:]
+	return strings.Contains(domain, ".") && !strings.HasPrefix(domain, ".") && !strings.HasSuffix(domain, ".")
+}
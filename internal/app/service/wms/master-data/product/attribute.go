@@ -0,0 +1,48 @@
+package product
+
+import (
+	"context"
+	"errors"
+
+	categoryModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/category"
+	productModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/product"
+)
+
+// validateAttributes checks the attributes of a product against the schema of
+// its category. A variant is checked with the attributes and, when it has
+// none of its own, the category of its parent.
+func (s *productService) validateAttributes(ctx context.Context, product *productModel.Product, parent *productModel.Product) error {
+	categoryID := product.CategoryID
+	values := map[string]interface{}{}
+	if parent != nil {
+		if categoryID == nil {
+			categoryID = parent.CategoryID
+		}
+
+		for name, value := range parent.Attributes {
+			values[name] = value
+		}
+	}
+
+	for name, value := range product.Attributes {
+		values[name] = value
+	}
+
+	if categoryID == nil {
+		if len(values) > 0 {
+			return errors.New("attributes require a category")
+		}
+		return nil
+	}
+
+	path, err := s.categoryRepo.ListAncestors(ctx, *categoryID)
+	if err != nil {
+		return err
+	}
+
+	if len(path) == 0 {
+		return errors.New("category not found")
+	}
+
+	return categoryModel.ValidateValues(categoryModel.Schema(path), values)
+}
@@ -2,32 +2,45 @@ package product
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strconv"
 
+	"ecosystem.garyle/service/internal/app/service/wms/master-data/importer"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
 	productModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/product"
+	categoryRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/category"
 	productRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/product"
+	"ecosystem.garyle/service/pkg/utils/filter"
+	"ecosystem.garyle/service/pkg/utils/spreadsheet"
 )
 
 type ProductService interface {
 	Create(ctx context.Context, product *productModel.Product) (*productModel.Product, error)
 	GetByID(ctx context.Context, id int) (*productModel.Product, error)
-	List(ctx context.Context, limit, page int) ([]*productModel.Product, error)
-	Count(ctx context.Context) (int, error)
+	List(ctx context.Context, limit, page int, params *filter.Params) ([]*productModel.Product, error)
+	Count(ctx context.Context, params *filter.Params) (int, error)
 	UpdateByID(ctx context.Context, product *productModel.Product, id int) error
 	DeleteByID(ctx context.Context, id int) error
+	Import(ctx context.Context, records []spreadsheet.Record, opts imports.Options, progress func(done, total int)) (*imports.Result, error)
+	Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(product *productModel.Product) error) error
+	GenerateVariants(ctx context.Context, parentID int, attributes productModel.VariantAttributes) ([]*productModel.Product, error)
+	ListVariants(ctx context.Context, parentID int) ([]*productModel.Product, error)
+	AttachVariants(ctx context.Context, products []*productModel.Product) error
 }
 
 type productService struct {
-	productRepo productRepo.ProductRepository
+	productRepo  productRepo.ProductRepository
+	categoryRepo categoryRepo.CategoryRepository
 }
 
-func NewProductService(productRepo productRepo.ProductRepository) ProductService {
-	return &productService{productRepo: productRepo}
+func NewProductService(productRepo productRepo.ProductRepository, categoryRepo categoryRepo.CategoryRepository) ProductService {
+	return &productService{productRepo: productRepo, categoryRepo: categoryRepo}
 }
 
 func (s *productService) Create(ctx context.Context, product *productModel.Product) (*productModel.Product, error) {
 	// validate product
-	err := validateProduct(product)
+	err := s.validateNewProduct(ctx, product)
 	if err != nil {
 		return nil, err
 	}
@@ -45,6 +58,40 @@ func (s *productService) Create(ctx context.Context, product *productModel.Produ
 	return s.productRepo.Create(ctx, product)
 }
 
+// validateNewProduct checks a product that is created or imported
+func (s *productService) validateNewProduct(ctx context.Context, product *productModel.Product) error {
+	if err := validateProduct(product); err != nil {
+		return err
+	}
+
+	// a variant must belong to a parent product that is not a variant itself
+	var parent *productModel.Product
+	if product.ParentID != nil {
+		var err error
+		parent, err = s.productRepo.GetByID(ctx, *product.ParentID)
+		if err != nil {
+			return err
+		}
+
+		if parent == nil {
+			return errors.New("parent product not found")
+		}
+
+		if parent.IsVariant() {
+			return errors.New("parent product is a variant")
+		}
+	}
+
+	if err := s.validateAttributes(ctx, product, parent); err != nil {
+		return err
+	}
+
+	// attribute definitions are managed through variant generation
+	product.VariantAttributes = nil
+
+	return nil
+}
+
 // validate product
 func validateProduct(product *productModel.Product) error {
 	if product.Sku == "" {
@@ -67,7 +114,7 @@ func validateProduct(product *productModel.Product) error {
 		return errors.New("dimension is required")
 	}
 
-	return nil
+	return validateStorageConditions(&product.Storage)
 }
 
 func (s *productService) GetByID(ctx context.Context, id int) (*productModel.Product, error) {
@@ -78,12 +125,12 @@ func (s *productService) GetByID(ctx context.Context, id int) (*productModel.Pro
 	return s.productRepo.GetByID(ctx, id)
 }
 
-func (s *productService) List(ctx context.Context, limit, page int) ([]*productModel.Product, error) {
-	return s.productRepo.List(ctx, limit, page)
+func (s *productService) List(ctx context.Context, limit, page int, params *filter.Params) ([]*productModel.Product, error) {
+	return s.productRepo.List(ctx, limit, page, params)
 }
 
-func (s *productService) Count(ctx context.Context) (int, error) {
-	return s.productRepo.Count(ctx)
+func (s *productService) Count(ctx context.Context, params *filter.Params) (int, error) {
+	return s.productRepo.Count(ctx, params)
 }
 
 func (s *productService) UpdateByID(ctx context.Context, product *productModel.Product, id int) error {
@@ -103,6 +150,24 @@ func (s *productService) UpdateByID(ctx context.Context, product *productModel.P
 		return errors.New("product not found")
 	}
 
+	// attributes that are left out keep their current values
+	check := *product
+	if check.Attributes == nil {
+		check.Attributes = existingProduct.Attributes
+	}
+
+	var parent *productModel.Product
+	if existingProduct.ParentID != nil {
+		parent, err = s.productRepo.GetByID(ctx, *existingProduct.ParentID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := s.validateAttributes(ctx, &check, parent); err != nil {
+		return err
+	}
+
 	return s.productRepo.UpdateByID(ctx, product, id)
 }
 
@@ -123,3 +188,108 @@ func (s *productService) DeleteByID(ctx context.Context, id int) error {
 
 	return s.productRepo.DeleteByID(ctx, id)
 }
+
+// Import validates and saves product rows read from an uploaded file. The
+// rows are validated like on create, including their parent and attributes.
+func (s *productService) Import(ctx context.Context, records []spreadsheet.Record, opts imports.Options, progress func(done, total int)) (*imports.Result, error) {
+	return importer.Run(ctx, records, importer.Source[productModel.Product]{
+		Entity: "product",
+		Parse:  parseProductRow,
+		Validate: func(product *productModel.Product) error {
+			return s.validateNewProduct(ctx, product)
+		},
+		Save: s.productRepo.CreateBatch,
+	}, opts, progress)
+}
+
+// parseProductRow maps the columns of an import row to a product
+func parseProductRow(values map[string]string) (*productModel.Product, error) {
+	product := &productModel.Product{
+		Sku:         values["sku"],
+		Name:        values["name"],
+		Description: values["description"],
+		Unit:        values["unit"],
+		Dimension:   values["dimension"],
+		Storage: productModel.StorageConditions{
+			HazmatClass: values["hazmat_class"],
+			UNNumber:    values["un_number"],
+		},
+	}
+
+	if values["weight"] != "" {
+		weight, err := strconv.ParseFloat(values["weight"], 64)
+		if err != nil {
+			return nil, errors.New("weight must be a number")
+		}
+		product.Weight = weight
+	}
+
+	if values["is_batch_tracked"] != "" {
+		isBatchTracked, err := strconv.ParseBool(values["is_batch_tracked"])
+		if err != nil {
+			return nil, errors.New("is_batch_tracked must be true or false")
+		}
+		product.IsBatchTracked = isBatchTracked
+	}
+
+	if values["min_temperature"] != "" {
+		minTemperature, err := strconv.ParseFloat(values["min_temperature"], 64)
+		if err != nil {
+			return nil, errors.New("min_temperature must be a number")
+		}
+		product.Storage.MinTemperature = &minTemperature
+	}
+
+	if values["max_temperature"] != "" {
+		maxTemperature, err := strconv.ParseFloat(values["max_temperature"], 64)
+		if err != nil {
+			return nil, errors.New("max_temperature must be a number")
+		}
+		product.Storage.MaxTemperature = &maxTemperature
+	}
+
+	if values["is_stackable"] != "" {
+		isStackable, err := strconv.ParseBool(values["is_stackable"])
+		if err != nil {
+			return nil, errors.New("is_stackable must be true or false")
+		}
+		product.Storage.IsStackable = &isStackable
+	}
+
+	if values["is_fragile"] != "" {
+		isFragile, err := strconv.ParseBool(values["is_fragile"])
+		if err != nil {
+			return nil, errors.New("is_fragile must be true or false")
+		}
+		product.Storage.IsFragile = isFragile
+	}
+
+	if values["parent_id"] != "" {
+		parentID, err := strconv.Atoi(values["parent_id"])
+		if err != nil || parentID <= 0 {
+			return nil, errors.New("parent_id must be a positive integer")
+		}
+		product.ParentID = &parentID
+	}
+
+	if values["category_id"] != "" {
+		categoryID, err := strconv.Atoi(values["category_id"])
+		if err != nil || categoryID <= 0 {
+			return nil, errors.New("category_id must be a positive integer")
+		}
+		product.CategoryID = &categoryID
+	}
+
+	if values["attributes"] != "" {
+		if err := json.Unmarshal([]byte(values["attributes"]), &product.Attributes); err != nil {
+			return nil, errors.New("attributes must be a JSON object")
+		}
+	}
+
+	return product, nil
+}
+
+// Export streams the product rows matching the list filters to fn
+func (s *productService) Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(product *productModel.Product) error) error {
+	return s.productRepo.Export(ctx, params, includeDeleted, fn)
+}
@@ -0,0 +1,48 @@
+package product
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	productModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/product"
+)
+
+var unNumberPattern = regexp.MustCompile(`^UN[0-9]{4}$`)
+
+// validateStorageConditions fills in the defaults, normalizes the UN number and checks that the
+// temperature range and hazmat classification are consistent
+func validateStorageConditions(storage *productModel.StorageConditions) error {
+	if storage.MinTemperature != nil && storage.MaxTemperature != nil && *storage.MinTemperature > *storage.MaxTemperature {
+		return errors.New("min_temperature must not be above max_temperature")
+	}
+
+	if storage.IsStackable == nil {
+		stackable := true
+		storage.IsStackable = &stackable
+	}
+
+	storage.HazmatClass = strings.TrimSpace(storage.HazmatClass)
+	storage.UNNumber = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(storage.UNNumber), " ", ""))
+	if storage.UNNumber != "" && !strings.HasPrefix(storage.UNNumber, "UN") {
+		storage.UNNumber = "UN" + storage.UNNumber
+	}
+
+	if storage.HazmatClass != "" && !productModel.HazmatClassPattern.MatchString(storage.HazmatClass) {
+		return errors.New("hazmat_class must be a UN hazard class such as 3 or 2.1")
+	}
+
+	if storage.UNNumber != "" && !unNumberPattern.MatchString(storage.UNNumber) {
+		return errors.New("un_number must be UN followed by 4 digits")
+	}
+
+	if storage.HazmatClass != "" && storage.UNNumber == "" {
+		return errors.New("un_number is required for hazardous products")
+	}
+
+	if storage.UNNumber != "" && storage.HazmatClass == "" {
+		return errors.New("hazmat_class is required when un_number is set")
+	}
+
+	return nil
+}
@@ -0,0 +1,259 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+
+	productModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/product"
+)
+
+// maxVariantCombinations limits how many variants one generation may create
+const maxVariantCombinations = 1000
+
+// GenerateVariants stores the attribute definitions of a parent product and
+// creates a variant for every combination of attribute values that does not
+// exist yet. Variants get the unit, weight and dimension of the parent, the
+// description and category are shared with the parent.
+func (s *productService) GenerateVariants(ctx context.Context, parentID int, attributes productModel.VariantAttributes) ([]*productModel.Product, error) {
+	if parentID <= 0 {
+		return nil, errors.New("invalid product id")
+	}
+
+	attributes, err := normalizeVariantAttributes(attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := s.productRepo.GetByID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if parent == nil {
+		return nil, errors.New("product not found")
+	}
+
+	if parent.IsVariant() {
+		return nil, errors.New("variants cannot have variants")
+	}
+
+	existing, err := s.productRepo.ListVariants(ctx, []int{parentID})
+	if err != nil {
+		return nil, err
+	}
+
+	// existing variants keep their sku, so new values may be added to an
+	// attribute but the attributes themselves cannot change
+	if len(existing) > 0 && !sameAttributeNames(parent.VariantAttributes, attributes) {
+		return nil, errors.New("attribute names cannot change once variants exist")
+	}
+
+	existingKeys := map[string]bool{}
+	for _, variant := range existing {
+		existingKeys[variantKey(variant.VariantValues)] = true
+	}
+
+	variants := []*productModel.Product{}
+	for _, values := range variantCombinations(attributes) {
+		if existingKeys[variantKey(values)] {
+			continue
+		}
+
+		variants = append(variants, newVariant(parent, attributes, values))
+	}
+
+	if err := s.productRepo.SaveVariants(ctx, parentID, attributes, variants); err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+// ListVariants returns the variants of a parent product
+func (s *productService) ListVariants(ctx context.Context, parentID int) ([]*productModel.Product, error) {
+	if parentID <= 0 {
+		return nil, errors.New("invalid product id")
+	}
+
+	parent, err := s.productRepo.GetByID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if parent == nil {
+		return nil, errors.New("product not found")
+	}
+
+	return s.productRepo.ListVariants(ctx, []int{parentID})
+}
+
+// AttachVariants loads the variants of the given products into their Variants field
+func (s *productService) AttachVariants(ctx context.Context, products []*productModel.Product) error {
+	ids := make([]int, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	variants, err := s.productRepo.ListVariants(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	byParent := map[int][]*productModel.Product{}
+	for _, variant := range variants {
+		byParent[*variant.ParentID] = append(byParent[*variant.ParentID], variant)
+	}
+
+	for _, product := range products {
+		product.Variants = byParent[product.ID]
+	}
+
+	return nil
+}
+
+// normalizeVariantAttributes trims names and values and rejects empty or duplicate entries
+func normalizeVariantAttributes(attributes productModel.VariantAttributes) (productModel.VariantAttributes, error) {
+	if len(attributes) == 0 {
+		return nil, errors.New("at least one attribute is required")
+	}
+
+	combinations := 1
+	names := map[string]bool{}
+	normalized := make(productModel.VariantAttributes, 0, len(attributes))
+	for _, attribute := range attributes {
+		name := strings.ToLower(strings.TrimSpace(attribute.Name))
+		if name == "" {
+			return nil, errors.New("attribute name is required")
+		}
+
+		if names[name] {
+			return nil, errors.New("attribute names must be unique")
+		}
+		names[name] = true
+
+		if len(attribute.Values) == 0 {
+			return nil, errors.New("attribute values are required")
+		}
+
+		seen := map[string]bool{}
+		values := make([]string, 0, len(attribute.Values))
+		for _, value := range attribute.Values {
+			value = strings.TrimSpace(value)
+			if value == "" || skuCode(value) == "" {
+				return nil, errors.New("attribute values must contain letters or digits")
+			}
+
+			if seen[strings.ToLower(value)] {
+				return nil, errors.New("attribute values must be unique")
+			}
+			seen[strings.ToLower(value)] = true
+
+			values = append(values, value)
+		}
+
+		combinations *= len(values)
+		if combinations > maxVariantCombinations {
+			return nil, errors.New("too many variant combinations")
+		}
+
+		normalized = append(normalized, productModel.VariantAttribute{Name: name, Values: values})
+	}
+
+	return normalized, nil
+}
+
+// variantCombinations returns every combination of the attribute values
+// in the order of the attribute definitions
+func variantCombinations(attributes productModel.VariantAttributes) []productModel.VariantValues {
+	combinations := []productModel.VariantValues{{}}
+	for _, attribute := range attributes {
+		next := make([]productModel.VariantValues, 0, len(combinations)*len(attribute.Values))
+		for _, combination := range combinations {
+			for _, value := range attribute.Values {
+				values := productModel.VariantValues{}
+				for name, existing := range combination {
+					values[name] = existing
+				}
+				values[attribute.Name] = value
+				next = append(next, values)
+			}
+		}
+		combinations = next
+	}
+
+	return combinations
+}
+
+// variantKey identifies a combination by its own attribute names and values,
+// case-insensitively
+func variantKey(values productModel.VariantValues) string {
+	parts := make([]string, 0, len(values))
+	for name, value := range values {
+		parts = append(parts, strings.ToLower(name)+"="+strings.ToLower(value))
+	}
+	sort.Strings(parts)
+
+	return strings.Join(parts, "\x00")
+}
+
+// sameAttributeNames checks if two attribute definitions name the same attributes
+func sameAttributeNames(a, b productModel.VariantAttributes) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	names := map[string]bool{}
+	for _, attribute := range a {
+		names[strings.ToLower(attribute.Name)] = true
+	}
+
+	for _, attribute := range b {
+		if !names[strings.ToLower(attribute.Name)] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newVariant builds a variant of parent, e.g. sku TSHIRT-M-RED and name "T-Shirt - M / Red"
+func newVariant(parent *productModel.Product, attributes productModel.VariantAttributes, values productModel.VariantValues) *productModel.Product {
+	codes := []string{parent.Sku}
+	labels := make([]string, 0, len(attributes))
+	for _, attribute := range attributes {
+		codes = append(codes, skuCode(values[attribute.Name]))
+		labels = append(labels, values[attribute.Name])
+	}
+
+	parentID := parent.ID
+	return &productModel.Product{
+		ParentID:       &parentID,
+		Sku:            strings.Join(codes, "-"),
+		Name:           parent.Name + " - " + strings.Join(labels, " / "),
+		Unit:           parent.Unit,
+		Weight:         parent.Weight,
+		Dimension:      parent.Dimension,
+		IsBatchTracked: parent.IsBatchTracked,
+		Storage:        parent.Storage,
+		VariantValues:  values,
+	}
+}
+
+// skuCode turns an attribute value into an uppercase sku segment
+func skuCode(value string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToUpper(value) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,57 @@
+package slotting
+
+import (
+	"context"
+	"errors"
+
+	slottingModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/slotting"
+	locationRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/location"
+	productRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/product"
+)
+
+type SlottingService interface {
+	CheckCompatibility(ctx context.Context, productID, locationID int) (*slottingModel.Compatibility, error)
+}
+
+type slottingService struct {
+	productRepo  productRepo.ProductRepository
+	locationRepo locationRepo.LocationRepository
+}
+
+func NewSlottingService(productRepo productRepo.ProductRepository, locationRepo locationRepo.LocationRepository) SlottingService {
+	return &slottingService{
+		productRepo:  productRepo,
+		locationRepo: locationRepo,
+	}
+}
+
+// CheckCompatibility implements SlottingService.
+func (s *slottingService) CheckCompatibility(ctx context.Context, productID, locationID int) (*slottingModel.Compatibility, error) {
+	if productID <= 0 {
+		return nil, errors.New("invalid product id")
+	}
+
+	if locationID <= 0 {
+		return nil, errors.New("invalid location id")
+	}
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+
+	location, err := s.locationRepo.GetByID(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if location == nil {
+		return nil, errors.New("location not found")
+	}
+
+	return slottingModel.CheckCompatibility(product, location), nil
+}
@@ -0,0 +1,214 @@
+package sourcing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	sourcingModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/sourcing"
+	productRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/product"
+	sourcingRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/sourcing"
+	supplierRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/supplier"
+	"ecosystem.garyle/service/pkg/utils/filter"
+)
+
+type SourcingService interface {
+	Create(ctx context.Context, productSupplier *sourcingModel.ProductSupplier) (*sourcingModel.ProductSupplier, error)
+	GetByID(ctx context.Context, id int) (*sourcingModel.ProductSupplier, error)
+	ListProductSources(ctx context.Context, productID, limit, page int, params *filter.Params) ([]*sourcingModel.ProductSupplier, int, error)
+	ListSupplierCatalog(ctx context.Context, supplierID, limit, page int, params *filter.Params) ([]*sourcingModel.ProductSupplier, int, error)
+	UpdateByID(ctx context.Context, productSupplier *sourcingModel.ProductSupplier, id int) error
+	DeleteByID(ctx context.Context, id int) error
+}
+
+type sourcingService struct {
+	sourcingRepo sourcingRepo.SourcingRepository
+	productRepo  productRepo.ProductRepository
+	supplierRepo supplierRepo.SupplierRepository
+}
+
+func NewSourcingService(sourcingRepo sourcingRepo.SourcingRepository, productRepo productRepo.ProductRepository, supplierRepo supplierRepo.SupplierRepository) SourcingService {
+	return &sourcingService{
+		sourcingRepo: sourcingRepo,
+		productRepo:  productRepo,
+		supplierRepo: supplierRepo,
+	}
+}
+
+// Create implements SourcingService.
+func (s *sourcingService) Create(ctx context.Context, productSupplier *sourcingModel.ProductSupplier) (*sourcingModel.ProductSupplier, error) {
+	if productSupplier.ProductID <= 0 {
+		return nil, errors.New("product_id is required")
+	}
+
+	if productSupplier.SupplierID <= 0 {
+		return nil, errors.New("supplier_id is required")
+	}
+
+	if err := validateProductSupplier(productSupplier); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkProduct(ctx, productSupplier.ProductID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkSupplier(ctx, productSupplier.SupplierID); err != nil {
+		return nil, err
+	}
+
+	return s.sourcingRepo.Create(ctx, productSupplier)
+}
+
+// validate the sourcing terms
+func validateProductSupplier(productSupplier *sourcingModel.ProductSupplier) error {
+	productSupplier.SupplierItemCode = strings.TrimSpace(productSupplier.SupplierItemCode)
+	productSupplier.Currency = strings.ToUpper(strings.TrimSpace(productSupplier.Currency))
+
+	if productSupplier.UnitCost < 0 {
+		return errors.New("unit_cost must not be negative")
+	}
+
+	if len(productSupplier.Currency) != 3 {
+		return errors.New("currency must be a 3-letter ISO 4217 code")
+	}
+
+	for _, r := range productSupplier.Currency {
+		if r < 'A' || r > 'Z' {
+			return errors.New("currency must be a 3-letter ISO 4217 code")
+		}
+	}
+
+	if productSupplier.MinOrderQty < 0 {
+		return errors.New("min_order_qty must not be negative")
+	}
+
+	if productSupplier.LeadTimeDays < 0 {
+		return errors.New("lead_time_days must not be negative")
+	}
+
+	return nil
+}
+
+func (s *sourcingService) checkProduct(ctx context.Context, productID int) error {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	if product == nil {
+		return errors.New("product not found")
+	}
+
+	return nil
+}
+
+func (s *sourcingService) checkSupplier(ctx context.Context, supplierID int) error {
+	supplier, err := s.supplierRepo.GetByID(ctx, supplierID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("supplier not found")
+		}
+
+		return err
+	}
+
+	if supplier == nil {
+		return errors.New("supplier not found")
+	}
+
+	return nil
+}
+
+// GetByID implements SourcingService.
+func (s *sourcingService) GetByID(ctx context.Context, id int) (*sourcingModel.ProductSupplier, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid product supplier id")
+	}
+
+	return s.sourcingRepo.GetByID(ctx, id)
+}
+
+// ListProductSources returns the suppliers of a product, preferred first, and their total
+func (s *sourcingService) ListProductSources(ctx context.Context, productID, limit, page int, params *filter.Params) ([]*sourcingModel.ProductSupplier, int, error) {
+	if err := s.checkProduct(ctx, productID); err != nil {
+		return nil, 0, err
+	}
+
+	productSuppliers, err := s.sourcingRepo.ListByProduct(ctx, productID, limit, page, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.sourcingRepo.CountByProduct(ctx, productID, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return productSuppliers, total, nil
+}
+
+// ListSupplierCatalog returns the products a supplier provides and their total
+func (s *sourcingService) ListSupplierCatalog(ctx context.Context, supplierID, limit, page int, params *filter.Params) ([]*sourcingModel.ProductSupplier, int, error) {
+	if err := s.checkSupplier(ctx, supplierID); err != nil {
+		return nil, 0, err
+	}
+
+	productSuppliers, err := s.sourcingRepo.ListBySupplier(ctx, supplierID, limit, page, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.sourcingRepo.CountBySupplier(ctx, supplierID, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return productSuppliers, total, nil
+}
+
+// UpdateByID implements SourcingService.
+func (s *sourcingService) UpdateByID(ctx context.Context, productSupplier *sourcingModel.ProductSupplier, id int) error {
+	if id <= 0 {
+		return errors.New("invalid product supplier id")
+	}
+
+	if err := validateProductSupplier(productSupplier); err != nil {
+		return err
+	}
+
+	existing, err := s.sourcingRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return errors.New("product supplier not found")
+	}
+
+	// the product and supplier of a sourcing record are fixed
+	productSupplier.ID = existing.ID
+	productSupplier.ProductID = existing.ProductID
+	productSupplier.SupplierID = existing.SupplierID
+
+	return s.sourcingRepo.UpdateByID(ctx, productSupplier, id)
+}
+
+// DeleteByID implements SourcingService.
+func (s *sourcingService) DeleteByID(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid product supplier id")
+	}
+
+	existing, err := s.sourcingRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return errors.New("product supplier not found")
+	}
+
+	return s.sourcingRepo.DeleteByID(ctx, id)
+}
@@ -0,0 +1,276 @@
+package stock
+
+import (
+	"context"
+	"errors"
+
+	locationModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
+	productModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/product"
+	slottingModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/slotting"
+	stockModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/stock"
+	batchRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/batch"
+	locationRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/location"
+	productRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/product"
+	stockRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/stock"
+)
+
+// maxRoomCandidates limits how many locations with room are checked for compatibility
+const maxRoomCandidates = 500
+
+type StockService interface {
+	ListByLocation(ctx context.Context, locationID int) ([]*stockModel.Stock, error)
+	Put(ctx context.Context, locationID int, movement *stockModel.Movement) (*stockModel.Stock, error)
+	Take(ctx context.Context, locationID int, movement *stockModel.Movement) (*stockModel.Stock, error)
+	GetUtilization(ctx context.Context, locationID int) (*locationModel.Utilization, error)
+	ListUtilization(ctx context.Context, limit, page int) ([]*locationModel.Utilization, int, error)
+	FindLocationsWithRoom(ctx context.Context, productID int, quantity float64, limit int) ([]*locationModel.Utilization, error)
+}
+
+type stockService struct {
+	stockRepo    stockRepo.StockRepository
+	locationRepo locationRepo.LocationRepository
+	productRepo  productRepo.ProductRepository
+	batchRepo    batchRepo.BatchRepository
+}
+
+func NewStockService(stockRepo stockRepo.StockRepository, locationRepo locationRepo.LocationRepository, productRepo productRepo.ProductRepository, batchRepo batchRepo.BatchRepository) StockService {
+	return &stockService{
+		stockRepo:    stockRepo,
+		locationRepo: locationRepo,
+		productRepo:  productRepo,
+		batchRepo:    batchRepo,
+	}
+}
+
+// ListByLocation implements StockService.
+func (s *stockService) ListByLocation(ctx context.Context, locationID int) ([]*stockModel.Stock, error) {
+	if _, err := s.getLocation(ctx, locationID); err != nil {
+		return nil, err
+	}
+
+	return s.stockRepo.ListByLocation(ctx, locationID)
+}
+
+// Put implements StockService. The product has to be compatible with the
+// location and fit within its limits. The location is checked while it is
+// locked for the put, so a status change or another put cannot slip in
+// between the check and the put.
+func (s *stockService) Put(ctx context.Context, locationID int, movement *stockModel.Movement) (*stockModel.Stock, error) {
+	if locationID <= 0 {
+		return nil, errors.New("invalid location id")
+	}
+
+	product, err := s.validateMovement(ctx, movement)
+	if err != nil {
+		return nil, err
+	}
+
+	weight, volume := requiredSpace(product, movement.Quantity)
+	stock, err := s.stockRepo.Put(ctx, locationID, movement, func(location *locationModel.Location, utilization *locationModel.Utilization) error {
+		if !location.Status.AllowsInbound() {
+			return errors.New("location is blocked for inbound")
+		}
+
+		if !slottingModel.CheckCompatibility(product, location).Compatible {
+			return errors.New("product is not compatible with the location")
+		}
+
+		if !utilization.Fits(weight, volume, movement.Quantity) {
+			return errors.New("location does not have room for the quantity")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if stock == nil {
+		return nil, errors.New("location not found")
+	}
+
+	return stock, nil
+}
+
+// Take implements StockService. The status of the location is checked while
+// it is locked for the take, like in Put.
+func (s *stockService) Take(ctx context.Context, locationID int, movement *stockModel.Movement) (*stockModel.Stock, error) {
+	if locationID <= 0 {
+		return nil, errors.New("invalid location id")
+	}
+
+	if _, err := s.validateMovement(ctx, movement); err != nil {
+		return nil, err
+	}
+
+	stock, err := s.stockRepo.Take(ctx, locationID, movement, func(location *locationModel.Location) error {
+		if location == nil {
+			return errors.New("location not found")
+		}
+
+		if !location.Status.AllowsOutbound() {
+			return errors.New("location is blocked for outbound")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if stock == nil {
+		return nil, errors.New("not enough stock in the location")
+	}
+
+	return stock, nil
+}
+
+// validateMovement checks the quantity and that a batch, when named or
+// required, belongs to the product
+func (s *stockService) validateMovement(ctx context.Context, movement *stockModel.Movement) (*productModel.Product, error) {
+	if movement.ProductID <= 0 {
+		return nil, errors.New("product_id is required")
+	}
+
+	if movement.Quantity <= 0 {
+		return nil, errors.New("quantity must be greater than 0")
+	}
+
+	product, err := s.productRepo.GetByID(ctx, movement.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+
+	if movement.BatchID == nil {
+		if product.IsBatchTracked {
+			return nil, errors.New("batch_id is required for a batch tracked product")
+		}
+		return product, nil
+	}
+
+	if !product.IsBatchTracked {
+		return nil, errors.New("product is not batch tracked")
+	}
+
+	batch, err := s.batchRepo.GetByID(ctx, *movement.BatchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if batch == nil {
+		return nil, errors.New("batch not found")
+	}
+
+	if batch.ProductID != product.ID {
+		return nil, errors.New("batch does not belong to the product")
+	}
+
+	return product, nil
+}
+
+// GetUtilization implements StockService.
+func (s *stockService) GetUtilization(ctx context.Context, locationID int) (*locationModel.Utilization, error) {
+	if locationID <= 0 {
+		return nil, errors.New("invalid location id")
+	}
+
+	utilization, err := s.stockRepo.GetUtilization(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if utilization == nil {
+		return nil, errors.New("location not found")
+	}
+
+	return utilization, nil
+}
+
+// ListUtilization implements StockService.
+func (s *stockService) ListUtilization(ctx context.Context, limit, page int) ([]*locationModel.Utilization, int, error) {
+	utilizations, err := s.stockRepo.ListUtilization(ctx, limit, page)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.stockRepo.CountLocations(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return utilizations, total, nil
+}
+
+// FindLocationsWithRoom implements StockService. It returns up to limit
+// locations that can take quantity of the product within their limits and
+// whose capabilities match the storage conditions of the product. Locations
+// blocked for inbound are left out.
+func (s *stockService) FindLocationsWithRoom(ctx context.Context, productID int, quantity float64, limit int) ([]*locationModel.Utilization, error) {
+	if productID <= 0 {
+		return nil, errors.New("product_id is required")
+	}
+
+	if quantity <= 0 {
+		return nil, errors.New("quantity must be greater than 0")
+	}
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+
+	weight, volume := requiredSpace(product, quantity)
+	candidates, err := s.stockRepo.ListWithRoom(ctx, weight, volume, quantity, maxRoomCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := []*locationModel.Utilization{}
+	for _, candidate := range candidates {
+		location, err := s.locationRepo.GetByID(ctx, candidate.LocationID)
+		if err != nil {
+			return nil, err
+		}
+
+		if location == nil || !location.Status.AllowsInbound() || !slottingModel.CheckCompatibility(product, location).Compatible {
+			continue
+		}
+
+		locations = append(locations, candidate)
+		if len(locations) == limit {
+			break
+		}
+	}
+
+	return locations, nil
+}
+
+func (s *stockService) getLocation(ctx context.Context, locationID int) (*locationModel.Location, error) {
+	if locationID <= 0 {
+		return nil, errors.New("invalid location id")
+	}
+
+	location, err := s.locationRepo.GetByID(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if location == nil {
+		return nil, errors.New("location not found")
+	}
+
+	return location, nil
+}
+
+// requiredSpace returns the weight and volume of quantity units of product
+func requiredSpace(product *productModel.Product, quantity float64) (float64, float64) {
+	return product.Weight * quantity, product.UnitVolume() * quantity
+}
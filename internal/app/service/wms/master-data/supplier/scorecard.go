@@ -0,0 +1,185 @@
+package supplier
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	supplierModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/supplier"
+)
+
+// ScorecardService rates suppliers on their receipts
+type ScorecardService interface {
+	RecordReceipt(ctx context.Context, supplierID int, receipt *supplierModel.Receipt) (*supplierModel.Receipt, error)
+	ListReceipts(ctx context.Context, supplierID int, period supplierModel.Period, limit, page int) ([]*supplierModel.Receipt, error)
+	Scorecard(ctx context.Context, supplierID int, period supplierModel.Period) (*supplierModel.Scorecard, error)
+	RankScorecards(ctx context.Context, request supplierModel.RankingRequest) ([]*supplierModel.Scorecard, error)
+}
+
+// RecordReceipt implements SupplierService.
+func (s *supplierService) RecordReceipt(ctx context.Context, supplierID int, receipt *supplierModel.Receipt) (*supplierModel.Receipt, error) {
+	if err := s.checkSupplier(ctx, supplierID); err != nil {
+		return nil, err
+	}
+
+	if err := validateReceipt(receipt); err != nil {
+		return nil, err
+	}
+
+	receipt.SupplierID = supplierID
+	created, err := s.supplierRepo.CreateReceipt(ctx, receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	if created == nil {
+		return nil, errors.New("product not found")
+	}
+
+	return created, nil
+}
+
+// ListReceipts implements SupplierService.
+func (s *supplierService) ListReceipts(ctx context.Context, supplierID int, period supplierModel.Period, limit, page int) ([]*supplierModel.Receipt, error) {
+	if err := s.checkSupplier(ctx, supplierID); err != nil {
+		return nil, err
+	}
+
+	return s.supplierRepo.ListReceipts(ctx, supplierID, period, limit, page)
+}
+
+// Scorecard implements SupplierService. A supplier without receipts in the
+// period gets an empty scorecard.
+func (s *supplierService) Scorecard(ctx context.Context, supplierID int, period supplierModel.Period) (*supplierModel.Scorecard, error) {
+	supplier, err := s.GetByID(ctx, supplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	scorecards, err := s.supplierRepo.Scorecards(ctx, period, supplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(scorecards) == 0 {
+		return &supplierModel.Scorecard{SupplierID: supplier.ID, SupplierName: supplier.Name, Period: period}, nil
+	}
+
+	scorecard := scorecards[0]
+	scorecard.Score = scorecard.CalculateScore()
+
+	return scorecard, nil
+}
+
+// RankScorecards implements SupplierService. Suppliers without receipts in the
+// period are not ranked.
+func (s *supplierService) RankScorecards(ctx context.Context, request supplierModel.RankingRequest) ([]*supplierModel.Scorecard, error) {
+	less, err := scorecardOrder(request.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	scorecards, err := s.supplierRepo.Scorecards(ctx, request.Period, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := make([]*supplierModel.Scorecard, 0, len(scorecards))
+	for _, scorecard := range scorecards {
+		if scorecard.Receipts < request.MinReceipts {
+			continue
+		}
+
+		scorecard.Score = scorecard.CalculateScore()
+		ranked = append(ranked, scorecard)
+	}
+
+	// ties keep the supplier order of the repository
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return less(ranked[i], ranked[j])
+	})
+
+	if request.Limit > 0 && len(ranked) > request.Limit {
+		ranked = ranked[:request.Limit]
+	}
+
+	for i, scorecard := range ranked {
+		scorecard.Rank = i + 1
+	}
+
+	return ranked, nil
+}
+
+// scorecardOrder returns how the ranked list is sorted. Missing rates rank last.
+func scorecardOrder(sortBy string) (func(a, b *supplierModel.Scorecard) bool, error) {
+	switch sortBy {
+	case "", "score":
+		return func(a, b *supplierModel.Scorecard) bool { return a.Score > b.Score }, nil
+	case "on_time_rate":
+		return func(a, b *supplierModel.Scorecard) bool { return higher(a.OnTimeRate, b.OnTimeRate) }, nil
+	case "fill_rate":
+		return func(a, b *supplierModel.Scorecard) bool { return higher(a.FillRate, b.FillRate) }, nil
+	case "rejection_rate":
+		return func(a, b *supplierModel.Scorecard) bool { return lower(a.RejectionRate, b.RejectionRate) }, nil
+	case "lead_time":
+		return func(a, b *supplierModel.Scorecard) bool { return lower(a.AverageLeadTimeDays, b.AverageLeadTimeDays) }, nil
+	}
+
+	return nil, errors.New("sort must be one of score, on_time_rate, fill_rate, rejection_rate or lead_time")
+}
+
+func higher(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a != nil && b == nil
+	}
+
+	return *a > *b
+}
+
+func lower(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a != nil && b == nil
+	}
+
+	return *a < *b
+}
+
+func validateReceipt(receipt *supplierModel.Receipt) error {
+	if receipt.ProductID <= 0 {
+		return errors.New("product_id is required")
+	}
+
+	if receipt.OrderedQty <= 0 {
+		return errors.New("ordered_qty must be greater than 0")
+	}
+
+	if receipt.ReceivedQty < 0 {
+		return errors.New("received_qty must not be negative")
+	}
+
+	if receipt.RejectedQty < 0 || receipt.RejectedQty > receipt.ReceivedQty {
+		return errors.New("rejected_qty must be between 0 and received_qty")
+	}
+
+	if receipt.OrderedAt.IsZero() || receipt.ExpectedAt.IsZero() || receipt.ReceivedAt.IsZero() {
+		return errors.New("ordered_at, expected_at and received_at are required")
+	}
+
+	if receipt.ExpectedAt.Before(receipt.OrderedAt) || receipt.ReceivedAt.Before(receipt.OrderedAt) {
+		return errors.New("expected_at and received_at must not be before ordered_at")
+	}
+
+	return nil
+}
+
+// ReceiptValidationErrors are the errors of RecordReceipt caused by the request
+func ReceiptValidationErrors() []string {
+	return []string{
+		"product_id is required",
+		"ordered_qty must be greater than 0",
+		"received_qty must not be negative",
+		"rejected_qty must be between 0 and received_qty",
+		"ordered_at, expected_at and received_at are required",
+		"expected_at and received_at must not be before ordered_at",
+	}
+}
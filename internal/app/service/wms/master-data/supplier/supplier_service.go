@@ -4,25 +4,58 @@ import (
 	"context"
 	"errors"
 
+	"ecosystem.garyle/service/internal/app/service/wms/master-data/importer"
+	partyService "ecosystem.garyle/service/internal/app/service/wms/master-data/party"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
+	partyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
 	supplierModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/supplier"
 	supplierRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/supplier"
+	"ecosystem.garyle/service/pkg/utils/filter"
+	"ecosystem.garyle/service/pkg/utils/spreadsheet"
 )
 
 type SupplierService interface {
 	Create(ctx context.Context, supplier *supplierModel.Supplier) (*supplierModel.Supplier, error)
 	GetByID(ctx context.Context, id int) (*supplierModel.Supplier, error)
-	List(ctx context.Context, limit, page int) ([]*supplierModel.Supplier, error)
-	Count(ctx context.Context) (int, error)
+	List(ctx context.Context, limit, page int, params *filter.Params) ([]*supplierModel.Supplier, error)
+	Count(ctx context.Context, params *filter.Params) (int, error)
 	UpdateByID(ctx context.Context, supplier *supplierModel.Supplier, id int) error
 	DeleteByID(ctx context.Context, id int) error
+	Import(ctx context.Context, records []spreadsheet.Record, opts imports.Options, progress func(done, total int)) (*imports.Result, error)
+	Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(supplier *supplierModel.Supplier) error) error
+	partyService.DetailService
+	partyService.DuplicateService
+	ScorecardService
 }
 
 type supplierService struct {
+	partyService.DetailService
+	partyService.DuplicateService
 	supplierRepo supplierRepo.SupplierRepository
 }
 
 func NewSupplierService(supplierRepo supplierRepo.SupplierRepository) SupplierService {
-	return &supplierService{supplierRepo: supplierRepo}
+	s := &supplierService{supplierRepo: supplierRepo}
+	s.DetailService = partyService.NewDetailService(supplierRepo, s.checkSupplier)
+	s.DuplicateService = partyService.NewDuplicateService(supplierRepo, "supplier", func(ctx context.Context, id int) (interface{}, error) {
+		return s.GetByID(ctx, id)
+	})
+
+	return s
+}
+
+// checkSupplier returns "supplier not found" when the supplier does not exist
+func (s *supplierService) checkSupplier(ctx context.Context, id int) error {
+	supplier, err := s.supplierRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if supplier == nil {
+		return errors.New("supplier not found")
+	}
+
+	return nil
 }
 
 // Create implements SupplierService.
@@ -43,18 +76,30 @@ func (s *supplierService) Create(ctx context.Context, supplier *supplierModel.Su
 		return nil, errors.New("supplier already exists")
 	}
 
+	if err := partyService.CheckTaxID(ctx, s.supplierRepo, "supplier", supplier.Registration, 0); err != nil {
+		return nil, err
+	}
+
+	// likely duplicates do not block the create, they are returned as a warning
+	profile := partyModel.NewProfile(0, supplier.Name, supplier.Address, supplier.Contact, supplier.Addresses, supplier.Contacts)
+	duplicates, err := s.MatchProfile(ctx, profile, partyModel.DefaultDuplicateThreshold)
+	if err != nil {
+		return nil, err
+	}
+
 	// create supplier
 	createdSupplier, err := s.supplierRepo.Create(ctx, supplier)
 	if err != nil {
 		return nil, err
 	}
+	createdSupplier.PossibleDuplicates = duplicates
 
 	return createdSupplier, nil
 }
 
 // List implements SupplierService.
-func (s *supplierService) List(ctx context.Context, limit int, page int) ([]*supplierModel.Supplier, error) {
-	suppliers, err := s.supplierRepo.List(ctx, limit, page)
+func (s *supplierService) List(ctx context.Context, limit, page int, params *filter.Params) ([]*supplierModel.Supplier, error) {
+	suppliers, err := s.supplierRepo.List(ctx, limit, page, params)
 	if err != nil {
 		return nil, err
 	}
@@ -68,8 +113,8 @@ func (s *supplierService) List(ctx context.Context, limit int, page int) ([]*sup
 }
 
 // Count implements SupplierService.
-func (s *supplierService) Count(ctx context.Context) (int, error) {
-	return s.supplierRepo.Count(ctx)
+func (s *supplierService) Count(ctx context.Context, params *filter.Params) (int, error) {
+	return s.supplierRepo.Count(ctx, params)
 }
 
 // GetByID implements SupplierService.
@@ -80,10 +125,18 @@ func (s *supplierService) GetByID(ctx context.Context, id int) (*supplierModel.S
 
 	supplier, err := s.supplierRepo.GetByID(ctx, id)
 	if err != nil {
-		if err.Error() == "sql: no rows in result set" {
-			return nil, errors.New("supplier not found")
-		}
+		return nil, err
+	}
+
+	if supplier == nil {
+		return nil, errors.New("supplier not found")
+	}
+
+	if supplier.Addresses, err = s.supplierRepo.ListAddresses(ctx, id); err != nil {
+		return nil, err
+	}
 
+	if supplier.Contacts, err = s.supplierRepo.ListContacts(ctx, id); err != nil {
 		return nil, err
 	}
 
@@ -96,7 +149,20 @@ func (s *supplierService) UpdateByID(ctx context.Context, supplier *supplierMode
 		return errors.New("invalid supplier id")
 	}
 
-	err := validateSupplier(supplier)
+	// addresses and contacts are changed through their own routes, the
+	// stored ones stand in for the free-text fields
+	addresses, err := s.supplierRepo.ListAddresses(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	contacts, err := s.supplierRepo.ListContacts(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	supplier.Addresses, supplier.Contacts = addresses, contacts
+	err = validateSupplier(supplier)
 	if err != nil {
 		return err
 	}
@@ -111,6 +177,10 @@ func (s *supplierService) UpdateByID(ctx context.Context, supplier *supplierMode
 		return errors.New("supplier not found")
 	}
 
+	if err := partyService.CheckTaxID(ctx, s.supplierRepo, "supplier", supplier.Registration, id); err != nil {
+		return err
+	}
+
 	return s.supplierRepo.UpdateByID(ctx, supplier, id)
 }
 
@@ -138,13 +208,49 @@ func validateSupplier(supplier *supplierModel.Supplier) error {
 		return errors.New("name is required")
 	}
 
-	if supplier.Address == "" {
+	// the free-text fields may be left out when structured details are given
+	if supplier.Address == "" && len(supplier.Addresses) == 0 {
 		return errors.New("address is required")
 	}
 
-	if supplier.Contact == "" {
+	if supplier.Contact == "" && len(supplier.Contacts) == 0 {
 		return errors.New("contact is required")
 	}
 
-	return nil
+	if err := partyService.ValidateRegistration(&supplier.Registration); err != nil {
+		return err
+	}
+
+	return partyService.ValidateDetails(supplier.Addresses, supplier.Contacts)
+}
+
+// Import validates and saves supplier rows read from an uploaded file
+func (s *supplierService) Import(ctx context.Context, records []spreadsheet.Record, opts imports.Options, progress func(done, total int)) (*imports.Result, error) {
+	return importer.Run(ctx, records, importer.Source[supplierModel.Supplier]{
+		Entity:   "supplier",
+		Parse:    parseSupplierRow,
+		Validate: validateSupplier,
+		Save:     s.supplierRepo.CreateBatch,
+	}, opts, progress)
+}
+
+// parseSupplierRow maps the columns of an import row to a supplier
+func parseSupplierRow(values map[string]string) (*supplierModel.Supplier, error) {
+	return &supplierModel.Supplier{
+		Name:    values["name"],
+		Address: values["address"],
+		Contact: values["contact"],
+		Registration: partyModel.Registration{
+			TaxCountry:         values["tax_country"],
+			TaxID:              values["tax_id"],
+			RegistrationNumber: values["registration_number"],
+			PaymentTerms:       values["payment_terms"],
+			Currency:           values["currency"],
+		},
+	}, nil
+}
+
+// Export streams the supplier rows matching the list filters to fn
+func (s *supplierService) Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(supplier *supplierModel.Supplier) error) error {
+	return s.supplierRepo.Export(ctx, params, includeDeleted, fn)
 }
@@ -0,0 +1,152 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	trashModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/trash"
+	trashRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/trash"
+)
+
+type TrashService interface {
+	List(ctx context.Context, entity string, limit, page int) ([]*trashModel.Item, int, error)
+	Restore(ctx context.Context, entity string, id int) (*trashModel.Item, error)
+	Purge(ctx context.Context, entity string, id int) error
+	PurgeExpired(ctx context.Context, entity string, deletedBefore time.Time) (*trashModel.PurgeReport, error)
+}
+
+type trashService struct {
+	trashRepo trashRepo.TrashRepository
+}
+
+func NewTrashService(trashRepo trashRepo.TrashRepository) TrashService {
+	return &trashService{trashRepo: trashRepo}
+}
+
+// UnknownEntityError is returned for an entity without a trash
+func UnknownEntityError() string {
+	return "entity must be one of " + strings.Join(trashModel.EntityNames(), ", ")
+}
+
+// NotInTrashError is returned when a row does not exist or is not deleted, e.g. "product not found in trash"
+func NotInTrashError(entity trashModel.Entity) string {
+	return entity.Singular + " not found in trash"
+}
+
+// List implements TrashService.
+func (s *trashService) List(ctx context.Context, name string, limit, page int) ([]*trashModel.Item, int, error) {
+	entity, err := findEntity(name)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items, err := s.trashRepo.List(ctx, entity, limit, page)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.trashRepo.Count(ctx, entity)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
+
+// Restore implements TrashService. Unique keys such as the product sku or
+// the location code are checked again, since another row may have taken them
+// since the delete.
+func (s *trashService) Restore(ctx context.Context, name string, id int) (*trashModel.Item, error) {
+	entity, err := findEntity(name)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.trashRepo.Restore(ctx, entity, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if item == nil {
+		return nil, errors.New(NotInTrashError(entity))
+	}
+
+	return item, nil
+}
+
+// Purge implements TrashService. A row that is still referenced returns a
+// trashModel.ReferencedError.
+func (s *trashService) Purge(ctx context.Context, name string, id int) error {
+	entity, err := findEntity(name)
+	if err != nil {
+		return err
+	}
+
+	purged, err := s.trashRepo.Purge(ctx, entity, id)
+	if err != nil {
+		return err
+	}
+
+	if !purged {
+		return errors.New(NotInTrashError(entity))
+	}
+
+	return nil
+}
+
+// PurgeExpired implements TrashService. Rows deleted before deletedBefore are
+// purged, the ones still referenced are skipped and reported.
+func (s *trashService) PurgeExpired(ctx context.Context, name string, deletedBefore time.Time) (*trashModel.PurgeReport, error) {
+	entities := trashModel.Entities
+	if name != "" {
+		entity, err := findEntity(name)
+		if err != nil {
+			return nil, err
+		}
+		entities = []trashModel.Entity{entity}
+	}
+
+	report := &trashModel.PurgeReport{
+		DeletedBefore: deletedBefore,
+		Purged:        map[string]int{},
+		Skipped:       []trashModel.Skipped{},
+	}
+
+	for _, entity := range entities {
+		ids, err := s.trashRepo.ListExpired(ctx, entity, deletedBefore)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			purged, err := s.trashRepo.Purge(ctx, entity, id)
+
+			var referenced *trashModel.ReferencedError
+			if errors.As(err, &referenced) {
+				report.Skipped = append(report.Skipped, trashModel.Skipped{Entity: entity.Name, ID: id, References: referenced.References})
+				continue
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			if purged {
+				report.Purged[entity.Name]++
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func findEntity(name string) (trashModel.Entity, error) {
+	entity, ok := trashModel.FindEntity(name)
+	if !ok {
+		return trashModel.Entity{}, errors.New(UnknownEntityError())
+	}
+
+	return entity, nil
+}
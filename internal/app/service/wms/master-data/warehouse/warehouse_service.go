@@ -0,0 +1,126 @@
+package warehouse
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	warehouseModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/warehouse"
+	warehouseRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/warehouse"
+	"ecosystem.garyle/service/pkg/utils/filter"
+)
+
+type WarehouseService interface {
+	Create(ctx context.Context, warehouse *warehouseModel.Warehouse) (*warehouseModel.Warehouse, error)
+	GetByID(ctx context.Context, id int) (*warehouseModel.Warehouse, error)
+	List(ctx context.Context, limit, page int, params *filter.Params) ([]*warehouseModel.Warehouse, error)
+	Count(ctx context.Context, params *filter.Params) (int, error)
+	UpdateByID(ctx context.Context, warehouse *warehouseModel.Warehouse, id int) error
+	DeleteByID(ctx context.Context, id int) error
+}
+
+type warehouseService struct {
+	warehouseRepo warehouseRepo.WarehouseRepository
+}
+
+func NewWarehouseService(warehouseRepo warehouseRepo.WarehouseRepository) WarehouseService {
+	return &warehouseService{warehouseRepo: warehouseRepo}
+}
+
+// Create implements WarehouseService.
+func (s *warehouseService) Create(ctx context.Context, warehouse *warehouseModel.Warehouse) (*warehouseModel.Warehouse, error) {
+	if err := validateWarehouse(warehouse); err != nil {
+		return nil, err
+	}
+
+	return s.warehouseRepo.Create(ctx, warehouse)
+}
+
+// validate warehouse
+func validateWarehouse(warehouse *warehouseModel.Warehouse) error {
+	warehouse.Code = strings.ToUpper(strings.TrimSpace(warehouse.Code))
+	if warehouse.Code == "" {
+		return errors.New("code is required")
+	}
+
+	// the code is the first segment of the location codes
+	if strings.Contains(warehouse.Code, "-") {
+		return errors.New("code must not contain '-'")
+	}
+
+	if strings.TrimSpace(warehouse.Name) == "" {
+		return errors.New("name is required")
+	}
+
+	return nil
+}
+
+// GetByID implements WarehouseService.
+func (s *warehouseService) GetByID(ctx context.Context, id int) (*warehouseModel.Warehouse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid warehouse id")
+	}
+
+	return s.warehouseRepo.GetByID(ctx, id)
+}
+
+// List implements WarehouseService.
+func (s *warehouseService) List(ctx context.Context, limit, page int, params *filter.Params) ([]*warehouseModel.Warehouse, error) {
+	return s.warehouseRepo.List(ctx, limit, page, params)
+}
+
+// Count implements WarehouseService.
+func (s *warehouseService) Count(ctx context.Context, params *filter.Params) (int, error) {
+	return s.warehouseRepo.Count(ctx, params)
+}
+
+// UpdateByID implements WarehouseService.
+func (s *warehouseService) UpdateByID(ctx context.Context, warehouse *warehouseModel.Warehouse, id int) error {
+	if id <= 0 {
+		return errors.New("invalid warehouse id")
+	}
+
+	if err := validateWarehouse(warehouse); err != nil {
+		return err
+	}
+
+	existing, err := s.warehouseRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return errors.New("warehouse not found")
+	}
+
+	warehouse.ID = existing.ID
+	return s.warehouseRepo.UpdateByID(ctx, warehouse, id)
+}
+
+// DeleteByID implements WarehouseService. A warehouse that still has
+// locations cannot be deleted.
+func (s *warehouseService) DeleteByID(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid warehouse id")
+	}
+
+	existing, err := s.warehouseRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		return errors.New("warehouse not found")
+	}
+
+	total, err := s.warehouseRepo.CountLocations(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if total > 0 {
+		return errors.New("warehouse still has locations")
+	}
+
+	return s.warehouseRepo.DeleteByID(ctx, id)
+}
@@ -0,0 +1,62 @@
+package batch
+
+import (
+	"database/sql"
+	"time"
+
+	"ecosystem.garyle/service/pkg/utils/filter"
+)
+
+// Status of a batch
+type Status string
+
+const (
+	StatusActive      Status = "active"
+	StatusQuarantined Status = "quarantined"
+	StatusExpired     Status = "expired"
+)
+
+// IsValid checks if the status is one of the known statuses
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusActive, StatusQuarantined, StatusExpired:
+		return true
+	}
+
+	return false
+}
+
+type Batch struct {
+	ID              int          `json:"id" db:"id"`
+	ProductID       int          `json:"product_id" db:"product_id"`
+	BatchNo         string       `json:"batch_no" db:"batch_no"` // unique per product
+	ManufactureDate *Date        `json:"manufacture_date" db:"manufacture_date"`
+	ExpireDate      *Date        `json:"expire_date" db:"expire_date"`
+	Status          Status       `json:"status" db:"status"` // active/quarantined/expired
+	CreatedAt       time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at" db:"updated_at"`
+	DeletedAt       sql.NullTime `json:"deleted_at" db:"deleted_at"`
+}
+
+func (b *Batch) IsDeleted() bool {
+	return b.DeletedAt.Valid
+}
+
+// StatusColumn reports an active batch past its expiry date as expired
+// without waiting for someone to update the row
+const StatusColumn = `CASE WHEN status = 'active' AND expire_date < CURRENT_DATE THEN 'expired' ELSE status END`
+
+// QuerySpec whitelists the fields that can be used to filter and sort the batch list
+var QuerySpec = filter.Spec{
+	Fields: map[string]filter.Field{
+		"id":               {Column: "id", Type: filter.Integer},
+		"product_id":       {Column: "product_id", Type: filter.Integer},
+		"batch_no":         {Column: "batch_no", Type: filter.String},
+		"manufacture_date": {Column: "manufacture_date", Type: filter.Time},
+		"expire_date":      {Column: "expire_date", Type: filter.Time},
+		"status":           {Column: StatusColumn, Type: filter.String},
+		"created_at":       {Column: "created_at", Type: filter.Time},
+		"updated_at":       {Column: "updated_at", Type: filter.Time},
+	},
+	DefaultSort: []filter.Sort{{Column: "created_at", Desc: true}},
+}
@@ -0,0 +1,67 @@
+package batch
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Date is a calendar date without time, written as YYYY-MM-DD in JSON
+type Date struct {
+	time.Time
+}
+
+// ParseDate parses a YYYY-MM-DD date
+func ParseDate(value string) (Date, error) {
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return Date{}, errors.New("date must use the YYYY-MM-DD format")
+	}
+
+	return Date{Time: t}, nil
+}
+
+// String implements fmt.Stringer.
+func (d Date) String() string {
+	return d.Format(dateLayout)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return errors.New("date must use the YYYY-MM-DD format")
+	}
+
+	parsed, err := ParseDate(value)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (d Date) Value() (driver.Value, error) {
+	return d.Time, nil
+}
+
+// Scan implements sql.Scanner.
+func (d *Date) Scan(src interface{}) error {
+	t, ok := src.(time.Time)
+	if !ok {
+		return errors.New("unsupported type for date column")
+	}
+
+	*d = Date{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+	return nil
+}
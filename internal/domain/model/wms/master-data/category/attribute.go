@@ -0,0 +1,189 @@
+package category
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+)
+
+// AttributeType is the value type of a category attribute
+type AttributeType string
+
+const (
+	AttributeString  AttributeType = "string"
+	AttributeNumber  AttributeType = "number" // may carry a unit, e.g. V or kg
+	AttributeEnum    AttributeType = "enum"   // one of a fixed list of values
+	AttributeBoolean AttributeType = "boolean"
+)
+
+// AttributeNamePattern limits attribute names to lowercase keys, they are
+// used as JSON keys and in product list filters such as attr.voltage
+var AttributeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// AttributeDefinition describes one product attribute of a category
+type AttributeDefinition struct {
+	Name     string        `json:"name"`
+	Type     AttributeType `json:"type"`
+	Unit     string        `json:"unit,omitempty"`   // number only
+	Values   []string      `json:"values,omitempty"` // enum only
+	Required bool          `json:"required"`
+}
+
+// AttributeDefinitions is stored as a JSONB array, nil is stored as NULL
+type AttributeDefinitions []AttributeDefinition
+
+// SchemaAttribute is an attribute that applies to a category, either defined
+// by the category itself or inherited from one of its ancestors
+type SchemaAttribute struct {
+	AttributeDefinition
+	CategoryID int  `json:"category_id"` // the category that defines the attribute
+	Inherited  bool `json:"inherited"`
+}
+
+// AttributeError is returned when a product attribute does not match the schema
+type AttributeError struct {
+	Attribute string
+	Message   string
+}
+
+func (e *AttributeError) Error() string {
+	return "attribute " + e.Attribute + " " + e.Message
+}
+
+// IsValid checks if the type is one of the known attribute types
+func (t AttributeType) IsValid() bool {
+	switch t {
+	case AttributeString, AttributeNumber, AttributeEnum, AttributeBoolean:
+		return true
+	}
+
+	return false
+}
+
+// Schema resolves the attributes of the last category in a root-first path.
+// A category may redefine an inherited attribute, the definition closest to
+// the category wins.
+func Schema(path []*Node) []SchemaAttribute {
+	if len(path) == 0 {
+		return []SchemaAttribute{}
+	}
+
+	categoryID := path[len(path)-1].ID
+	schema := []SchemaAttribute{}
+	index := map[string]int{}
+	for _, node := range path {
+		for _, definition := range node.Attributes {
+			attribute := SchemaAttribute{
+				AttributeDefinition: definition,
+				CategoryID:          node.ID,
+				Inherited:           node.ID != categoryID,
+			}
+
+			if i, ok := index[definition.Name]; ok {
+				schema[i] = attribute
+				continue
+			}
+
+			index[definition.Name] = len(schema)
+			schema = append(schema, attribute)
+		}
+	}
+
+	return schema
+}
+
+// ValidateValues checks attribute values against a schema. Values that are
+// not in the schema are rejected, a null value counts as not set.
+func ValidateValues(schema []SchemaAttribute, values map[string]interface{}) error {
+	defined := map[string]bool{}
+	for _, attribute := range schema {
+		defined[attribute.Name] = true
+
+		value, ok := values[attribute.Name]
+		if !ok || value == nil || value == "" {
+			if attribute.Required {
+				return &AttributeError{Attribute: attribute.Name, Message: "is required"}
+			}
+			continue
+		}
+
+		if err := attribute.Check(value); err != nil {
+			return err
+		}
+	}
+
+	for name := range values {
+		if !defined[name] {
+			return &AttributeError{Attribute: name, Message: "is not defined for the category"}
+		}
+	}
+
+	return nil
+}
+
+// Check verifies that a value decoded from JSON matches the definition
+func (d AttributeDefinition) Check(value interface{}) error {
+	switch d.Type {
+	case AttributeString:
+		if _, ok := value.(string); !ok {
+			return &AttributeError{Attribute: d.Name, Message: "must be a string"}
+		}
+	case AttributeNumber:
+		if _, ok := number(value); !ok {
+			return &AttributeError{Attribute: d.Name, Message: "must be a number"}
+		}
+	case AttributeBoolean:
+		if _, ok := value.(bool); !ok {
+			return &AttributeError{Attribute: d.Name, Message: "must be true or false"}
+		}
+	case AttributeEnum:
+		text, _ := value.(string)
+		for _, allowed := range d.Values {
+			if text == allowed {
+				return nil
+			}
+		}
+		return &AttributeError{Attribute: d.Name, Message: "must be one of the allowed values"}
+	}
+
+	return nil
+}
+
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case json.Number:
+		f, err := strconv.ParseFloat(string(v), 64)
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+// Value implements driver.Valuer.
+func (d AttributeDefinitions) Value() (driver.Value, error) {
+	if d == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(d)
+}
+
+// Scan implements sql.Scanner.
+func (d *AttributeDefinitions) Scan(src interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, d)
+	case string:
+		return json.Unmarshal([]byte(data), d)
+	}
+
+	return errors.New("unsupported type for jsonb column")
+}
@@ -3,18 +3,62 @@ package category
 import (
 	"database/sql"
 	"time"
+
+	"ecosystem.garyle/service/pkg/utils/filter"
 )
 
 type Category struct {
-	ID        int          `json:"id" db:"id"`
-	Name      string       `json:"name" db:"name"`
-	ParentID  *int         `json:"parent_id" db:"parent_id"`
-	CreatedAt time.Time    `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
-	DeletedAt sql.NullTime `json:"deleted_at" db:"deleted_at"`
+	ID         int                  `json:"id" db:"id"`
+	Name       string               `json:"name" db:"name"`
+	ParentID   *int                 `json:"parent_id" db:"parent_id"`
+	Attributes AttributeDefinitions `json:"attributes,omitempty" db:"attributes"` // product attributes, inherited by child categories
+	CreatedAt  time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time            `json:"updated_at" db:"updated_at"`
+	DeletedAt  sql.NullTime         `json:"deleted_at" db:"deleted_at"`
 }
 
 // IsDeleted checks if the location is deleted
 func (c *Category) IsDeleted() bool {
 	return c.DeletedAt.Valid
 }
+
+// QuerySpec whitelists the fields that can be used to filter and sort the category list
+var QuerySpec = filter.Spec{
+	Fields: map[string]filter.Field{
+		"id":         {Column: "id", Type: filter.Integer},
+		"name":       {Column: "name", Type: filter.String},
+		"parent_id":  {Column: "parent_id", Type: filter.Integer},
+		"created_at": {Column: "created_at", Type: filter.Time},
+		"updated_at": {Column: "updated_at", Type: filter.Time},
+	},
+	DefaultSort: []filter.Sort{{Column: "created_at", Desc: true}},
+}
+
+// ExportColumns lists the columns of a category export in their default order
+var ExportColumns = []string{"id", "name", "parent_id", "created_at", "updated_at", "deleted_at"}
+
+// ExportValue returns the value of an export column
+func (c *Category) ExportValue(column string) interface{} {
+	switch column {
+	case "id":
+		return c.ID
+	case "name":
+		return c.Name
+	case "parent_id":
+		if c.ParentID != nil {
+			return *c.ParentID
+		}
+		return nil
+	case "created_at":
+		return c.CreatedAt
+	case "updated_at":
+		return c.UpdatedAt
+	case "deleted_at":
+		if c.DeletedAt.Valid {
+			return c.DeletedAt.Time
+		}
+		return nil
+	}
+
+	return nil
+}
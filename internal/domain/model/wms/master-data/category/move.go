@@ -0,0 +1,28 @@
+package category
+
+// MoveRequest moves a category under another parent, a nil parent makes it a root
+type MoveRequest struct {
+	ParentID *int `json:"parent_id"`
+}
+
+// DeletePolicy decides what happens to the children of a deleted category
+type DeletePolicy string
+
+const (
+	DeleteBlock    DeletePolicy = "block"    // refuse to delete a category that has children
+	DeleteCascade  DeletePolicy = "cascade"  // delete the whole subtree
+	DeleteReparent DeletePolicy = "reparent" // move the children up to the parent of the deleted category
+)
+
+// DefaultDeletePolicy is used when a delete names no policy
+const DefaultDeletePolicy = DeleteBlock
+
+// IsValid checks if the policy is one of the known policies
+func (p DeletePolicy) IsValid() bool {
+	switch p {
+	case DeleteBlock, DeleteCascade, DeleteReparent:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,42 @@
+package category
+
+import "sort"
+
+// Node is a category in the category tree, the depth of a root is 0
+type Node struct {
+	*Category
+	Depth    int     `json:"depth"`
+	Children []*Node `json:"children,omitempty"`
+}
+
+// BuildTree nests nodes under their parents. Nodes whose parent is not in the
+// list become roots, so a subtree has its top category as the only root.
+// Children are ordered by name.
+func BuildTree(nodes []*Node) []*Node {
+	byID := make(map[int]*Node, len(nodes))
+	for _, node := range nodes {
+		byID[node.ID] = node
+	}
+
+	roots := []*Node{}
+	for _, node := range nodes {
+		if node.ParentID != nil {
+			if parent, ok := byID[*node.ParentID]; ok && parent != node {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+
+		roots = append(roots, node)
+	}
+
+	sortByName(roots)
+	return roots
+}
+
+func sortByName(nodes []*Node) {
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	for _, node := range nodes {
+		sortByName(node.Children)
+	}
+}
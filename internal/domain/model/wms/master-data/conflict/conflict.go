@@ -0,0 +1,17 @@
+package conflict
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is returned when a write would give two active rows the same unique
+// key, e.g. two products with one sku. Deleted rows do not count.
+type Error struct {
+	Entity string   // e.g. product
+	Fields []string // e.g. sku
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("a %s with this %s already exists", e.Entity, strings.Join(e.Fields, " and "))
+}
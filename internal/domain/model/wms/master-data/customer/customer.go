@@ -3,13 +3,25 @@ package customer
 import (
 	"database/sql"
 	"time"
+
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
+	"ecosystem.garyle/service/pkg/utils/filter"
 )
 
 type Customer struct {
-	ID        int          `json:"id" db:"id"`
-	Name      string       `json:"name" db:"name"`
-	Address   string       `json:"address" db:"address"`
-	Contact   string       `json:"contact" db:"contact"`
+	ID      int    `json:"id" db:"id"`
+	Name    string `json:"name" db:"name"`
+	Address string `json:"address" db:"address"` // free text, see Addresses
+	Contact string `json:"contact" db:"contact"` // free text, see Contacts
+	party.Registration
+	Addresses []*party.Address `json:"addresses,omitempty" db:"-"`
+	Contacts  []*party.Contact `json:"contacts,omitempty" db:"-"`
+	// ServiceProfile is how the orders of the customer are served, see ServiceProfile
+	ServiceProfile *ServiceProfile `json:"service_profile,omitempty" db:"-"`
+	// PossibleDuplicates warns about likely duplicates when the customer is created
+	PossibleDuplicates []party.DuplicateMatch `json:"possible_duplicates,omitempty" db:"-"`
+	// ErasedAt is set when the personal data of the customer was erased
+	ErasedAt  sql.NullTime `json:"erased_at" db:"erased_at"`
 	CreatedAt time.Time    `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
 	DeletedAt sql.NullTime `json:"deleted_at" db:"deleted_at"`
@@ -18,3 +30,62 @@ type Customer struct {
 func (c *Customer) IsDeleted() bool {
 	return c.DeletedAt.Valid
 }
+
+// IsErased reports whether the personal data of the customer was erased
+func (c *Customer) IsErased() bool {
+	return c.ErasedAt.Valid
+}
+
+// QuerySpec whitelists the fields that can be used to filter and sort the customer list.
+// Address and contact are encrypted at rest and cannot be filtered on.
+var QuerySpec = filter.Spec{
+	Fields: map[string]filter.Field{
+		"id":            {Column: "id", Type: filter.Integer},
+		"name":          {Column: "name", Type: filter.String},
+		"tax_country":   {Column: "tax_country", Type: filter.String},
+		"tax_id":        {Column: "tax_id", Type: filter.String},
+		"payment_terms": {Column: "payment_terms", Type: filter.String},
+		"currency":      {Column: "currency", Type: filter.String},
+		"created_at":    {Column: "created_at", Type: filter.Time},
+		"updated_at":    {Column: "updated_at", Type: filter.Time},
+	},
+	DefaultSort: []filter.Sort{{Column: "created_at", Desc: true}},
+}
+
+// ExportColumns lists the columns of a customer export in their default order
+var ExportColumns = []string{"id", "name", "address", "contact", "tax_country", "tax_id", "registration_number", "payment_terms", "currency", "created_at", "updated_at", "deleted_at"}
+
+// ExportValue returns the value of an export column
+func (c *Customer) ExportValue(column string) interface{} {
+	switch column {
+	case "id":
+		return c.ID
+	case "name":
+		return c.Name
+	case "address":
+		return c.Address
+	case "contact":
+		return c.Contact
+	case "tax_country":
+		return c.TaxCountry
+	case "tax_id":
+		return c.TaxID
+	case "registration_number":
+		return c.RegistrationNumber
+	case "payment_terms":
+		return c.PaymentTerms
+	case "currency":
+		return c.Currency
+	case "created_at":
+		return c.CreatedAt
+	case "updated_at":
+		return c.UpdatedAt
+	case "deleted_at":
+		if c.DeletedAt.Valid {
+			return c.DeletedAt.Time
+		}
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,265 @@
+package customer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PriorityTier orders customers when outbound work competes for capacity
+type PriorityTier string
+
+const (
+	PriorityLow      PriorityTier = "low"
+	PriorityStandard PriorityTier = "standard"
+	PriorityHigh     PriorityTier = "high"
+	PriorityCritical PriorityTier = "critical"
+)
+
+// IsValid checks if the tier is one of the known tiers
+func (p PriorityTier) IsValid() bool {
+	switch p {
+	case PriorityLow, PriorityStandard, PriorityHigh, PriorityCritical:
+		return true
+	}
+
+	return false
+}
+
+// Rank is the tier as a number, higher is served first
+func (p PriorityTier) Rank() int {
+	switch p {
+	case PriorityLow:
+		return 1
+	case PriorityHigh:
+		return 3
+	case PriorityCritical:
+		return 4
+	}
+
+	return 2
+}
+
+// SplitPolicy tells whether an order may ship in several parts
+type SplitPolicy string
+
+const (
+	SplitAllowed      SplitPolicy = "allowed"       // ship what is available, the rest follows
+	SplitLineComplete SplitPolicy = "line_complete" // the order may split but each line ships complete
+	SplitShipComplete SplitPolicy = "ship_complete" // the whole order ships at once
+)
+
+// IsValid checks if the policy is one of the known policies
+func (s SplitPolicy) IsValid() bool {
+	switch s {
+	case SplitAllowed, SplitLineComplete, SplitShipComplete:
+		return true
+	}
+
+	return false
+}
+
+// DefaultTimezone is used for the cutoff time when a profile has no timezone
+const DefaultTimezone = "Asia/Jakarta"
+
+// DefaultDispatchDays are the weekdays a warehouse ships on
+var DefaultDispatchDays = []string{"mon", "tue", "wed", "thu", "fri"}
+
+// Weekdays maps the day names of DispatchDays to time.Weekday
+var Weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ServiceProfile is how outbound orders of a customer are served. A customer
+// without a profile gets DefaultServiceProfile.
+type ServiceProfile struct {
+	CustomerID int `json:"customer_id" db:"customer_id"`
+	// OrderCutoff is the local time, HH:MM, until which an order is dispatched
+	// the same day. Empty means any order of a dispatch day makes it.
+	OrderCutoff string `json:"order_cutoff" db:"order_cutoff"`
+	Timezone    string `json:"timezone" db:"timezone"` // IANA name, e.g. Asia/Jakarta
+	// DispatchLeadDays are the dispatch days between taking an order and
+	// dispatching it, 0 for same-day dispatch
+	DispatchLeadDays int      `json:"dispatch_lead_days" db:"dispatch_lead_days"`
+	DispatchDays     []string `json:"dispatch_days" db:"dispatch_days"` // e.g. mon, see Weekdays
+	// AllowedCarriers are carrier codes, e.g. JNE or SICEPAT. Empty allows any carrier.
+	AllowedCarriers      []string     `json:"allowed_carriers" db:"allowed_carriers"`
+	PreferredCarrier     string       `json:"preferred_carrier" db:"preferred_carrier"`
+	SplitPolicy          SplitPolicy  `json:"split_policy" db:"split_policy"`
+	LabelingInstructions string       `json:"labeling_instructions" db:"labeling_instructions"`
+	PackingInstructions  string       `json:"packing_instructions" db:"packing_instructions"`
+	PriorityTier         PriorityTier `json:"priority_tier" db:"priority_tier"`
+	CreatedAt            time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultServiceProfile is the profile of a customer without one
+func DefaultServiceProfile(customerID int) *ServiceProfile {
+	return &ServiceProfile{
+		CustomerID:      customerID,
+		Timezone:        DefaultTimezone,
+		DispatchDays:    append([]string{}, DefaultDispatchDays...),
+		AllowedCarriers: []string{},
+		SplitPolicy:     SplitAllowed,
+		PriorityTier:    PriorityStandard,
+	}
+}
+
+// Normalize fills in the defaults and writes day names and carrier codes the
+// way they are stored
+func (p *ServiceProfile) Normalize() {
+	if p.Timezone == "" {
+		p.Timezone = DefaultTimezone
+	}
+
+	if len(p.DispatchDays) == 0 {
+		p.DispatchDays = append([]string{}, DefaultDispatchDays...)
+	}
+	for i, day := range p.DispatchDays {
+		p.DispatchDays[i] = strings.ToLower(strings.TrimSpace(day))
+	}
+
+	if p.AllowedCarriers == nil {
+		p.AllowedCarriers = []string{}
+	}
+	for i, carrier := range p.AllowedCarriers {
+		p.AllowedCarriers[i] = strings.ToUpper(strings.TrimSpace(carrier))
+	}
+	p.PreferredCarrier = strings.ToUpper(strings.TrimSpace(p.PreferredCarrier))
+
+	if p.SplitPolicy == "" {
+		p.SplitPolicy = SplitAllowed
+	}
+
+	if p.PriorityTier == "" {
+		p.PriorityTier = PriorityStandard
+	}
+}
+
+// AllowsCarrier reports whether an order of the customer may ship with a carrier
+func (p *ServiceProfile) AllowsCarrier(carrier string) bool {
+	if len(p.AllowedCarriers) == 0 {
+		return true
+	}
+
+	carrier = strings.ToUpper(strings.TrimSpace(carrier))
+	for _, allowed := range p.AllowedCarriers {
+		if allowed == carrier {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DispatchBy returns the local date an order taken at orderedAt must be
+// dispatched on. An order after the cutoff or on a day without dispatch
+// counts from the next dispatch day.
+func (p *ServiceProfile) DispatchBy(orderedAt time.Time) (time.Time, error) {
+	location, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unknown timezone %s", p.Timezone)
+	}
+
+	local := orderedAt.In(location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+
+	if p.OrderCutoff != "" {
+		cutoff, err := time.Parse("15:04", p.OrderCutoff)
+		if err != nil {
+			return time.Time{}, errors.New("order_cutoff must use the HH:MM format")
+		}
+
+		if local.Hour()*60+local.Minute() > cutoff.Hour()*60+cutoff.Minute() {
+			day = day.AddDate(0, 0, 1)
+		}
+	}
+
+	dispatchDays := map[time.Weekday]bool{}
+	for _, name := range p.DispatchDays {
+		dispatchDays[Weekdays[name]] = true
+	}
+	if len(dispatchDays) == 0 {
+		return time.Time{}, errors.New("dispatch_days must not be empty")
+	}
+
+	for !dispatchDays[day.Weekday()] {
+		day = day.AddDate(0, 0, 1)
+	}
+
+	for i := 0; i < p.DispatchLeadDays; i++ {
+		day = day.AddDate(0, 0, 1)
+		for !dispatchDays[day.Weekday()] {
+			day = day.AddDate(0, 0, 1)
+		}
+	}
+
+	return day, nil
+}
+
+// OrderCheck is an outbound order checked against the profile of its customer
+type OrderCheck struct {
+	OrderedAt time.Time `json:"ordered_at"`
+	Carrier   string    `json:"carrier"` // optional
+	Partial   bool      `json:"partial"` // whether the shipment would leave part of the order behind
+	// PartialLines whether a line would ship with less than its quantity
+	PartialLines bool `json:"partial_lines"`
+}
+
+// OrderDecision is what outbound processing must respect for an order
+type OrderDecision struct {
+	CustomerID           int          `json:"customer_id"`
+	DispatchBy           string       `json:"dispatch_by"` // local date, YYYY-MM-DD
+	SameDay              bool         `json:"same_day"`
+	Carrier              string       `json:"carrier"` // the requested carrier, or the preferred one
+	CarrierAllowed       bool         `json:"carrier_allowed"`
+	ShipmentAllowed      bool         `json:"shipment_allowed"` // false when the split policy forbids the partial shipment
+	PriorityTier         PriorityTier `json:"priority_tier"`
+	PriorityRank         int          `json:"priority_rank"`
+	LabelingInstructions string       `json:"labeling_instructions"`
+	PackingInstructions  string       `json:"packing_instructions"`
+}
+
+// Decide checks an order against the profile
+func (p *ServiceProfile) Decide(check OrderCheck) (*OrderDecision, error) {
+	dispatchBy, err := p.DispatchBy(check.OrderedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	location, _ := time.LoadLocation(p.Timezone)
+	ordered := check.OrderedAt.In(location)
+
+	carrier := strings.ToUpper(strings.TrimSpace(check.Carrier))
+	if carrier == "" {
+		carrier = p.PreferredCarrier
+	}
+
+	shipmentAllowed := true
+	switch p.SplitPolicy {
+	case SplitShipComplete:
+		shipmentAllowed = !check.Partial && !check.PartialLines
+	case SplitLineComplete:
+		shipmentAllowed = !check.PartialLines
+	}
+
+	return &OrderDecision{
+		CustomerID:           p.CustomerID,
+		DispatchBy:           dispatchBy.Format("2006-01-02"),
+		SameDay:              dispatchBy.Year() == ordered.Year() && dispatchBy.YearDay() == ordered.YearDay(),
+		Carrier:              carrier,
+		CarrierAllowed:       carrier == "" || p.AllowsCarrier(carrier),
+		ShipmentAllowed:      shipmentAllowed,
+		PriorityTier:         p.PriorityTier,
+		PriorityRank:         p.PriorityTier.Rank(),
+		LabelingInstructions: p.LabelingInstructions,
+		PackingInstructions:  p.PackingInstructions,
+	}, nil
+}
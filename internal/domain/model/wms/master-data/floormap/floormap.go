@@ -0,0 +1,22 @@
+package floormap
+
+import (
+	"time"
+
+	locationModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
+)
+
+// FloorMap is the walkway graph of a warehouse with the places of its locations
+type FloorMap struct {
+	WarehouseID int                `json:"warehouse_id"`
+	Graph       Graph              `json:"graph"`
+	Locations   []LocationPosition `json:"locations"`
+	UpdatedAt   *time.Time         `json:"updated_at"`
+}
+
+// LocationPosition places a location, named by id or code, on the floor map
+type LocationPosition struct {
+	LocationID int    `json:"location_id"`
+	Code       string `json:"code"`
+	locationModel.Coordinates
+}
@@ -0,0 +1,258 @@
+package floormap
+
+import (
+	"container/heap"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	locationModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
+)
+
+// Node is a point of the walkway network, e.g. an aisle end or a crossing
+type Node struct {
+	ID string  `json:"id"`
+	X  float64 `json:"x"` // m
+	Y  float64 `json:"y"` // m
+}
+
+// Edge is a walkway between two nodes, it can be walked both ways
+type Edge struct {
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Length *float64 `json:"length"` // m, defaults to the straight distance between the nodes
+}
+
+// Graph is the aisle and walkway network of a warehouse floor
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Value implements driver.Valuer, the graph is stored as JSONB
+func (g Graph) Value() (driver.Value, error) {
+	return json.Marshal(g)
+}
+
+// Scan implements sql.Scanner
+func (g *Graph) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*g = Graph{}
+		return nil
+	case []byte:
+		return json.Unmarshal(data, g)
+	case string:
+		return json.Unmarshal([]byte(data), g)
+	}
+
+	return fmt.Errorf("cannot scan %T into a floor graph", value)
+}
+
+// Validate checks that node ids are unique and that every edge joins two
+// different known nodes
+func (g *Graph) Validate() error {
+	nodes := make(map[string]bool, len(g.Nodes))
+	for _, node := range g.Nodes {
+		if node.ID == "" {
+			return errors.New("every graph node needs an id")
+		}
+
+		if nodes[node.ID] {
+			return fmt.Errorf("graph node %s is defined twice", node.ID)
+		}
+		nodes[node.ID] = true
+	}
+
+	for _, edge := range g.Edges {
+		if !nodes[edge.From] || !nodes[edge.To] {
+			return fmt.Errorf("graph edge %s-%s joins an unknown node", edge.From, edge.To)
+		}
+
+		if edge.From == edge.To {
+			return fmt.Errorf("graph edge %s-%s joins a node to itself", edge.From, edge.To)
+		}
+
+		if edge.Length != nil && *edge.Length < 0 {
+			return fmt.Errorf("graph edge %s-%s has a negative length", edge.From, edge.To)
+		}
+	}
+
+	return nil
+}
+
+// Route is the walking route between two locations
+type Route struct {
+	FromLocationID int      `json:"from_location_id"`
+	ToLocationID   int      `json:"to_location_id"`
+	Distance       float64  `json:"distance"` // m
+	Path           []string `json:"path"`     // the graph nodes walked past
+}
+
+// ShortestRoute returns the walking distance between two points and the nodes
+// walked past. Each point steps onto the nearest walkway, then the route
+// follows the walkways to the walkway nearest to the other point.
+func (g *Graph) ShortestRoute(from, to locationModel.Coordinates) (float64, []string, error) {
+	start, ok := g.nearestEdge(from)
+	if !ok {
+		return 0, nil, errors.New("floor map has no walkways")
+	}
+
+	end, _ := g.nearestEdge(to)
+
+	// both points step onto the same walkway, walk straight along it
+	direct := math.Inf(1)
+	if start.edge == end.edge {
+		direct = start.offset + math.Abs(start.position-end.position)*start.length + end.offset
+	}
+
+	index := make(map[string]int, len(g.Nodes))
+	for i, node := range g.Nodes {
+		index[node.ID] = i
+	}
+
+	// the start and end points are added as two extra nodes
+	source, target := len(g.Nodes), len(g.Nodes)+1
+	adjacency := make([][]arc, len(g.Nodes)+2)
+	for i, edge := range g.Edges {
+		length := g.edgeLength(i)
+		a, b := index[edge.From], index[edge.To]
+		adjacency[a] = append(adjacency[a], arc{to: b, length: length})
+		adjacency[b] = append(adjacency[b], arc{to: a, length: length})
+	}
+
+	edge := g.Edges[start.edge]
+	adjacency[source] = append(adjacency[source],
+		arc{to: index[edge.From], length: start.offset + start.position*start.length},
+		arc{to: index[edge.To], length: start.offset + (1-start.position)*start.length},
+	)
+
+	edge = g.Edges[end.edge]
+	adjacency[index[edge.From]] = append(adjacency[index[edge.From]], arc{to: target, length: end.offset + end.position*end.length})
+	adjacency[index[edge.To]] = append(adjacency[index[edge.To]], arc{to: target, length: end.offset + (1-end.position)*end.length})
+
+	distance, previous := dijkstra(adjacency, source)
+	if math.IsInf(distance[target], 1) {
+		return 0, nil, errors.New("locations are not connected by walkways")
+	}
+
+	if direct <= distance[target] {
+		return direct, []string{}, nil
+	}
+
+	path := []string{}
+	for node := previous[target]; node != source; node = previous[node] {
+		path = append([]string{g.Nodes[node].ID}, path...)
+	}
+
+	return distance[target], path, nil
+}
+
+// projection is where a point steps onto a walkway
+type projection struct {
+	edge     int
+	position float64 // 0 at the from node, 1 at the to node
+	offset   float64 // m, from the point to the walkway
+	length   float64 // m, walking length of the walkway
+}
+
+func (g *Graph) nearestEdge(point locationModel.Coordinates) (projection, bool) {
+	nodes := make(map[string]Node, len(g.Nodes))
+	for _, node := range g.Nodes {
+		nodes[node.ID] = node
+	}
+
+	best := projection{offset: math.Inf(1)}
+	for i, edge := range g.Edges {
+		a, b := nodes[edge.From], nodes[edge.To]
+		dx, dy := b.X-a.X, b.Y-a.Y
+
+		position := 0.0
+		if squared := dx*dx + dy*dy; squared > 0 {
+			position = ((point.X-a.X)*dx + (point.Y-a.Y)*dy) / squared
+			position = math.Max(0, math.Min(1, position))
+		}
+
+		offset := math.Hypot(point.X-(a.X+position*dx), point.Y-(a.Y+position*dy))
+		if offset < best.offset {
+			best = projection{edge: i, position: position, offset: offset, length: g.edgeLength(i)}
+		}
+	}
+
+	return best, len(g.Edges) > 0
+}
+
+func (g *Graph) edgeLength(i int) float64 {
+	edge := g.Edges[i]
+	if edge.Length != nil {
+		return *edge.Length
+	}
+
+	var from, to Node
+	for _, node := range g.Nodes {
+		if node.ID == edge.From {
+			from = node
+		}
+		if node.ID == edge.To {
+			to = node
+		}
+	}
+
+	return math.Hypot(to.X-from.X, to.Y-from.Y)
+}
+
+type arc struct {
+	to     int
+	length float64
+}
+
+// dijkstra returns the shortest distance from source to every node and the
+// node each one is reached from
+func dijkstra(adjacency [][]arc, source int) ([]float64, []int) {
+	distance := make([]float64, len(adjacency))
+	previous := make([]int, len(adjacency))
+	for i := range distance {
+		distance[i] = math.Inf(1)
+		previous[i] = -1
+	}
+	distance[source] = 0
+
+	queue := &nodeQueue{{node: source}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(queued)
+		if current.distance > distance[current.node] {
+			continue
+		}
+
+		for _, next := range adjacency[current.node] {
+			if candidate := current.distance + next.length; candidate < distance[next.to] {
+				distance[next.to] = candidate
+				previous[next.to] = current.node
+				heap.Push(queue, queued{node: next.to, distance: candidate})
+			}
+		}
+	}
+
+	return distance, previous
+}
+
+type queued struct {
+	node     int
+	distance float64
+}
+
+// nodeQueue is a min-heap of nodes by distance
+type nodeQueue []queued
+
+func (q nodeQueue) Len() int            { return len(q) }
+func (q nodeQueue) Less(i, j int) bool  { return q[i].distance < q[j].distance }
+func (q nodeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x interface{}) { *q = append(*q, x.(queued)) }
+func (q *nodeQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
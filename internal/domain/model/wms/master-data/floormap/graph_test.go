@@ -0,0 +1,117 @@
+package floormap
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	locationModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
+)
+
+func TestShortestRoute(t *testing.T) {
+	// two aisles joined at the back: A0-A1 and B0-B1, 20 m long and 10 m apart
+	aisles := Graph{
+		Nodes: []Node{{ID: "A0", X: 0, Y: 0}, {ID: "A1", X: 0, Y: 20}, {ID: "B1", X: 10, Y: 20}, {ID: "B0", X: 10, Y: 0}},
+		Edges: []Edge{{From: "A0", To: "A1"}, {From: "A1", To: "B1"}, {From: "B1", To: "B0"}},
+	}
+
+	// the same aisles with a short cut at the front that is longer than it looks
+	detour := 30.0
+	withFront := Graph{
+		Nodes: aisles.Nodes,
+		Edges: append(append([]Edge{}, aisles.Edges...), Edge{From: "A0", To: "B0", Length: &detour}),
+	}
+
+	disconnected := Graph{
+		Nodes: []Node{{ID: "A0", X: 0, Y: 0}, {ID: "A1", X: 0, Y: 20}, {ID: "B0", X: 10, Y: 0}, {ID: "B1", X: 10, Y: 20}},
+		Edges: []Edge{{From: "A0", To: "A1"}, {From: "B0", To: "B1"}},
+	}
+
+	tests := []struct {
+		name     string
+		graph    Graph
+		from, to locationModel.Coordinates
+		distance float64
+		path     []string
+		err      string
+	}{
+		{
+			name:     "same walkway",
+			graph:    aisles,
+			from:     locationModel.Coordinates{X: 1, Y: 2},
+			to:       locationModel.Coordinates{X: 1, Y: 8},
+			distance: 8,
+			path:     []string{},
+		},
+		{
+			name:     "height does not count",
+			graph:    aisles,
+			from:     locationModel.Coordinates{X: 1, Y: 2, Z: 4},
+			to:       locationModel.Coordinates{X: 1, Y: 8},
+			distance: 8,
+			path:     []string{},
+		},
+		{
+			name:     "around the back",
+			graph:    aisles,
+			from:     locationModel.Coordinates{X: 1, Y: 5},
+			to:       locationModel.Coordinates{X: 9, Y: 5},
+			distance: 42,
+			path:     []string{"A1", "B1"},
+		},
+		{
+			name:     "shorter walkway across the front",
+			graph:    withFront,
+			from:     locationModel.Coordinates{X: 1, Y: 5},
+			to:       locationModel.Coordinates{X: 9, Y: 5},
+			distance: 42,
+			path:     []string{"A1", "B1"},
+		},
+		{
+			name:     "edge length overrides the straight distance",
+			graph:    withFront,
+			from:     locationModel.Coordinates{X: 1, Y: 1},
+			to:       locationModel.Coordinates{X: 9, Y: 1},
+			distance: 34,
+			path:     []string{"A0", "B0"},
+		},
+		{
+			name:  "not connected",
+			graph: disconnected,
+			from:  locationModel.Coordinates{X: 1, Y: 5},
+			to:    locationModel.Coordinates{X: 9, Y: 5},
+			err:   "locations are not connected by walkways",
+		},
+		{
+			name:  "no walkways",
+			graph: Graph{Nodes: aisles.Nodes},
+			from:  locationModel.Coordinates{X: 1, Y: 5},
+			to:    locationModel.Coordinates{X: 9, Y: 5},
+			err:   "floor map has no walkways",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			distance, path, err := tt.graph.ShortestRoute(tt.from, tt.to)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if math.Abs(distance-tt.distance) > 1e-9 {
+				t.Errorf("distance = %g, want %g", distance, tt.distance)
+			}
+
+			if !reflect.DeepEqual(path, tt.path) {
+				t.Errorf("path = %v, want %v", path, tt.path)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package floormap
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+)
+
+// pixelsPerMetre is the scale of the rendered floor layout
+const pixelsPerMetre = 20
+
+// RenderSVG draws the walkways, their nodes and the locations of the floor
+// map. The drawing uses metres as user units, y grows downwards.
+func (f *FloorMap) RenderSVG(w io.Writer) error {
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	extend := func(x, y float64) {
+		minX, minY = math.Min(minX, x), math.Min(minY, y)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+	}
+
+	nodes := make(map[string]Node, len(f.Graph.Nodes))
+	for _, node := range f.Graph.Nodes {
+		nodes[node.ID] = node
+		extend(node.X, node.Y)
+	}
+
+	for _, position := range f.Locations {
+		extend(position.X, position.Y)
+	}
+
+	if math.IsInf(minX, 1) {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+
+	// leave a border of 2 m around the drawing
+	minX, minY, maxX, maxY = minX-2, minY-2, maxX+2, maxY+2
+	width, height := maxX-minX, maxY-minY
+
+	buffer := bufio.NewWriter(w)
+	fmt.Fprintf(buffer, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="%g %g %g %g">`+"\n",
+		width*pixelsPerMetre, height*pixelsPerMetre, minX, minY, width, height)
+	fmt.Fprintf(buffer, `<rect x="%g" y="%g" width="%g" height="%g" fill="#ffffff"/>`+"\n", minX, minY, width, height)
+
+	fmt.Fprint(buffer, `<g stroke="#9e9e9e" stroke-width="0.6" stroke-linecap="round">`+"\n")
+	for _, edge := range f.Graph.Edges {
+		from, to := nodes[edge.From], nodes[edge.To]
+		fmt.Fprintf(buffer, `<line x1="%g" y1="%g" x2="%g" y2="%g"/>`+"\n", from.X, from.Y, to.X, to.Y)
+	}
+	fmt.Fprint(buffer, "</g>\n")
+
+	fmt.Fprint(buffer, `<g fill="#616161" font-family="sans-serif" font-size="0.5">`+"\n")
+	for _, node := range f.Graph.Nodes {
+		fmt.Fprintf(buffer, `<circle cx="%g" cy="%g" r="0.3"/><text x="%g" y="%g">%s</text>`+"\n",
+			node.X, node.Y, node.X+0.4, node.Y-0.4, html.EscapeString(node.ID))
+	}
+	fmt.Fprint(buffer, "</g>\n")
+
+	fmt.Fprint(buffer, `<g fill="#1e88e5" font-family="sans-serif" font-size="0.4">`+"\n")
+	for _, position := range f.Locations {
+		fmt.Fprintf(buffer, `<rect x="%g" y="%g" width="0.8" height="0.8"><title>%s</title></rect><text x="%g" y="%g">%s</text>`+"\n",
+			position.X-0.4, position.Y-0.4, html.EscapeString(position.Code), position.X+0.5, position.Y+0.15, html.EscapeString(position.Code))
+	}
+	fmt.Fprint(buffer, "</g>\n</svg>\n")
+
+	return buffer.Flush()
+}
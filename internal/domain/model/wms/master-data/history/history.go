@@ -0,0 +1,149 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AuthorHeader is the request header that names the author of a change
+const AuthorHeader = "X-Changed-By"
+
+// maxAuthorLength is the length of record_versions.changed_by
+const maxAuthorLength = 100
+
+// Entity is a master-data table whose updates are versioned
+type Entity struct {
+	Name     string // used in the route, e.g. products
+	Singular string // used in messages, e.g. product
+	Table    string
+	// Encrypted are the snapshot fields encrypted at rest, see fieldcrypt
+	Encrypted []string
+}
+
+// Entities are the master-data entities with a change history. Every update
+// of their rows, by any route, records a version in the database.
+var Entities = []Entity{
+	{Name: "products", Singular: "product", Table: "products"},
+	{Name: "locations", Singular: "location", Table: "locations"},
+	{Name: "suppliers", Singular: "supplier", Table: "suppliers"},
+	{Name: "customers", Singular: "customer", Table: "customers", Encrypted: []string{"address", "contact"}},
+	{Name: "categories", Singular: "category", Table: "categories"},
+}
+
+// FindEntity returns the entity with the name used in the route
+func FindEntity(name string) (Entity, bool) {
+	for _, entity := range Entities {
+		if entity.Name == name {
+			return entity, true
+		}
+	}
+
+	return Entity{}, false
+}
+
+// EntityNames lists the names of Entities, sorted
+func EntityNames() []string {
+	names := make([]string, 0, len(Entities))
+	for _, entity := range Entities {
+		names = append(names, entity.Name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Version is a snapshot of a row, effective from ChangedAt until the
+// ChangedAt of the next version. Version 1 is the row before its first
+// recorded update, or the current row when it was never updated.
+type Version struct {
+	Entity    string                 `json:"entity"`
+	RecordID  int                    `json:"record_id"`
+	Version   int                    `json:"version"`
+	ChangedBy string                 `json:"changed_by"` // empty when the change was not made through an update route
+	ChangedAt time.Time              `json:"changed_at"`
+	Snapshot  map[string]interface{} `json:"snapshot"`
+}
+
+// Change is a field that differs between two versions
+type Change struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// Diff are the field changes from one version of a row to another
+type Diff struct {
+	Entity   string   `json:"entity"`
+	RecordID int      `json:"record_id"`
+	From     *Version `json:"from"`
+	To       *Version `json:"to"`
+	Changes  []Change `json:"changes"`
+}
+
+// Compare lists the fields that differ between two versions, by field name
+func Compare(from, to *Version) *Diff {
+	fields := map[string]bool{}
+	for field := range from.Snapshot {
+		fields[field] = true
+	}
+	for field := range to.Snapshot {
+		fields[field] = true
+	}
+
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	changes := []Change{}
+	for _, field := range names {
+		before, after := from.Snapshot[field], to.Snapshot[field]
+		if !reflect.DeepEqual(before, after) {
+			changes = append(changes, Change{Field: field, From: before, To: after})
+		}
+	}
+
+	return &Diff{Entity: to.Entity, RecordID: to.RecordID, From: from, To: to, Changes: changes}
+}
+
+// ParseAt reads the time of an as-of read. A date, YYYY-MM-DD, means the end
+// of that day in UTC.
+func ParseAt(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return now, nil
+	}
+
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, nil
+	}
+
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, errors.New("at must be an RFC 3339 time or a YYYY-MM-DD date")
+	}
+
+	return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
+
+type authorKey struct{}
+
+// WithAuthor returns a context that names the author of the changes made with it
+func WithAuthor(ctx context.Context, author string) context.Context {
+	author = strings.TrimSpace(author)
+	if len(author) > maxAuthorLength {
+		author = author[:maxAuthorLength]
+	}
+
+	return context.WithValue(ctx, authorKey{}, author)
+}
+
+// AuthorFrom returns the author set with WithAuthor, empty when none was set
+func AuthorFrom(ctx context.Context) string {
+	author, _ := ctx.Value(authorKey{}).(string)
+	return author
+}
@@ -0,0 +1,66 @@
+package imports
+
+import (
+	"errors"
+	"time"
+)
+
+// Mode decides what happens to the valid rows when some rows are invalid
+type Mode string
+
+const (
+	AllOrNothing Mode = "all_or_nothing" // nothing is saved when any row fails
+	SkipInvalid  Mode = "skip_invalid"   // invalid rows are skipped, the rest is saved
+)
+
+// ErrAborted is returned by a batch insert that was rolled back because a row failed
+var ErrAborted = errors.New("import aborted, no rows were saved")
+
+type Options struct {
+	Mode   Mode `json:"mode"`
+	DryRun bool `json:"dry_run"`
+}
+
+// RowError is a validation or database error of a single file row
+type RowError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+type Result struct {
+	Entity      string     `json:"entity"`
+	Mode        Mode       `json:"mode"`
+	DryRun      bool       `json:"dry_run"`
+	TotalRows   int        `json:"total_rows"`
+	ValidRows   int        `json:"valid_rows"`
+	InvalidRows int        `json:"invalid_rows"`
+	Imported    int        `json:"imported"`
+	Errors      []RowError `json:"errors"`
+}
+
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+)
+
+// Job is a background import of a large file
+type Job struct {
+	ID         string     `json:"id"`
+	Entity     string     `json:"entity"`
+	Status     JobStatus  `json:"status"`
+	Processed  int        `json:"processed"`
+	Total      int        `json:"total"`
+	Result     *Result    `json:"result,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// IsFinished checks if the job is no longer running
+func (j *Job) IsFinished() bool {
+	return j.Status == JobCompleted || j.Status == JobFailed
+}
@@ -0,0 +1,15 @@
+package location
+
+// StorageCapabilities describes what a location can store
+type StorageCapabilities struct {
+	MinTemperature *float64 `json:"min_temperature"` // °C, the coldest the location gets, nil when not controlled
+	MaxTemperature *float64 `json:"max_temperature"` // °C, the warmest the location gets, nil when not controlled
+	HazmatClasses  []string `json:"hazmat_classes"`  // UN hazard classes allowed here, "3" allows 3 and "2" allows every division of 2
+	IsBlockStacked bool     `json:"is_block_stacked"`
+	AcceptsFragile *bool    `json:"accepts_fragile"` // defaults to true
+}
+
+// AllowsFragile reports whether fragile products may be stored, they are allowed unless set otherwise
+func (c StorageCapabilities) AllowsFragile() bool {
+	return c.AcceptsFragile == nil || *c.AcceptsFragile
+}
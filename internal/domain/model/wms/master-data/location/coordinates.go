@@ -0,0 +1,15 @@
+package location
+
+import "math"
+
+// Coordinates place a location on the floor map of its warehouse
+type Coordinates struct {
+	X float64 `json:"x"` // m, along the floor
+	Y float64 `json:"y"` // m, across the floor
+	Z float64 `json:"z"` // m, height above the floor, it does not add to walking distances
+}
+
+// FloorDistance is the straight distance to other on the floor
+func (c Coordinates) FloorDistance(other Coordinates) float64 {
+	return math.Hypot(c.X-other.X, c.Y-other.Y)
+}
@@ -0,0 +1,179 @@
+package location
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxGeneratedLocations limits how many codes a single pattern may expand to
+const MaxGeneratedLocations = 10000
+
+// GenerateRequest describes locations to generate from a code pattern. With
+// a warehouse or parent the pattern expands to the segments of locations at
+// level in the hierarchy, their codes are built from the path like on create.
+type GenerateRequest struct {
+	Pattern      string               `json:"pattern"` // e.g. A-{01..20}-{L1..L5}-{B01..B10}, or B{01..10} for segments
+	WarehouseID  *int                 `json:"warehouse_id"`
+	ParentID     *int                 `json:"parent_id"`
+	Level        Level                `json:"level"`
+	Zone         string               `json:"zone"`
+	Type         string               `json:"type"`
+	Capacity     float64              `json:"capacity"`
+	Capabilities *StorageCapabilities `json:"capabilities"`
+	Limits       *Limits              `json:"limits"`
+	SkipExisting bool                 `json:"skip_existing"` // leave out codes that exist instead of refusing the whole batch
+}
+
+// IsHierarchical checks if the pattern expands to segments in a hierarchy
+func (r *GenerateRequest) IsHierarchical() bool {
+	return r.WarehouseID != nil || r.ParentID != nil
+}
+
+// GeneratedCode is a code expanded from a pattern
+type GeneratedCode struct {
+	Code   string `json:"code"`
+	Exists bool   `json:"exists"` // collides with an existing location code
+}
+
+// GenerateResult is the preview or outcome of a generation
+type GenerateResult struct {
+	Pattern    string          `json:"pattern"`
+	Total      int             `json:"total"`
+	Collisions int             `json:"collisions"`
+	Created    int             `json:"created"`
+	Codes      []GeneratedCode `json:"codes"`
+}
+
+// ExpandPattern expands every {..} group of pattern and returns the codes in
+// order. A group is a range such as {01..20}, {L1..L5} or {A..F}, or a list
+// such as {A,C,E}. Zero padding of a numeric range is kept.
+func ExpandPattern(pattern string) ([]string, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return nil, errors.New("pattern is required")
+	}
+
+	codes := []string{""}
+	rest := pattern
+	for rest != "" {
+		open := strings.Index(rest, "{")
+		if open < 0 {
+			if strings.Contains(rest, "}") {
+				return nil, errors.New("pattern has an unmatched '}'")
+			}
+			codes = appendAll(codes, []string{rest})
+			break
+		}
+
+		end := strings.Index(rest[open:], "}")
+		if end < 0 {
+			return nil, errors.New("pattern has an unmatched '{'")
+		}
+		end += open
+
+		if strings.Contains(rest[:open], "}") {
+			return nil, errors.New("pattern has an unmatched '}'")
+		}
+
+		values, err := expandGroup(rest[open+1 : end])
+		if err != nil {
+			return nil, err
+		}
+
+		if len(codes)*len(values) > MaxGeneratedLocations {
+			return nil, fmt.Errorf("pattern expands to more than %d locations", MaxGeneratedLocations)
+		}
+
+		codes = appendAll(codes, []string{rest[:open]})
+		codes = appendAll(codes, values)
+		rest = rest[end+1:]
+	}
+
+	return codes, nil
+}
+
+func appendAll(prefixes, values []string) []string {
+	result := make([]string, 0, len(prefixes)*len(values))
+	for _, prefix := range prefixes {
+		for _, value := range values {
+			result = append(result, prefix+value)
+		}
+	}
+
+	return result
+}
+
+func expandGroup(group string) ([]string, error) {
+	if strings.Contains(group, ",") {
+		values := strings.Split(group, ",")
+		for i, value := range values {
+			values[i] = strings.TrimSpace(value)
+			if values[i] == "" {
+				return nil, fmt.Errorf("empty value in {%s}", group)
+			}
+		}
+		return values, nil
+	}
+
+	from, to, ok := strings.Cut(group, "..")
+	if !ok {
+		return nil, fmt.Errorf("invalid group {%s}, use a range such as {01..20} or a list such as {A,B}", group)
+	}
+
+	// a single letter range, e.g. {A..F}
+	if len(from) == 1 && len(to) == 1 && isLetter(from[0]) && isLetter(to[0]) {
+		if from[0] > to[0] {
+			return nil, fmt.Errorf("invalid range {%s}, the start is after the end", group)
+		}
+
+		values := []string{}
+		for c := from[0]; c <= to[0]; c++ {
+			values = append(values, string(c))
+		}
+		return values, nil
+	}
+
+	// a numeric range with an optional common prefix, e.g. {B01..B10}
+	fromPrefix, fromDigits := splitDigits(from)
+	toPrefix, toDigits := splitDigits(to)
+	if fromDigits == "" || toDigits == "" || fromPrefix != toPrefix {
+		return nil, fmt.Errorf("invalid range {%s}", group)
+	}
+
+	start, _ := strconv.Atoi(fromDigits)
+	stop, _ := strconv.Atoi(toDigits)
+	if start > stop {
+		return nil, fmt.Errorf("invalid range {%s}, the start is after the end", group)
+	}
+
+	if stop-start+1 > MaxGeneratedLocations {
+		return nil, fmt.Errorf("pattern expands to more than %d locations", MaxGeneratedLocations)
+	}
+
+	width := 0
+	if strings.HasPrefix(fromDigits, "0") || strings.HasPrefix(toDigits, "0") {
+		width = max(len(fromDigits), len(toDigits))
+	}
+
+	values := make([]string, 0, stop-start+1)
+	for n := start; n <= stop; n++ {
+		values = append(values, fmt.Sprintf("%s%0*d", fromPrefix, width, n))
+	}
+
+	return values, nil
+}
+
+// splitDigits splits a trailing number from its prefix, e.g. L04 into L and 04
+func splitDigits(value string) (string, string) {
+	i := len(value)
+	for i > 0 && value[i-1] >= '0' && value[i-1] <= '9' {
+		i--
+	}
+
+	return value[:i], value[i:]
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
@@ -0,0 +1,70 @@
+package location
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+		count   int // checked instead of want for large expansions
+		err     string
+	}{
+		{name: "no groups", pattern: "DOCK-1", want: []string{"DOCK-1"}},
+		{name: "zero padded range", pattern: "A-{08..11}", want: []string{"A-08", "A-09", "A-10", "A-11"}},
+		{name: "unpadded range", pattern: "R{9..11}", want: []string{"R9", "R10", "R11"}},
+		{name: "range with prefix", pattern: "{L1..L3}", want: []string{"L1", "L2", "L3"}},
+		{name: "padded range with prefix", pattern: "{B01..B03}", want: []string{"B01", "B02", "B03"}},
+		{name: "letter range", pattern: "{A..C}-1", want: []string{"A-1", "B-1", "C-1"}},
+		{name: "list", pattern: "{A, C,E}", want: []string{"A", "C", "E"}},
+		{
+			name:    "groups combine in order",
+			pattern: "A-{01..02}-{L1..L2}",
+			want:    []string{"A-01-L1", "A-01-L2", "A-02-L1", "A-02-L2"},
+		},
+		{name: "full rack", pattern: "A-{01..20}-{L1..L5}-{B01..B10}", count: 1000},
+		{name: "at the limit", pattern: "{1..100}{1..100}", count: MaxGeneratedLocations},
+		{name: "empty", pattern: "  ", err: "pattern is required"},
+		{name: "unmatched open", pattern: "A-{01..02", err: "unmatched '{'"},
+		{name: "unmatched close", pattern: "A-01..02}", err: "unmatched '}'"},
+		{name: "close before open", pattern: "A}-{1..2}", err: "unmatched '}'"},
+		{name: "not a range", pattern: "{A}", err: "invalid group {A}"},
+		{name: "reversed range", pattern: "{10..01}", err: "the start is after the end"},
+		{name: "reversed letters", pattern: "{C..A}", err: "the start is after the end"},
+		{name: "prefixes differ", pattern: "{L1..B3}", err: "invalid range"},
+		{name: "empty list value", pattern: "{A,,B}", err: "empty value"},
+		{name: "too many codes", pattern: "{1..100}{1..101}", err: "more than 10000 locations"},
+		{name: "range too long", pattern: "{1..10001}", err: "more than 10000 locations"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandPattern(tt.pattern)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.want == nil {
+				if len(got) != tt.count {
+					t.Errorf("expanded to %d codes, want %d", len(got), tt.count)
+				}
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandPattern = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
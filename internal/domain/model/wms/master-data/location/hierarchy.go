@@ -0,0 +1,83 @@
+package location
+
+// Level is the type of a node in the location hierarchy
+type Level string
+
+const (
+	LevelZone  Level = "zone"
+	LevelAisle Level = "aisle"
+	LevelRack  Level = "rack"
+	LevelShelf Level = "level"
+	LevelBin   Level = "bin"
+)
+
+// levelDepth orders the levels from the top of the hierarchy down
+var levelDepth = map[Level]int{
+	LevelZone:  1,
+	LevelAisle: 2,
+	LevelRack:  3,
+	LevelShelf: 4,
+	LevelBin:   5,
+}
+
+// IsValid checks if the level is one of the known levels
+func (l Level) IsValid() bool {
+	_, ok := levelDepth[l]
+	return ok
+}
+
+// IsBelow checks if l is a deeper level than parent
+func (l Level) IsBelow(parent Level) bool {
+	return levelDepth[l] > levelDepth[parent]
+}
+
+// CodeSeparator joins the segments of a full-path code, e.g. WH1-A-03-R2-L4-B07
+const CodeSeparator = "-"
+
+// Node is a location in a hierarchy tree with the capacity of its subtree
+type Node struct {
+	*Location
+	RolledUpCapacity float64 `json:"rolled_up_capacity"` // own capacity for a leaf, the sum of the children otherwise
+	Children         []*Node `json:"children"`
+}
+
+// BuildTree links the locations to their parents and rolls the capacity up
+// from the leaves. Locations whose parent is not in the list become roots.
+func BuildTree(locations []*Location) []*Node {
+	nodes := make(map[int]*Node, len(locations))
+	for _, location := range locations {
+		nodes[location.ID] = &Node{Location: location, Children: []*Node{}}
+	}
+
+	roots := []*Node{}
+	for _, location := range locations {
+		node := nodes[location.ID]
+		if location.ParentID != nil {
+			if parent, ok := nodes[*location.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	for _, root := range roots {
+		root.rollUp()
+	}
+
+	return roots
+}
+
+func (n *Node) rollUp() float64 {
+	if len(n.Children) == 0 {
+		n.RolledUpCapacity = n.Capacity
+		return n.RolledUpCapacity
+	}
+
+	n.RolledUpCapacity = 0
+	for _, child := range n.Children {
+		n.RolledUpCapacity += child.rollUp()
+	}
+
+	return n.RolledUpCapacity
+}
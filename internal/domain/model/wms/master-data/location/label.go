@@ -0,0 +1,29 @@
+package location
+
+import "ecosystem.garyle/service/pkg/utils/label"
+
+// MaxLabels limits how many labels a single request may print
+const MaxLabels = 2000
+
+// LabelRequest selects the locations to print labels for, by id and/or by zone
+type LabelRequest struct {
+	LocationIDs    []int           `json:"location_ids"`
+	Zone           string          `json:"zone"`
+	Format         label.Format    `json:"format"`          // zpl or pdf
+	Template       string          `json:"template"`        // name of a predefined template, see label.Templates
+	CustomTemplate *label.Template `json:"custom_template"` // overrides the fields it sets of the named template
+}
+
+// Label returns the label content of the location, the barcode holds the code
+func (l *Location) Label() label.Label {
+	return label.Label{
+		Barcode: l.Code,
+		Fields: map[string]string{
+			"code":    l.Code,
+			"zone":    l.Zone,
+			"type":    l.Type,
+			"level":   string(l.Level),
+			"segment": l.Segment,
+		},
+	}
+}
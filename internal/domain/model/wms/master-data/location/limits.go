@@ -0,0 +1,69 @@
+package location
+
+// Limits caps what a location can hold, a nil limit is not enforced
+type Limits struct {
+	MaxWeight *float64 `json:"max_weight"` // kg
+	MaxVolume *float64 `json:"max_volume"` // m³
+	MaxUnits  *int     `json:"max_units"`  // pallets or units of stock, counted in the unit of the product
+}
+
+// IsSet checks if any limit is set
+func (l Limits) IsSet() bool {
+	return l.MaxWeight != nil || l.MaxVolume != nil || l.MaxUnits != nil
+}
+
+// Utilization compares the limits of a location with the stock stored there
+type Utilization struct {
+	LocationID int    `json:"location_id"`
+	Code       string `json:"code"`
+	Limits
+	UsedWeight        float64  `json:"used_weight"`
+	UsedVolume        float64  `json:"used_volume"`
+	UsedUnits         float64  `json:"used_units"`
+	WeightUtilization *float64 `json:"weight_utilization"` // percent of max_weight, nil without a limit
+	VolumeUtilization *float64 `json:"volume_utilization"`
+	UnitsUtilization  *float64 `json:"units_utilization"`
+}
+
+// Calculate fills in the utilization percentages from the used amounts
+func (u *Utilization) Calculate() {
+	u.WeightUtilization = percentOf(u.UsedWeight, u.MaxWeight)
+	u.VolumeUtilization = percentOf(u.UsedVolume, u.MaxVolume)
+
+	if u.MaxUnits != nil {
+		maxUnits := float64(*u.MaxUnits)
+		u.UnitsUtilization = percentOf(u.UsedUnits, &maxUnits)
+	}
+}
+
+// Fits checks if the location has room for another weight, volume and number of units
+func (u *Utilization) Fits(weight, volume, units float64) bool {
+	if u.MaxWeight != nil && u.UsedWeight+weight > *u.MaxWeight {
+		return false
+	}
+
+	if u.MaxVolume != nil && u.UsedVolume+volume > *u.MaxVolume {
+		return false
+	}
+
+	if u.MaxUnits != nil && u.UsedUnits+units > float64(*u.MaxUnits) {
+		return false
+	}
+
+	return true
+}
+
+func percentOf(used float64, limit *float64) *float64 {
+	if limit == nil {
+		return nil
+	}
+
+	percent := 100.0
+	if *limit > 0 {
+		percent = used / *limit * 100
+	} else if used == 0 {
+		percent = 0
+	}
+
+	return &percent
+}
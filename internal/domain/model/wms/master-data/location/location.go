@@ -3,20 +3,119 @@ package location
 import (
 	"database/sql"
 	"time"
+
+	"ecosystem.garyle/service/pkg/utils/filter"
 )
 
 type Location struct {
-	ID        int          `json:"id" db:"id"`
-	Code      string       `json:"code" db:"code"`
-	Zone      string       `json:"zone" db:"zone"`
-	Type      string       `json:"type" db:"type"`         //rack/bin/area
-	Capacity  float64      `json:"capacity" db:"capacity"` //1000
-	CreatedAt time.Time    `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
-	DeletedAt sql.NullTime `json:"deleted_at" db:"deleted_at"`
+	ID           int                  `json:"id" db:"id"`
+	WarehouseID  *int                 `json:"warehouse_id" db:"warehouse_id"`
+	ParentID     *int                 `json:"parent_id" db:"parent_id"`
+	Level        Level                `json:"level" db:"level"`     // zone/aisle/rack/level/bin, empty for flat locations
+	Segment      string               `json:"segment" db:"segment"` // own part of the code, e.g. B07
+	Code         string               `json:"code" db:"code"`       // unique, the full path when segment is set, e.g. WH1-A-03-R2-L4-B07
+	Zone         string               `json:"zone" db:"zone"`
+	Type         string               `json:"type" db:"type"`         //rack/bin/area
+	Capacity     float64              `json:"capacity" db:"capacity"` //1000
+	Capabilities *StorageCapabilities `json:"capabilities" db:"-"`    // nil on update keeps the current capabilities
+	Limits       *Limits              `json:"limits" db:"-"`          // nil on update keeps the current limits
+	Coordinates  *Coordinates         `json:"coordinates" db:"-"`     // nil when the location is not on the floor map
+	Status       Status               `json:"status" db:"status"`     // active unless blocked, see StatusChange
+	StatusChange *StatusChange        `json:"status_change" db:"-"`   // why and by whom the location is blocked, nil when active
+	CreatedAt    time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at" db:"updated_at"`
+	DeletedAt    sql.NullTime         `json:"deleted_at" db:"deleted_at"`
 }
 
 // IsDeleted checks if the location is deleted
 func (l *Location) IsDeleted() bool {
 	return l.DeletedAt.Valid
 }
+
+// QuerySpec whitelists the fields that can be used to filter and sort the location list
+var QuerySpec = filter.Spec{
+	Fields: map[string]filter.Field{
+		"id":               {Column: "id", Type: filter.Integer},
+		"warehouse_id":     {Column: "warehouse_id", Type: filter.Integer},
+		"parent_id":        {Column: "parent_id", Type: filter.Integer},
+		"level":            {Column: "level", Type: filter.String},
+		"code":             {Column: "code", Type: filter.String},
+		"zone":             {Column: "zone", Type: filter.String},
+		"type":             {Column: "type", Type: filter.String},
+		"capacity":         {Column: "capacity", Type: filter.Number},
+		"max_weight":       {Column: "max_weight", Type: filter.Number},
+		"max_volume":       {Column: "max_volume", Type: filter.Number},
+		"max_units":        {Column: "max_units", Type: filter.Integer},
+		"is_block_stacked": {Column: "is_block_stacked", Type: filter.Bool},
+		"accepts_fragile":  {Column: "accepts_fragile", Type: filter.Bool},
+		"status":           {Column: StatusColumn, Type: filter.String},
+		"created_at":       {Column: "created_at", Type: filter.Time},
+		"updated_at":       {Column: "updated_at", Type: filter.Time},
+	},
+	DefaultSort: []filter.Sort{{Column: "created_at", Desc: true}},
+}
+
+// ExportColumns lists the columns of a location export in their default order
+var ExportColumns = []string{"id", "warehouse_id", "parent_id", "level", "segment", "code", "zone", "type", "capacity", "max_weight", "max_volume", "max_units", "status", "created_at", "updated_at", "deleted_at"}
+
+// ExportValue returns the value of an export column
+func (l *Location) ExportValue(column string) interface{} {
+	switch column {
+	case "id":
+		return l.ID
+	case "warehouse_id":
+		if l.WarehouseID != nil {
+			return *l.WarehouseID
+		}
+		return nil
+	case "parent_id":
+		if l.ParentID != nil {
+			return *l.ParentID
+		}
+		return nil
+	case "level":
+		return string(l.Level)
+	case "segment":
+		return l.Segment
+	case "code":
+		return l.Code
+	case "zone":
+		return l.Zone
+	case "type":
+		return l.Type
+	case "capacity":
+		return l.Capacity
+	case "status":
+		return string(l.Status)
+	case "max_weight", "max_volume", "max_units":
+		return l.limitValue(column)
+	case "created_at":
+		return l.CreatedAt
+	case "updated_at":
+		return l.UpdatedAt
+	case "deleted_at":
+		if l.DeletedAt.Valid {
+			return l.DeletedAt.Time
+		}
+		return nil
+	}
+
+	return nil
+}
+
+func (l *Location) limitValue(column string) interface{} {
+	if l.Limits == nil {
+		return nil
+	}
+
+	switch {
+	case column == "max_weight" && l.Limits.MaxWeight != nil:
+		return *l.Limits.MaxWeight
+	case column == "max_volume" && l.Limits.MaxVolume != nil:
+		return *l.Limits.MaxVolume
+	case column == "max_units" && l.Limits.MaxUnits != nil:
+		return *l.Limits.MaxUnits
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package location
+
+import "time"
+
+// Status is the operational status of a location
+type Status string
+
+const (
+	StatusActive          Status = "active"
+	StatusBlockedInbound  Status = "blocked_inbound"  // nothing may be put away here
+	StatusBlockedOutbound Status = "blocked_outbound" // nothing may be picked from here
+	StatusBlocked         Status = "blocked"          // blocked both ways
+	StatusUnderCount      Status = "under_count"      // frozen both ways until the count is done
+)
+
+// IsValid checks if the status is one of the known statuses
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusActive, StatusBlockedInbound, StatusBlockedOutbound, StatusBlocked, StatusUnderCount:
+		return true
+	}
+
+	return false
+}
+
+// AllowsInbound reports whether stock may be put into the location
+func (s Status) AllowsInbound() bool {
+	return s == StatusActive || s == StatusBlockedOutbound
+}
+
+// AllowsOutbound reports whether stock may be taken from the location
+func (s Status) AllowsOutbound() bool {
+	return s == StatusActive || s == StatusBlockedInbound
+}
+
+// Reason is why a location was blocked
+type Reason string
+
+const (
+	ReasonDamage      Reason = "damage"
+	ReasonMaintenance Reason = "maintenance"
+	ReasonCounting    Reason = "counting"
+	ReasonQuality     Reason = "quality"
+	ReasonOther       Reason = "other"
+)
+
+// IsValid checks if the reason is one of the known reason codes
+func (r Reason) IsValid() bool {
+	switch r {
+	case ReasonDamage, ReasonMaintenance, ReasonCounting, ReasonQuality, ReasonOther:
+		return true
+	}
+
+	return false
+}
+
+// StatusChange sets the status of a location, a status other than active
+// needs a reason and may expire
+type StatusChange struct {
+	Status    Status     `json:"status"`
+	Reason    Reason     `json:"reason"`
+	Note      string     `json:"note"`
+	SetBy     string     `json:"set_by"`
+	SetAt     *time.Time `json:"set_at"`
+	ExpiresAt *time.Time `json:"expires_at"` // the location is active again from then on
+}
+
+// StatusColumn resolves the status in queries, a status past its expiry is active again
+const StatusColumn = `CASE WHEN locations.status_expires_at IS NOT NULL AND locations.status_expires_at <= NOW() THEN 'active' ELSE locations.status END`
@@ -0,0 +1,343 @@
+package party
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxBlockSize skips name words shared by so many parties that they say
+// nothing, e.g. "jaya", comparing all of them would be quadratic
+const maxBlockSize = 1000
+
+// DefaultDuplicateThreshold is the score from which two parties are reported
+// as likely duplicates
+const DefaultDuplicateThreshold = 0.75
+
+// the weights of the signals in a duplicate score, a signal that is missing
+// on either side is left out and the others are scaled up
+const (
+	nameWeight    = 0.6
+	addressWeight = 0.25
+	contactWeight = 0.15
+)
+
+// legalForms are dropped from names before they are compared, so "PT. Maju
+// Jaya Tbk" and "PT Maju Jaya" compare as equal
+var legalForms = map[string]bool{
+	"pt": true, "cv": true, "ud": true, "pd": true, "fa": true, "tbk": true, "persero": true, "koperasi": true,
+	"ltd": true, "limited": true, "inc": true, "llc": true, "co": true, "corp": true, "corporation": true,
+	"company": true, "gmbh": true, "bv": true, "sdn": true, "bhd": true, "pte": true, "plc": true,
+}
+
+// address abbreviations are expanded so "Jl." and "Jalan" match
+var addressWords = map[string]string{
+	"jl": "jalan", "jln": "jalan", "gg": "gang", "kec": "kecamatan", "kel": "kelurahan",
+	"kab": "kabupaten", "no": "nomor", "st": "street", "rd": "road",
+}
+
+var (
+	nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+	emailPattern    = regexp.MustCompile(`[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}`)
+	phoneDigits     = regexp.MustCompile(`\+?[0-9][0-9 ().-]{5,}[0-9]`)
+)
+
+// Profile is what duplicate detection knows about a party. Address and
+// Contact join the free-text fields with the structured addresses and contacts.
+type Profile struct {
+	ID      int
+	Name    string
+	Address string
+	Contact string
+}
+
+// NewProfile builds the profile of a party that may not be stored yet
+func NewProfile(id int, name, address, contact string, addresses []*Address, contacts []*Contact) Profile {
+	addressParts := []string{address}
+	for _, item := range addresses {
+		addressParts = append(addressParts, item.Street, item.City, item.PostalCode)
+	}
+
+	contactParts := []string{contact}
+	for _, item := range contacts {
+		contactParts = append(contactParts, item.Phone, item.Email)
+	}
+
+	return Profile{
+		ID:      id,
+		Name:    name,
+		Address: strings.Join(addressParts, " "),
+		Contact: strings.Join(contactParts, " "),
+	}
+}
+
+// DuplicateMatch is a party that looks like a duplicate of another one
+type DuplicateMatch struct {
+	ID           int     `json:"id"`
+	Name         string  `json:"name"`
+	Score        float64 `json:"score"`
+	NameScore    float64 `json:"name_score"`
+	AddressScore float64 `json:"address_score"`
+	ContactScore float64 `json:"contact_score"`
+}
+
+// DuplicatePair is an entry of the duplicate candidate report, the party
+// with the lower id is listed first
+type DuplicatePair struct {
+	ID    int            `json:"id"`
+	Name  string         `json:"name"`
+	Match DuplicateMatch `json:"match"`
+}
+
+// MergeRequest merges a duplicate into the party named in the route
+type MergeRequest struct {
+	DuplicateID int    `json:"duplicate_id"`
+	MergedBy    string `json:"merged_by"`
+	Reason      string `json:"reason"`
+}
+
+// MergeRecord is the audit record left by a merge
+type MergeRecord struct {
+	ID          int              `json:"id" db:"id"`
+	PartyType   string           `json:"party_type" db:"party_type"` // supplier or customer
+	SurvivorID  int              `json:"survivor_id" db:"survivor_id"`
+	DuplicateID int              `json:"duplicate_id" db:"duplicate_id"`
+	MergedBy    string           `json:"merged_by" db:"merged_by"`
+	Reason      string           `json:"reason" db:"reason"`
+	Moved       map[string]int64 `json:"moved" db:"moved"`       // rows moved to the survivor per table
+	Snapshot    json.RawMessage  `json:"snapshot" db:"snapshot"` // the duplicate as it was before the merge
+	MergedAt    time.Time        `json:"merged_at" db:"merged_at"`
+}
+
+// NormalizeName lowercases a name and drops punctuation and legal forms
+func NormalizeName(name string) string {
+	words := strings.Fields(nonAlphanumeric.ReplaceAllString(strings.ToLower(name), " "))
+	kept := make([]string, 0, len(words))
+	for _, word := range words {
+		if !legalForms[word] {
+			kept = append(kept, word)
+		}
+	}
+
+	// a name made of legal forms only is kept as it is
+	if len(kept) == 0 {
+		return strings.Join(words, " ")
+	}
+
+	return strings.Join(kept, " ")
+}
+
+func normalizeAddress(address string) string {
+	words := strings.Fields(nonAlphanumeric.ReplaceAllString(strings.ToLower(address), " "))
+	for i, word := range words {
+		if expanded, ok := addressWords[word]; ok {
+			words[i] = expanded
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// contactKeys extracts the emails and phone numbers of a contact text, phone
+// numbers are reduced to their digits with the Indonesian +62 prefix as 0
+func contactKeys(contact string) map[string]bool {
+	contact = strings.ToLower(contact)
+	keys := map[string]bool{}
+	for _, email := range emailPattern.FindAllString(contact, -1) {
+		keys[email] = true
+	}
+
+	for _, phone := range phoneDigits.FindAllString(emailPattern.ReplaceAllString(contact, " "), -1) {
+		digits := strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, phone)
+
+		if strings.HasPrefix(digits, "62") {
+			digits = "0" + digits[2:]
+		}
+
+		if len(digits) >= 7 {
+			keys[digits] = true
+		}
+	}
+
+	return keys
+}
+
+// trigrams splits a text into its padded three-letter grams
+func trigrams(text string) map[string]int {
+	grams := map[string]int{}
+	for _, word := range strings.Fields(text) {
+		padded := "  " + word + " "
+		for i := 0; i+3 <= len(padded); i++ {
+			grams[padded[i:i+3]]++
+		}
+	}
+
+	return grams
+}
+
+// dice is the Dice coefficient of the trigrams of two texts
+func dice(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+
+	if a == b {
+		return 1
+	}
+
+	gramsA, gramsB := trigrams(a), trigrams(b)
+	shared, total := 0, 0
+	for gram, count := range gramsA {
+		total += count
+		if other, ok := gramsB[gram]; ok {
+			shared += min(count, other)
+		}
+	}
+	for _, count := range gramsB {
+		total += count
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	return 2 * float64(shared) / float64(total)
+}
+
+// compared is a profile with its normalized fields
+type compared struct {
+	Profile
+	name     string
+	address  string
+	contacts map[string]bool
+}
+
+func prepare(profile Profile) compared {
+	return compared{
+		Profile:  profile,
+		name:     NormalizeName(profile.Name),
+		address:  normalizeAddress(profile.Address),
+		contacts: contactKeys(profile.Contact),
+	}
+}
+
+// score compares two profiles, the score runs from 0 to 1
+func score(a, b compared) DuplicateMatch {
+	match := DuplicateMatch{ID: b.ID, Name: b.Name, NameScore: dice(a.name, b.name)}
+	weighted, weights := nameWeight*match.NameScore, nameWeight
+
+	if a.address != "" && b.address != "" {
+		match.AddressScore = dice(a.address, b.address)
+		weighted += addressWeight * match.AddressScore
+		weights += addressWeight
+	}
+
+	if len(a.contacts) > 0 && len(b.contacts) > 0 {
+		for key := range a.contacts {
+			if b.contacts[key] {
+				match.ContactScore = 1
+				break
+			}
+		}
+		weighted += contactWeight * match.ContactScore
+		weights += contactWeight
+	}
+
+	match.Score = round(weighted / weights)
+	match.NameScore = round(match.NameScore)
+	match.AddressScore = round(match.AddressScore)
+	return match
+}
+
+func round(value float64) float64 {
+	return float64(int(value*1000+0.5)) / 1000
+}
+
+// FindMatches returns the profiles that look like a duplicate of profile,
+// best match first. The profile itself is skipped when it is in the list.
+func FindMatches(profile Profile, profiles []Profile, threshold float64) []DuplicateMatch {
+	target := prepare(profile)
+	matches := []DuplicateMatch{}
+	for _, other := range profiles {
+		if other.ID == profile.ID && profile.ID != 0 {
+			continue
+		}
+
+		match := score(target, prepare(other))
+		if match.Score >= threshold {
+			matches = append(matches, match)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	return matches
+}
+
+// FindPairs returns the pairs of profiles that look like duplicates, best
+// pair first. Only profiles that share a name word, a phone number or an
+// email are compared.
+func FindPairs(profiles []Profile, threshold float64, limit int) []DuplicatePair {
+	prepared := make([]compared, len(profiles))
+	blocks := map[string][]int{}
+	for i, profile := range profiles {
+		prepared[i] = prepare(profile)
+		for _, word := range strings.Fields(prepared[i].name) {
+			blocks["n:"+word] = append(blocks["n:"+word], i)
+		}
+		for key := range prepared[i].contacts {
+			blocks["c:"+key] = append(blocks["c:"+key], i)
+		}
+	}
+
+	seen := map[[2]int]bool{}
+	pairs := []DuplicatePair{}
+	for _, block := range blocks {
+		if len(block) > maxBlockSize {
+			continue
+		}
+
+		for x := 0; x < len(block); x++ {
+			for y := x + 1; y < len(block); y++ {
+				key := [2]int{block[x], block[y]}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				left, right := prepared[block[x]], prepared[block[y]]
+				match := score(left, right)
+				if match.Score < threshold {
+					continue
+				}
+
+				pairs = append(pairs, DuplicatePair{ID: left.ID, Name: left.Name, Match: match})
+			}
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].Match.Score != pairs[j].Match.Score {
+			return pairs[i].Match.Score > pairs[j].Match.Score
+		}
+		if pairs[i].ID != pairs[j].ID {
+			return pairs[i].ID < pairs[j].ID
+		}
+		return pairs[i].Match.ID < pairs[j].Match.ID
+	})
+
+	if limit > 0 && len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+
+	return pairs
+}
@@ -0,0 +1,60 @@
+package party
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Suppliers and customers are both parties, they share the same kind of
+// addresses and contact persons
+
+// AddressType tells what an address is used for
+type AddressType string
+
+const (
+	AddressBilling  AddressType = "billing"
+	AddressShipping AddressType = "shipping"
+	AddressPickup   AddressType = "pickup"
+)
+
+// IsValid checks if the type is one of the known address types
+func (t AddressType) IsValid() bool {
+	switch t {
+	case AddressBilling, AddressShipping, AddressPickup:
+		return true
+	}
+
+	return false
+}
+
+// Address is a structured address of a party. Addresses are soft deleted so
+// orders and shipments can keep referring to them.
+type Address struct {
+	ID         int          `json:"id" db:"id"`
+	Type       AddressType  `json:"type" db:"type"`
+	Label      string       `json:"label" db:"label"` // e.g. "Main warehouse"
+	Street     string       `json:"street" db:"street"`
+	City       string       `json:"city" db:"city"`
+	Province   string       `json:"province" db:"province"`
+	PostalCode string       `json:"postal_code" db:"postal_code"`
+	Country    string       `json:"country" db:"country"` // ISO 3166-1 alpha-2, e.g. ID
+	Latitude   *float64     `json:"latitude" db:"latitude"`
+	Longitude  *float64     `json:"longitude" db:"longitude"`
+	IsDefault  bool         `json:"is_default" db:"is_default"` // default address of its type
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at" db:"updated_at"`
+	DeletedAt  sql.NullTime `json:"-" db:"deleted_at"`
+}
+
+// Contact is a contact person of a party
+type Contact struct {
+	ID        int          `json:"id" db:"id"`
+	Name      string       `json:"name" db:"name"`
+	Role      string       `json:"role" db:"role"` // e.g. purchasing, finance
+	Phone     string       `json:"phone" db:"phone"`
+	Email     string       `json:"email" db:"email"`
+	IsPrimary bool         `json:"is_primary" db:"is_primary"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
+	DeletedAt sql.NullTime `json:"-" db:"deleted_at"`
+}
@@ -0,0 +1,95 @@
+package party
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Registration is the tax and business registration data of a supplier or
+// customer, used for invoicing
+type Registration struct {
+	TaxCountry         string `json:"tax_country" db:"tax_country"` // ISO 3166-1 alpha-2, picks the tax ID format
+	TaxID              string `json:"tax_id" db:"tax_id"`           // stored normalized, e.g. the digits of an NPWP
+	RegistrationNumber string `json:"registration_number" db:"registration_number"`
+	PaymentTerms       string `json:"payment_terms" db:"payment_terms"` // e.g. NET30, see PaymentTermsPattern
+	Currency           string `json:"currency" db:"currency"`           // ISO 4217, e.g. IDR
+}
+
+// DefaultTaxCountry is used when a tax ID is sent without a tax_country
+const DefaultTaxCountry = "ID"
+
+var (
+	// COD, CIA (cash in advance), EOM (end of month) or NET followed by the number of days
+	PaymentTermsPattern = regexp.MustCompile(`^(COD|CIA|EOM|NET[0-9]{1,3})$`)
+	// ISO 4217 currency codes, e.g. IDR or USD
+	CurrencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+	// registration numbers differ per country, only the characters and length are checked
+	RegistrationNumberPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 ./-]{1,48}[A-Za-z0-9]$`)
+)
+
+// TaxIDFormat validates the tax IDs of one country
+type TaxIDFormat struct {
+	Name    string         `json:"name"`    // e.g. NPWP
+	Example string         `json:"example"` // shown in the validation error
+	Pattern *regexp.Regexp `json:"-"`       // matched against the normalized value
+	// Check runs after the pattern matched, e.g. for a check digit
+	Check func(value string) bool `json:"-"`
+}
+
+// TaxIDFormats are the tax ID formats by country code. A country without an
+// entry accepts any tax ID of 3 to 30 letters and digits, so another country
+// is supported by adding its format here.
+var TaxIDFormats = map[string]TaxIDFormat{
+	// NPWP, 15 digits, or 16 digits since the NIK became the NPWP of individuals
+	"ID": {Name: "NPWP", Example: "01.234.567.8-901.000", Pattern: regexp.MustCompile(`^([0-9]{15}|[0-9]{16})$`)},
+	// Unique Entity Number of businesses and companies
+	"SG": {Name: "UEN", Example: "201912345K", Pattern: regexp.MustCompile(`^([0-9]{8}[A-Z]|[0-9]{9}[A-Z]|[TSR][0-9]{2}[A-Z]{2}[0-9]{4}[A-Z])$`)},
+	// Tax Identification Number with its type prefix, e.g. C for companies
+	"MY": {Name: "TIN", Example: "C2584563202", Pattern: regexp.MustCompile(`^[A-Z]{1,2}[0-9]{9,11}$`)},
+	// 13 digit tax ID with a mod 11 check digit
+	"TH": {Name: "TIN", Example: "0105536112014", Pattern: regexp.MustCompile(`^[0-9]{13}$`), Check: thaiCheckDigit},
+	// 9 digit TIN with an optional 3 or 5 digit branch code
+	"PH": {Name: "TIN", Example: "123-456-789-000", Pattern: regexp.MustCompile(`^([0-9]{9}|[0-9]{12}|[0-9]{14})$`)},
+}
+
+// genericTaxID is accepted for countries without a TaxIDFormat
+var genericTaxID = regexp.MustCompile(`^[A-Z0-9]{3,30}$`)
+
+// NormalizeTaxID uppercases a tax ID and drops the separators people type,
+// so "01.234.567.8-901.000" is stored as "012345678901000"
+func NormalizeTaxID(value string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(value) {
+		switch r {
+		case ' ', '.', '-', '/':
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// ValidTaxID checks a normalized tax ID against the format of its country
+func ValidTaxID(country, value string) bool {
+	format, ok := TaxIDFormats[country]
+	if !ok {
+		return genericTaxID.MatchString(value)
+	}
+
+	if !format.Pattern.MatchString(value) {
+		return false
+	}
+
+	return format.Check == nil || format.Check(value)
+}
+
+// thaiCheckDigit checks the last digit of a Thai tax ID
+func thaiCheckDigit(value string) bool {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		sum += int(value[i]-'0') * (13 - i)
+	}
+
+	return int(value[12]-'0') == (11-sum%11)%10
+}
@@ -0,0 +1,66 @@
+package product
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/category"
+	"ecosystem.garyle/service/pkg/utils/filter"
+)
+
+// Attributes holds the values of the attributes defined by the category of
+// the product, e.g. {"voltage": 220, "allergens": "nuts"}. It is stored as a
+// JSONB object.
+type Attributes map[string]interface{}
+
+// attributePrefix marks product list filters on attributes, e.g. ?attr.voltage[gte]=110
+const attributePrefix = "attr."
+
+// AttributesColumn resolves the attributes of a product, a variant gets the
+// attributes of its parent merged under its own
+const AttributesColumn = `COALESCE((SELECT pp.attributes FROM products pp WHERE pp.id = products.parent_id), '{}'::jsonb) || COALESCE(attributes, '{}'::jsonb)`
+
+// attributeValue is the JSON value of one attribute, falling back to the parent
+func attributeValue(name string) string {
+	return `COALESCE(products.attributes->'` + name + `', (SELECT pp.attributes->'` + name + `' FROM products pp WHERE pp.id = products.parent_id))`
+}
+
+// attributeField resolves an attr.<name> filter. Range filters compare the
+// value as a number, every other filter compares its text.
+func attributeField(name string, op filter.Operator) (filter.Field, bool) {
+	if !strings.HasPrefix(name, attributePrefix) {
+		return filter.Field{}, false
+	}
+
+	// the name ends up in the SQL, only plain attribute names are allowed
+	name = strings.TrimPrefix(name, attributePrefix)
+	if !category.AttributeNamePattern.MatchString(name) {
+		return filter.Field{}, false
+	}
+
+	value := attributeValue(name)
+	switch op {
+	case filter.Gt, filter.Gte, filter.Lt, filter.Lte:
+		return filter.Field{
+			Column: `(CASE WHEN jsonb_typeof(` + value + `) = 'number' THEN (` + value + ` #>> '{}')::numeric END)`,
+			Type:   filter.Number,
+		}, true
+	}
+
+	return filter.Field{Column: `(` + value + ` #>> '{}')`, Type: filter.String}, true
+}
+
+// Value implements driver.Valuer.
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(a)
+}
+
+// Scan implements sql.Scanner.
+func (a *Attributes) Scan(src interface{}) error {
+	return scanJSON(src, a)
+}
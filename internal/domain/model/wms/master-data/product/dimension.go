@@ -0,0 +1,31 @@
+package product
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// dimensionPattern matches a length x width x height dimension in centimetres, e.g. 100x50x20
+var dimensionPattern = regexp.MustCompile(`^\s*([0-9]+(?:\.[0-9]+)?)\s*x\s*([0-9]+(?:\.[0-9]+)?)\s*x\s*([0-9]+(?:\.[0-9]+)?)\s*$`)
+
+// UnitVolumeColumn resolves the volume of one unit in m³ from the dimension
+// in queries that select from products, it is 0 when the dimension cannot be read
+const UnitVolumeColumn = `COALESCE((SELECT d[1]::numeric * d[2]::numeric * d[3]::numeric / 1000000 FROM regexp_match(lower(products.dimension), '^\s*([0-9]+(?:\.[0-9]+)?)\s*x\s*([0-9]+(?:\.[0-9]+)?)\s*x\s*([0-9]+(?:\.[0-9]+)?)\s*$') AS d), 0)`
+
+// UnitVolume returns the volume of one unit in m³, it is 0 when the
+// dimension cannot be read
+func (p *Product) UnitVolume() float64 {
+	match := dimensionPattern.FindStringSubmatch(strings.ToLower(p.Dimension))
+	if match == nil {
+		return 0
+	}
+
+	volume := 1.0
+	for _, side := range match[1:] {
+		value, _ := strconv.ParseFloat(side, 64)
+		volume *= value
+	}
+
+	return volume / 1000000
+}
@@ -2,23 +2,135 @@ package product
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
+
+	"ecosystem.garyle/service/pkg/utils/filter"
 )
 
 type Product struct {
-	ID          int          `json:"id" db:"id"`
-	Sku         string       `json:"sku" db:"sku"` // unique
-	Name        string       `json:"name" db:"name"`
-	Description string       `json:"description" db:"description"`
-	Unit        string       `json:"unit" db:"unit"`           //kg/pcs/box/lot
-	Weight      float64      `json:"weight" db:"weight"`       //24.5
-	Dimension   string       `json:"dimension" db:"dimension"` //100x50x20
-	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
-	DeletedAt   sql.NullTime `json:"deleted_at" db:"deleted_at"`
+	ID                int               `json:"id" db:"id"`
+	ParentID          *int              `json:"parent_id" db:"parent_id"` // set on variants
+	CategoryID        *int              `json:"category_id" db:"category_id"`
+	Sku               string            `json:"sku" db:"sku"` // unique
+	Name              string            `json:"name" db:"name"`
+	Description       string            `json:"description" db:"description"`
+	Unit              string            `json:"unit" db:"unit"`                         //kg/pcs/box/lot
+	Weight            float64           `json:"weight" db:"weight"`                     //24.5
+	Dimension         string            `json:"dimension" db:"dimension"`               //100x50x20
+	IsBatchTracked    bool              `json:"is_batch_tracked" db:"is_batch_tracked"` // stock movements must name a batch
+	Storage           StorageConditions `json:"storage" db:"-"`
+	Attributes        Attributes        `json:"attributes,omitempty" db:"attributes"`                 // validated against the category schema
+	VariantAttributes VariantAttributes `json:"variant_attributes,omitempty" db:"variant_attributes"` // parent only, e.g. size and color
+	VariantValues     VariantValues     `json:"variant_values,omitempty" db:"variant_values"`         // variant only, e.g. {"size": "M"}
+	Variants          []*Product        `json:"variants,omitempty" db:"-"`
+	CreatedAt         time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at" db:"updated_at"`
+	DeletedAt         sql.NullTime      `json:"deleted_at" db:"deleted_at"`
 }
 
 // is product deleted?
 func (p *Product) IsDeleted() bool {
 	return p.DeletedAt.Valid
 }
+
+// A variant shares the description and category of its parent unless it
+// has its own, these expressions resolve the effective value in queries
+const (
+	DescriptionColumn = `COALESCE(NULLIF(description, ''), (SELECT pp.description FROM products pp WHERE pp.id = products.parent_id), '')`
+	CategoryIDColumn  = `COALESCE(category_id, (SELECT pp.category_id FROM products pp WHERE pp.id = products.parent_id))`
+)
+
+// QuerySpec whitelists the fields that can be used to filter and sort the product list
+var QuerySpec = filter.Spec{
+	Fields: map[string]filter.Field{
+		"id":               {Column: "id", Type: filter.Integer},
+		"parent_id":        {Column: "parent_id", Type: filter.Integer},
+		"category_id":      {Column: CategoryIDColumn, Type: filter.Integer},
+		"sku":              {Column: "sku", Type: filter.String},
+		"name":             {Column: "name", Type: filter.String},
+		"description":      {Column: DescriptionColumn, Type: filter.String},
+		"unit":             {Column: "unit", Type: filter.String},
+		"weight":           {Column: "weight", Type: filter.Number},
+		"dimension":        {Column: "dimension", Type: filter.String},
+		"is_batch_tracked": {Column: "is_batch_tracked", Type: filter.Bool},
+		"hazmat_class":     {Column: "hazmat_class", Type: filter.String},
+		"un_number":        {Column: "un_number", Type: filter.String},
+		"is_stackable":     {Column: "is_stackable", Type: filter.Bool},
+		"is_fragile":       {Column: "is_fragile", Type: filter.Bool},
+		"created_at":       {Column: "created_at", Type: filter.Time},
+		"updated_at":       {Column: "updated_at", Type: filter.Time},
+	},
+	DefaultSort: []filter.Sort{{Column: "created_at", Desc: true}},
+	Dynamic:     attributeField,
+}
+
+// ExportColumns lists the columns of a product export in their default order
+var ExportColumns = []string{"id", "parent_id", "category_id", "sku", "name", "description", "unit", "weight", "dimension", "is_batch_tracked", "min_temperature", "max_temperature", "hazmat_class", "un_number", "is_stackable", "is_fragile", "attributes", "created_at", "updated_at", "deleted_at"}
+
+// ExportValue returns the value of an export column
+func (p *Product) ExportValue(column string) interface{} {
+	switch column {
+	case "id":
+		return p.ID
+	case "parent_id":
+		if p.ParentID != nil {
+			return *p.ParentID
+		}
+		return nil
+	case "category_id":
+		if p.CategoryID != nil {
+			return *p.CategoryID
+		}
+		return nil
+	case "sku":
+		return p.Sku
+	case "name":
+		return p.Name
+	case "description":
+		return p.Description
+	case "unit":
+		return p.Unit
+	case "weight":
+		return p.Weight
+	case "dimension":
+		return p.Dimension
+	case "is_batch_tracked":
+		return p.IsBatchTracked
+	case "min_temperature":
+		if p.Storage.MinTemperature != nil {
+			return *p.Storage.MinTemperature
+		}
+		return nil
+	case "max_temperature":
+		if p.Storage.MaxTemperature != nil {
+			return *p.Storage.MaxTemperature
+		}
+		return nil
+	case "hazmat_class":
+		return p.Storage.HazmatClass
+	case "un_number":
+		return p.Storage.UNNumber
+	case "is_stackable":
+		return p.Storage.Stackable()
+	case "is_fragile":
+		return p.Storage.IsFragile
+	case "attributes":
+		if len(p.Attributes) == 0 {
+			return ""
+		}
+		attributes, _ := json.Marshal(p.Attributes)
+		return string(attributes)
+	case "created_at":
+		return p.CreatedAt
+	case "updated_at":
+		return p.UpdatedAt
+	case "deleted_at":
+		if p.DeletedAt.Valid {
+			return p.DeletedAt.Time
+		}
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package product
+
+import (
+	"regexp"
+	"strings"
+)
+
+// StorageConditions describes how a product has to be stored
+type StorageConditions struct {
+	MinTemperature *float64 `json:"min_temperature"` // °C, nil means no lower limit
+	MaxTemperature *float64 `json:"max_temperature"` // °C, nil means no upper limit
+	HazmatClass    string   `json:"hazmat_class"`    // UN hazard class or division, e.g. 3 or 2.1, empty when not hazardous
+	UNNumber       string   `json:"un_number"`       // e.g. UN1203
+	IsStackable    *bool    `json:"is_stackable"`    // defaults to true
+	IsFragile      bool     `json:"is_fragile"`
+}
+
+// HazmatClassPattern matches the UN hazard classes 1 to 9, optionally with a
+// division, e.g. 2.1 or 6.2
+var HazmatClassPattern = regexp.MustCompile(`^[1-9](\.[1-6])?$`)
+
+// IsHazardous checks if the product is classified as dangerous goods
+func (s StorageConditions) IsHazardous() bool {
+	return s.HazmatClass != ""
+}
+
+// Stackable reports whether the product may be stacked, it is stackable unless set otherwise
+func (s StorageConditions) Stackable() bool {
+	return s.IsStackable == nil || *s.IsStackable
+}
+
+// HazmatClassGroup returns the class of a division, e.g. 2 for 2.1
+func HazmatClassGroup(class string) string {
+	if i := strings.Index(class, "."); i >= 0 {
+		return class[:i]
+	}
+
+	return class
+}
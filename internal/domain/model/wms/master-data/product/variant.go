@@ -0,0 +1,66 @@
+package product
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// VariantAttribute is a configurable attribute of a parent product, the
+// variants are generated from the combinations of all attribute values
+type VariantAttribute struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// VariantAttributes is stored as a JSONB array
+type VariantAttributes []VariantAttribute
+
+// VariantValues holds the attribute values of one variant and is stored as a JSONB object
+type VariantValues map[string]string
+
+// IsVariant checks if the product is a variant of another product
+func (p *Product) IsVariant() bool {
+	return p.ParentID != nil
+}
+
+// Value implements driver.Valuer.
+func (a VariantAttributes) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(a)
+}
+
+// Scan implements sql.Scanner.
+func (a *VariantAttributes) Scan(src interface{}) error {
+	return scanJSON(src, a)
+}
+
+// Value implements driver.Valuer.
+func (v VariantValues) Value() (driver.Value, error) {
+	if len(v) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(v)
+}
+
+// Scan implements sql.Scanner.
+func (v *VariantValues) Scan(src interface{}) error {
+	return scanJSON(src, v)
+}
+
+func scanJSON(src interface{}, dest interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, dest)
+	case string:
+		return json.Unmarshal([]byte(data), dest)
+	}
+
+	return errors.New("unsupported type for jsonb column")
+}
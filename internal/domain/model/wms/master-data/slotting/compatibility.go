@@ -0,0 +1,76 @@
+package slotting
+
+import (
+	"fmt"
+
+	locationModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
+	productModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/product"
+)
+
+// Compatibility tells whether a product may be stored in a location, and why not
+type Compatibility struct {
+	ProductID  int      `json:"product_id"`
+	LocationID int      `json:"location_id"`
+	Compatible bool     `json:"compatible"`
+	Reasons    []string `json:"reasons"`
+}
+
+// CheckCompatibility compares the storage conditions of product with the
+// capabilities of location
+func CheckCompatibility(product *productModel.Product, location *locationModel.Location) *Compatibility {
+	storage := product.Storage
+	capabilities := locationModel.StorageCapabilities{}
+	if location.Capabilities != nil {
+		capabilities = *location.Capabilities
+	}
+
+	reasons := []string{}
+
+	// the whole temperature range of the location has to fit the product
+	if storage.MinTemperature != nil {
+		if capabilities.MinTemperature == nil {
+			reasons = append(reasons, fmt.Sprintf("product needs at least %g°C but the location temperature is not controlled", *storage.MinTemperature))
+		} else if *capabilities.MinTemperature < *storage.MinTemperature {
+			reasons = append(reasons, fmt.Sprintf("product needs at least %g°C but the location goes down to %g°C", *storage.MinTemperature, *capabilities.MinTemperature))
+		}
+	}
+
+	if storage.MaxTemperature != nil {
+		if capabilities.MaxTemperature == nil {
+			reasons = append(reasons, fmt.Sprintf("product needs at most %g°C but the location temperature is not controlled", *storage.MaxTemperature))
+		} else if *capabilities.MaxTemperature > *storage.MaxTemperature {
+			reasons = append(reasons, fmt.Sprintf("product needs at most %g°C but the location goes up to %g°C", *storage.MaxTemperature, *capabilities.MaxTemperature))
+		}
+	}
+
+	if storage.IsHazardous() && !allowsHazmatClass(capabilities.HazmatClasses, storage.HazmatClass) {
+		reasons = append(reasons, fmt.Sprintf("location is not approved for hazard class %s (%s)", storage.HazmatClass, storage.UNNumber))
+	}
+
+	if !storage.Stackable() && capabilities.IsBlockStacked {
+		reasons = append(reasons, "product cannot be stacked but the location is block stacked")
+	}
+
+	if storage.IsFragile && !capabilities.AllowsFragile() {
+		reasons = append(reasons, "product is fragile but the location does not accept fragile goods")
+	}
+
+	return &Compatibility{
+		ProductID:  product.ID,
+		LocationID: location.ID,
+		Compatible: len(reasons) == 0,
+		Reasons:    reasons,
+	}
+}
+
+// allowsHazmatClass checks class against the allowed classes, a class
+// without division allows all of its divisions
+func allowsHazmatClass(allowed []string, class string) bool {
+	for _, allowedClass := range allowed {
+		if allowedClass == class || allowedClass == productModel.HazmatClassGroup(class) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,50 @@
+package sourcing
+
+import (
+	"database/sql"
+	"time"
+
+	"ecosystem.garyle/service/pkg/utils/filter"
+)
+
+// ProductSupplier records that a supplier provides a product and on which terms
+type ProductSupplier struct {
+	ID               int          `json:"id" db:"id"`
+	ProductID        int          `json:"product_id" db:"product_id"`
+	SupplierID       int          `json:"supplier_id" db:"supplier_id"`
+	SupplierItemCode string       `json:"supplier_item_code" db:"supplier_item_code"` // the supplier's own code for the product
+	UnitCost         float64      `json:"unit_cost" db:"unit_cost"`
+	Currency         string       `json:"currency" db:"currency"` // ISO 4217, e.g. IDR
+	MinOrderQty      float64      `json:"min_order_qty" db:"min_order_qty"`
+	LeadTimeDays     int          `json:"lead_time_days" db:"lead_time_days"`
+	IsPreferred      bool         `json:"is_preferred" db:"is_preferred"` // at most one preferred supplier per product
+	ProductSku       string       `json:"product_sku,omitempty" db:"-"`
+	ProductName      string       `json:"product_name,omitempty" db:"-"`
+	SupplierName     string       `json:"supplier_name,omitempty" db:"-"`
+	CreatedAt        time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time    `json:"updated_at" db:"updated_at"`
+	DeletedAt        sql.NullTime `json:"deleted_at" db:"deleted_at"`
+}
+
+func (p *ProductSupplier) IsDeleted() bool {
+	return p.DeletedAt.Valid
+}
+
+// QuerySpec whitelists the fields that can be used to filter and sort a product's
+// sources and a supplier's catalog
+var QuerySpec = filter.Spec{
+	Fields: map[string]filter.Field{
+		"id":                 {Column: "id", Type: filter.Integer},
+		"product_id":         {Column: "product_id", Type: filter.Integer},
+		"supplier_id":        {Column: "supplier_id", Type: filter.Integer},
+		"supplier_item_code": {Column: "supplier_item_code", Type: filter.String},
+		"unit_cost":          {Column: "unit_cost", Type: filter.Number},
+		"currency":           {Column: "currency", Type: filter.String},
+		"min_order_qty":      {Column: "min_order_qty", Type: filter.Number},
+		"lead_time_days":     {Column: "lead_time_days", Type: filter.Integer},
+		"is_preferred":       {Column: "is_preferred", Type: filter.Bool},
+		"created_at":         {Column: "created_at", Type: filter.Time},
+		"updated_at":         {Column: "updated_at", Type: filter.Time},
+	},
+	DefaultSort: []filter.Sort{{Column: "is_preferred", Desc: true}, {Column: "unit_cost"}},
+}
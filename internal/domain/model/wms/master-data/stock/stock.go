@@ -0,0 +1,24 @@
+package stock
+
+import "time"
+
+// Stock is the quantity of a product, and of a batch for batch-tracked
+// products, stored in a location
+type Stock struct {
+	ID          int       `json:"id" db:"id"`
+	LocationID  int       `json:"location_id" db:"location_id"`
+	ProductID   int       `json:"product_id" db:"product_id"`
+	BatchID     *int      `json:"batch_id" db:"batch_id"`
+	Quantity    float64   `json:"quantity" db:"quantity"` // in the unit of the product
+	ProductSku  string    `json:"product_sku,omitempty" db:"-"`
+	ProductName string    `json:"product_name,omitempty" db:"-"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Movement puts stock into or takes stock out of a location
+type Movement struct {
+	ProductID int     `json:"product_id"`
+	BatchID   *int    `json:"batch_id"` // required for batch-tracked products
+	Quantity  float64 `json:"quantity"`
+}
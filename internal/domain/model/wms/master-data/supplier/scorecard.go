@@ -0,0 +1,121 @@
+package supplier
+
+import (
+	"errors"
+	"time"
+)
+
+// Receipt is one received order line of a supplier, the source of its scorecard
+type Receipt struct {
+	ID          int       `json:"id" db:"id"`
+	SupplierID  int       `json:"supplier_id" db:"supplier_id"`
+	ProductID   int       `json:"product_id" db:"product_id"`
+	Reference   string    `json:"reference" db:"reference"` // e.g. the purchase order or delivery note number
+	OrderedQty  float64   `json:"ordered_qty" db:"ordered_qty"`
+	ReceivedQty float64   `json:"received_qty" db:"received_qty"`
+	RejectedQty float64   `json:"rejected_qty" db:"rejected_qty"` // part of the received quantity that failed QC
+	OrderedAt   time.Time `json:"ordered_at" db:"ordered_at"`
+	ExpectedAt  time.Time `json:"expected_at" db:"expected_at"` // the promised delivery date
+	ReceivedAt  time.Time `json:"received_at" db:"received_at"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// DefaultScorecardDays is the period of a scorecard when none is given
+const DefaultScorecardDays = 90
+
+// Period is the range of receipt dates a scorecard covers, From inclusive and To exclusive
+type Period struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Scorecard rates the deliveries of a supplier over a period. The rates are
+// between 0 and 1 and nil when the period has no receipts to compute them from.
+type Scorecard struct {
+	SupplierID   int    `json:"supplier_id"`
+	SupplierName string `json:"supplier_name"`
+	Period
+	Receipts int `json:"receipts"`
+	// OnTimeRate is the share of receipts received on or before the expected date
+	OnTimeRate *float64 `json:"on_time_rate"`
+	// FillRate is the received quantity over the ordered quantity, over-deliveries count as full
+	FillRate *float64 `json:"fill_rate"`
+	// RejectionRate is the rejected quantity over the received quantity
+	RejectionRate *float64 `json:"rejection_rate"`
+	// AverageLeadTimeDays is the average time from order to receipt
+	AverageLeadTimeDays *float64 `json:"average_lead_time_days"`
+	Score               float64  `json:"score"` // see CalculateScore
+	Rank                int      `json:"rank,omitempty"`
+}
+
+// CalculateScore combines the rates into one number between 0 and 100 used to
+// rank suppliers. On-time delivery and fill rate weigh 40% each, QC 20%.
+func (s *Scorecard) CalculateScore() float64 {
+	if s.Receipts == 0 {
+		return 0
+	}
+
+	score := 0.4*value(s.OnTimeRate) + 0.4*value(s.FillRate)
+	if s.RejectionRate != nil {
+		score += 0.2 * (1 - *s.RejectionRate)
+	} else {
+		// nothing was received, so nothing was rejected either
+		score += 0.2
+	}
+
+	return score * 100
+}
+
+func value(rate *float64) float64 {
+	if rate == nil {
+		return 0
+	}
+
+	return *rate
+}
+
+// ScorecardSorts are the values of the sort parameter of the ranked list
+var ScorecardSorts = []string{"score", "on_time_rate", "fill_rate", "rejection_rate", "lead_time"}
+
+// RankingRequest selects and orders the suppliers of the ranked list
+type RankingRequest struct {
+	Period
+	Sort        string // one of ScorecardSorts, lead time and rejection rate rank the lowest first
+	MinReceipts int    // suppliers with fewer receipts in the period are left out
+	Limit       int
+}
+
+const periodLayout = "2006-01-02"
+
+// ParsePeriod reads the from and to dates of a scorecard, both YYYY-MM-DD and
+// inclusive. Without to the period ends today, without from it starts
+// DefaultScorecardDays before its end.
+func ParsePeriod(from, to string, now time.Time) (Period, error) {
+	var period Period
+
+	if to == "" {
+		period.To = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	} else {
+		date, err := time.Parse(periodLayout, to)
+		if err != nil {
+			return Period{}, errors.New("to must use the YYYY-MM-DD format")
+		}
+		period.To = date.AddDate(0, 0, 1)
+	}
+
+	if from == "" {
+		period.From = period.To.AddDate(0, 0, -DefaultScorecardDays)
+	} else {
+		date, err := time.Parse(periodLayout, from)
+		if err != nil {
+			return Period{}, errors.New("from must use the YYYY-MM-DD format")
+		}
+		period.From = date
+	}
+
+	if !period.From.Before(period.To) {
+		return Period{}, errors.New("from must not be after to")
+	}
+
+	return period, nil
+}
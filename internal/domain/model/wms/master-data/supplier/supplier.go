@@ -3,18 +3,81 @@ package supplier
 import (
 	"database/sql"
 	"time"
+
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
+	"ecosystem.garyle/service/pkg/utils/filter"
 )
 
 type Supplier struct {
-	ID        int          `json:"id" db:"id"`
-	Name      string       `json:"name" db:"name"`
-	Address   string       `json:"address" db:"address"`
-	Contact   string       `json:"contact" db:"contact"`
-	CreatedAt time.Time    `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
-	DeletedAt sql.NullTime `json:"deleted_at" db:"deleted_at"`
+	ID      int    `json:"id" db:"id"`
+	Name    string `json:"name" db:"name"`
+	Address string `json:"address" db:"address"` // free text, see Addresses
+	Contact string `json:"contact" db:"contact"` // free text, see Contacts
+	party.Registration
+	Addresses []*party.Address `json:"addresses,omitempty" db:"-"`
+	Contacts  []*party.Contact `json:"contacts,omitempty" db:"-"`
+	// PossibleDuplicates warns about likely duplicates when the supplier is created
+	PossibleDuplicates []party.DuplicateMatch `json:"possible_duplicates,omitempty" db:"-"`
+	CreatedAt          time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time              `json:"updated_at" db:"updated_at"`
+	DeletedAt          sql.NullTime           `json:"deleted_at" db:"deleted_at"`
 }
 
 func (s *Supplier) IsDeleted() bool {
 	return s.DeletedAt.Valid
 }
+
+// QuerySpec whitelists the fields that can be used to filter and sort the supplier list
+var QuerySpec = filter.Spec{
+	Fields: map[string]filter.Field{
+		"id":            {Column: "id", Type: filter.Integer},
+		"name":          {Column: "name", Type: filter.String},
+		"address":       {Column: "address", Type: filter.String},
+		"contact":       {Column: "contact", Type: filter.String},
+		"tax_country":   {Column: "tax_country", Type: filter.String},
+		"tax_id":        {Column: "tax_id", Type: filter.String},
+		"payment_terms": {Column: "payment_terms", Type: filter.String},
+		"currency":      {Column: "currency", Type: filter.String},
+		"created_at":    {Column: "created_at", Type: filter.Time},
+		"updated_at":    {Column: "updated_at", Type: filter.Time},
+	},
+	DefaultSort: []filter.Sort{{Column: "created_at", Desc: true}},
+}
+
+// ExportColumns lists the columns of a supplier export in their default order
+var ExportColumns = []string{"id", "name", "address", "contact", "tax_country", "tax_id", "registration_number", "payment_terms", "currency", "created_at", "updated_at", "deleted_at"}
+
+// ExportValue returns the value of an export column
+func (s *Supplier) ExportValue(column string) interface{} {
+	switch column {
+	case "id":
+		return s.ID
+	case "name":
+		return s.Name
+	case "address":
+		return s.Address
+	case "contact":
+		return s.Contact
+	case "tax_country":
+		return s.TaxCountry
+	case "tax_id":
+		return s.TaxID
+	case "registration_number":
+		return s.RegistrationNumber
+	case "payment_terms":
+		return s.PaymentTerms
+	case "currency":
+		return s.Currency
+	case "created_at":
+		return s.CreatedAt
+	case "updated_at":
+		return s.UpdatedAt
+	case "deleted_at":
+		if s.DeletedAt.Valid {
+			return s.DeletedAt.Time
+		}
+		return nil
+	}
+
+	return nil
+}
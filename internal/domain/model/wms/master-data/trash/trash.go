@@ -0,0 +1,239 @@
+package trash
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UniqueKey is a set of columns that must be unique among active rows. It is
+// checked again when a deleted row is restored, a clash is a *conflict.Error.
+type UniqueKey struct {
+	Columns []string
+	// Where limits the check to restored rows matching a condition, e.g. a tax
+	// ID is only unique when it is set
+	Where string
+}
+
+// Parent is a row that must be active before a row referring to it can be restored
+type Parent struct {
+	Column string
+	Entity string // name of the parent entity
+}
+
+// Reference is a column of another table that refers to an entity
+type Reference struct {
+	Table  string
+	Column string
+	Where  string // optional condition on the referring rows
+}
+
+// Entity describes how the rows of one master-data table are restored and purged
+type Entity struct {
+	Name     string // used in the route, e.g. products
+	Singular string // used in messages, e.g. product
+	Table    string
+	Label    string // SQL expression shown in the trash listing
+	Unique   []UniqueKey
+	Parents  []Parent
+	// References block a purge while any referring row exists, deleted or not
+	References []Reference
+	// Owned rows belong to the entity and are purged together with it
+	Owned []Reference
+	// Retain is an optional SQL condition on rows that are never purged
+	Retain       string
+	RetainReason string // why retained rows are kept, used in messages
+}
+
+// Entities are the master-data entities with a trash. A retention purge runs
+// through them in this order, so referring rows are purged before the rows
+// they refer to.
+var Entities = []Entity{
+	{
+		Name: "product-suppliers", Singular: "product supplier", Table: "product_suppliers",
+		Label: "CONCAT_WS(' / ', product_id::text, supplier_id::text, NULLIF(supplier_item_code, ''))",
+		Unique: []UniqueKey{
+			{Columns: []string{"product_id", "supplier_id"}},
+			{Columns: []string{"product_id", "is_preferred"}, Where: "is_preferred"},
+		},
+		Parents: []Parent{{Column: "product_id", Entity: "products"}, {Column: "supplier_id", Entity: "suppliers"}},
+	},
+	{
+		Name: "batches", Singular: "batch", Table: "batches", Label: "batch_no",
+		Unique:     []UniqueKey{{Columns: []string{"product_id", "batch_no"}}},
+		Parents:    []Parent{{Column: "product_id", Entity: "products"}},
+		References: []Reference{{Table: "location_stock", Column: "batch_id", Where: "quantity > 0"}},
+		Owned:      []Reference{{Table: "location_stock", Column: "batch_id", Where: "quantity = 0"}},
+	},
+	{
+		Name: "locations", Singular: "location", Table: "locations", Label: "code",
+		Unique:  []UniqueKey{{Columns: []string{"code"}}},
+		Parents: []Parent{{Column: "warehouse_id", Entity: "warehouses"}, {Column: "parent_id", Entity: "locations"}},
+		References: []Reference{
+			{Table: "locations", Column: "parent_id"},
+			{Table: "location_stock", Column: "location_id", Where: "quantity > 0"},
+		},
+		Owned: []Reference{
+			{Table: "location_stock", Column: "location_id", Where: "quantity = 0"},
+			{Table: "record_versions", Column: "record_id", Where: "entity = 'locations'"},
+		},
+	},
+	{
+		Name: "products", Singular: "product", Table: "products", Label: "sku || ' ' || name",
+		Unique:  []UniqueKey{{Columns: []string{"sku"}}},
+		Parents: []Parent{{Column: "parent_id", Entity: "products"}, {Column: "category_id", Entity: "categories"}},
+		References: []Reference{
+			{Table: "products", Column: "parent_id"},
+			{Table: "product_suppliers", Column: "product_id"},
+			{Table: "batches", Column: "product_id"},
+			{Table: "supplier_receipts", Column: "product_id"},
+			{Table: "location_stock", Column: "product_id", Where: "quantity > 0"},
+		},
+		Owned: []Reference{
+			{Table: "location_stock", Column: "product_id", Where: "quantity = 0"},
+			{Table: "record_versions", Column: "record_id", Where: "entity = 'products'"},
+		},
+	},
+	{
+		Name: "categories", Singular: "category", Table: "categories", Label: "name",
+		Parents: []Parent{{Column: "parent_id", Entity: "categories"}},
+		References: []Reference{
+			{Table: "categories", Column: "parent_id"},
+			{Table: "products", Column: "category_id"},
+		},
+		Owned: []Reference{{Table: "record_versions", Column: "record_id", Where: "entity = 'categories'"}},
+	},
+	{
+		Name: "warehouses", Singular: "warehouse", Table: "warehouses", Label: "code || ' ' || name",
+		Unique:     []UniqueKey{{Columns: []string{"code"}}},
+		References: []Reference{{Table: "locations", Column: "warehouse_id"}},
+		Owned:      []Reference{{Table: "warehouse_floor_maps", Column: "warehouse_id"}},
+	},
+	{
+		Name: "suppliers", Singular: "supplier", Table: "suppliers", Label: "name",
+		Unique: []UniqueKey{{Columns: []string{"tax_country", "tax_id"}, Where: "tax_id <> ''"}},
+		References: []Reference{
+			{Table: "product_suppliers", Column: "supplier_id"},
+			{Table: "supplier_receipts", Column: "supplier_id"},
+			{Table: "party_merges", Column: "survivor_id", Where: "party_type = 'supplier'"},
+			{Table: "party_merges", Column: "duplicate_id", Where: "party_type = 'supplier'"},
+		},
+		Owned: []Reference{
+			{Table: "supplier_addresses", Column: "supplier_id"},
+			{Table: "supplier_contacts", Column: "supplier_id"},
+			{Table: "record_versions", Column: "record_id", Where: "entity = 'suppliers'"},
+		},
+	},
+	{
+		Name: "customers", Singular: "customer", Table: "customers", Label: "name",
+		Unique: []UniqueKey{{Columns: []string{"tax_country", "tax_id"}, Where: "tax_id <> ''"}},
+		References: []Reference{
+			{Table: "party_merges", Column: "survivor_id", Where: "party_type = 'customer'"},
+			{Table: "party_merges", Column: "duplicate_id", Where: "party_type = 'customer'"},
+		},
+		// an erased customer stays so the orders referring to it stay valid
+		Retain:       "erased_at IS NOT NULL",
+		RetainReason: "it was erased and orders may still refer to it",
+		Owned: []Reference{
+			{Table: "customer_addresses", Column: "customer_id"},
+			{Table: "customer_contacts", Column: "customer_id"},
+			{Table: "customer_service_profiles", Column: "customer_id"},
+			{Table: "record_versions", Column: "record_id", Where: "entity = 'customers'"},
+		},
+	},
+}
+
+// FindEntity returns the entity with the name used in the route
+func FindEntity(name string) (Entity, bool) {
+	for _, entity := range Entities {
+		if entity.Name == name {
+			return entity, true
+		}
+	}
+
+	return Entity{}, false
+}
+
+// EntityNames lists the names of Entities, sorted
+func EntityNames() []string {
+	names := make([]string, 0, len(Entities))
+	for _, entity := range Entities {
+		names = append(names, entity.Name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Item is a deleted row in the trash listing
+type Item struct {
+	ID        int       `json:"id"`
+	Entity    string    `json:"entity"`
+	Label     string    `json:"label"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// ParentDeletedError is returned when a restored row refers to a deleted parent
+type ParentDeletedError struct {
+	Entity string
+	Column string
+	Parent string
+}
+
+func (e *ParentDeletedError) Error() string {
+	return fmt.Sprintf("%s cannot be restored while its %s refers to a deleted %s", e.Entity, e.Column, e.Parent)
+}
+
+// ReferencedError is returned when a purged row is still referenced
+type ReferencedError struct {
+	Entity     string
+	ID         int
+	References map[string]int // number of referring rows per table
+}
+
+func (e *ReferencedError) Error() string {
+	tables := make([]string, 0, len(e.References))
+	for table := range e.References {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	parts := make([]string, 0, len(tables))
+	for _, table := range tables {
+		parts = append(parts, fmt.Sprintf("%s (%d)", table, e.References[table]))
+	}
+
+	return fmt.Sprintf("%s %d is still referenced by %s", e.Entity, e.ID, strings.Join(parts, ", "))
+}
+
+// RetainedError is returned when a purged row matches the Retain condition of its entity
+type RetainedError struct {
+	Entity string
+	ID     int
+	Reason string
+}
+
+func (e *RetainedError) Error() string {
+	return fmt.Sprintf("%s %d cannot be purged, %s", e.Entity, e.ID, e.Reason)
+}
+
+// PurgeRequest runs a retention purge on demand
+type PurgeRequest struct {
+	Entity        string `json:"entity"`          // optional, all entities when empty
+	OlderThanDays *int   `json:"older_than_days"` // purge rows deleted at least this many days ago
+}
+
+// Skipped is a row a retention purge left in the trash
+type Skipped struct {
+	Entity     string         `json:"entity"`
+	ID         int            `json:"id"`
+	References map[string]int `json:"references"`
+}
+
+// PurgeReport is the result of a retention purge
+type PurgeReport struct {
+	DeletedBefore time.Time      `json:"deleted_before"`
+	Purged        map[string]int `json:"purged"` // number of purged rows per entity
+	Skipped       []Skipped      `json:"skipped"`
+}
@@ -0,0 +1,35 @@
+package warehouse
+
+import (
+	"database/sql"
+	"time"
+
+	"ecosystem.garyle/service/pkg/utils/filter"
+)
+
+type Warehouse struct {
+	ID        int          `json:"id" db:"id"`
+	Code      string       `json:"code" db:"code"` // unique, first segment of the location codes, e.g. WH1
+	Name      string       `json:"name" db:"name"`
+	Address   string       `json:"address" db:"address"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
+	DeletedAt sql.NullTime `json:"deleted_at" db:"deleted_at"`
+}
+
+func (w *Warehouse) IsDeleted() bool {
+	return w.DeletedAt.Valid
+}
+
+// QuerySpec whitelists the fields that can be used to filter and sort the warehouse list
+var QuerySpec = filter.Spec{
+	Fields: map[string]filter.Field{
+		"id":         {Column: "id", Type: filter.Integer},
+		"code":       {Column: "code", Type: filter.String},
+		"name":       {Column: "name", Type: filter.String},
+		"address":    {Column: "address", Type: filter.String},
+		"created_at": {Column: "created_at", Type: filter.Time},
+		"updated_at": {Column: "updated_at", Type: filter.Time},
+	},
+	DefaultSort: []filter.Sort{{Column: "code"}},
+}
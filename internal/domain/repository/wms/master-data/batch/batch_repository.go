@@ -0,0 +1,17 @@
+package batch
+
+import (
+	"context"
+
+	batchModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/batch"
+	"ecosystem.garyle/service/pkg/utils/filter"
+)
+
+type BatchRepository interface {
+	Create(ctx context.Context, batch *batchModel.Batch) (*batchModel.Batch, error)
+	GetByID(ctx context.Context, id int) (*batchModel.Batch, error)
+	List(ctx context.Context, limit, page int, params *filter.Params) ([]*batchModel.Batch, error)
+	Count(ctx context.Context, params *filter.Params) (int, error)
+	UpdateByID(ctx context.Context, batch *batchModel.Batch, id int) error
+	DeleteByID(ctx context.Context, id int) error
+}
@@ -4,13 +4,25 @@ import (
 	"context"
 
 	categoryModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/category"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
+	"ecosystem.garyle/service/pkg/utils/filter"
 )
 
 type CategoryRepository interface {
 	Create(ctx context.Context, category *categoryModel.Category) (*categoryModel.Category, error)
-	List(ctx context.Context, limit, page int) ([]*categoryModel.Category, error)
-	Count(ctx context.Context) (int, error)
+	CreateBatch(ctx context.Context, categories []*categoryModel.Category, opts imports.Options, report func(index int, err error)) error
+	Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(category *categoryModel.Category) error) error
+	List(ctx context.Context, limit, page int, params *filter.Params) ([]*categoryModel.Category, error)
+	Count(ctx context.Context, params *filter.Params) (int, error)
 	GetByID(ctx context.Context, id int) (*categoryModel.Category, error)
 	Update(ctx context.Context, category *categoryModel.Category, id int) (*categoryModel.Category, error)
 	Delete(ctx context.Context, id int) error
+	ListTree(ctx context.Context) ([]*categoryModel.Node, error)
+	GetSubtree(ctx context.Context, id int) ([]*categoryModel.Node, error)
+	ListAncestors(ctx context.Context, id int) ([]*categoryModel.Node, error)
+	Move(ctx context.Context, id int, parentID *int) error
+	IsInSubtree(ctx context.Context, rootID, id int) (bool, error)
+	CountChildren(ctx context.Context, id int) (int, error)
+	DeleteSubtree(ctx context.Context, id int) error
+	DeleteAndReparent(ctx context.Context, id int) error
 }
@@ -4,13 +4,23 @@ import (
 	"context"
 
 	customerModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/customer"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
+	partyRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/party"
+	"ecosystem.garyle/service/pkg/utils/filter"
 )
 
 type CustomerRepository interface {
+	partyRepo.DetailRepository
+	partyRepo.MergeRepository
+	partyRepo.RegistrationRepository
+	ServiceProfileRepository
+	PrivacyRepository
 	Create(ctx context.Context, customer *customerModel.Customer) (*customerModel.Customer, error)
+	CreateBatch(ctx context.Context, customers []*customerModel.Customer, opts imports.Options, report func(index int, err error)) error
+	Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(customer *customerModel.Customer) error) error
 	GetByID(ctx context.Context, id int) (*customerModel.Customer, error)
-	List(ctx context.Context, limit, page int) ([]*customerModel.Customer, error)
-	Count(ctx context.Context) (int, error)
+	List(ctx context.Context, limit, page int, params *filter.Params) ([]*customerModel.Customer, error)
+	Count(ctx context.Context, params *filter.Params) (int, error)
 	UpdateByID(ctx context.Context, customer *customerModel.Customer, id int) error
 	DeleteByID(ctx context.Context, id int) error
 }
@@ -0,0 +1,15 @@
+package customer
+
+import "context"
+
+// PrivacyRepository erases the personal data of customers and rotates the key
+// it is encrypted with
+type PrivacyRepository interface {
+	// Erase anonymizes the customer, its addresses, contacts and merge
+	// snapshots. Rows keep their ids so orders still reference them. It
+	// returns false when the customer does not exist.
+	Erase(ctx context.Context, id int) (bool, error)
+	// RotateKeys re-encrypts the values written with another key than the
+	// active one and returns the rows rewritten per table
+	RotateKeys(ctx context.Context) (map[string]int, error)
+}
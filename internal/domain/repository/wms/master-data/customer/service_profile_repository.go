@@ -0,0 +1,15 @@
+package customer
+
+import (
+	"context"
+
+	customerModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/customer"
+)
+
+// ServiceProfileRepository stores the service-level profile of a customer
+type ServiceProfileRepository interface {
+	// GetServiceProfile returns nil when the customer has no profile
+	GetServiceProfile(ctx context.Context, customerID int) (*customerModel.ServiceProfile, error)
+	SaveServiceProfile(ctx context.Context, profile *customerModel.ServiceProfile) (*customerModel.ServiceProfile, error)
+	DeleteServiceProfile(ctx context.Context, customerID int) (bool, error)
+}
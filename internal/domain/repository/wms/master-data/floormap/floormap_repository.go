@@ -0,0 +1,12 @@
+package floormap
+
+import (
+	"context"
+
+	floorMapModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/floormap"
+)
+
+type FloorMapRepository interface {
+	Get(ctx context.Context, warehouseID int) (*floorMapModel.FloorMap, error)
+	Save(ctx context.Context, warehouseID int, graph floorMapModel.Graph, positions []floorMapModel.LocationPosition) error
+}
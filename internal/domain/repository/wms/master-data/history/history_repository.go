@@ -0,0 +1,22 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	historyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/history"
+)
+
+// HistoryRepository reads the versions of a row. A row that was never
+// updated has its current state as version 1, a row that does not exist has
+// no versions.
+type HistoryRepository interface {
+	// List returns the versions of a row, newest first
+	List(ctx context.Context, entity historyModel.Entity, id, limit, page int) ([]*historyModel.Version, error)
+	// Count returns the number of versions, which is also the latest version
+	Count(ctx context.Context, entity historyModel.Entity, id int) (int, error)
+	// Get returns nil when the version does not exist
+	Get(ctx context.Context, entity historyModel.Entity, id, version int) (*historyModel.Version, error)
+	// GetAt returns the version effective at a time, nil when there is none
+	GetAt(ctx context.Context, entity historyModel.Entity, id int, at time.Time) (*historyModel.Version, error)
+}
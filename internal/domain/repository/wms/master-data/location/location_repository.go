@@ -3,14 +3,24 @@ package location
 import (
 	"context"
 
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
 	locationModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
+	"ecosystem.garyle/service/pkg/utils/filter"
 )
 
 type LocationRepository interface {
 	Create(ctx context.Context, location *locationModel.Location) (*locationModel.Location, error)
+	CreateBatch(ctx context.Context, locations []*locationModel.Location, opts imports.Options, report func(index int, err error)) error
+	Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(location *locationModel.Location) error) error
 	GetByID(ctx context.Context, id int) (*locationModel.Location, error)
-	List(ctx context.Context, limit, page int) ([]*locationModel.Location, error)
-	Count(ctx context.Context) (int, error)
+	List(ctx context.Context, limit, page int, params *filter.Params) ([]*locationModel.Location, error)
+	Count(ctx context.Context, params *filter.Params) (int, error)
 	Update(ctx context.Context, location *locationModel.Location, id int) (*locationModel.Location, error)
 	Delete(ctx context.Context, id int) error
+	GetSubtree(ctx context.Context, id int) ([]*locationModel.Location, error)
+	ListByWarehouse(ctx context.Context, warehouseID int) ([]*locationModel.Location, error)
+	CountChildren(ctx context.Context, id int) (int, error)
+	UpdateStatus(ctx context.Context, id int, change *locationModel.StatusChange) (*locationModel.Location, error)
+	FindExistingCodes(ctx context.Context, codes []string) ([]string, error)
+	ListForLabels(ctx context.Context, ids []int, zone string, limit int) ([]*locationModel.Location, error)
 }
@@ -0,0 +1,34 @@
+package party
+
+import (
+	"context"
+
+	partyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
+)
+
+// DetailRepository stores the addresses and contact persons of one kind of party
+type DetailRepository interface {
+	ListAddresses(ctx context.Context, ownerID int) ([]*partyModel.Address, error)
+	GetAddress(ctx context.Context, ownerID, id int) (*partyModel.Address, error)
+	CreateAddress(ctx context.Context, ownerID int, address *partyModel.Address) (*partyModel.Address, error)
+	UpdateAddress(ctx context.Context, ownerID, id int, address *partyModel.Address) (*partyModel.Address, error)
+	DeleteAddress(ctx context.Context, ownerID, id int) error
+	ListContacts(ctx context.Context, ownerID int) ([]*partyModel.Contact, error)
+	GetContact(ctx context.Context, ownerID, id int) (*partyModel.Contact, error)
+	CreateContact(ctx context.Context, ownerID int, contact *partyModel.Contact) (*partyModel.Contact, error)
+	UpdateContact(ctx context.Context, ownerID, id int, contact *partyModel.Contact) (*partyModel.Contact, error)
+	DeleteContact(ctx context.Context, ownerID, id int) error
+}
+
+// MergeRepository finds and merges duplicates of one kind of party
+type MergeRepository interface {
+	ListProfiles(ctx context.Context) ([]partyModel.Profile, error)
+	Merge(ctx context.Context, record *partyModel.MergeRecord) error
+	ListMerges(ctx context.Context, survivorID int) ([]*partyModel.MergeRecord, error)
+}
+
+// RegistrationRepository looks up the registration data of one kind of party
+type RegistrationRepository interface {
+	// TaxIDTaken reports whether an active party other than excludeID uses the tax ID
+	TaxIDTaken(ctx context.Context, country, taxID string, excludeID int) (bool, error)
+}
@@ -3,14 +3,20 @@ package product
 import (
 	"context"
 
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
 	productModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/product"
+	"ecosystem.garyle/service/pkg/utils/filter"
 )
 
 type ProductRepository interface {
 	Create(ctx context.Context, product *productModel.Product) (*productModel.Product, error)
+	CreateBatch(ctx context.Context, products []*productModel.Product, opts imports.Options, report func(index int, err error)) error
+	Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(product *productModel.Product) error) error
 	GetByID(ctx context.Context, id int) (*productModel.Product, error)
-	List(ctx context.Context, limit, page int) ([]*productModel.Product, error)
-	Count(ctx context.Context) (int, error)
+	List(ctx context.Context, limit, page int, params *filter.Params) ([]*productModel.Product, error)
+	Count(ctx context.Context, params *filter.Params) (int, error)
 	UpdateByID(ctx context.Context, product *productModel.Product, id int) error
 	DeleteByID(ctx context.Context, id int) error
+	ListVariants(ctx context.Context, parentIDs []int) ([]*productModel.Product, error)
+	SaveVariants(ctx context.Context, parentID int, attributes productModel.VariantAttributes, variants []*productModel.Product) error
 }
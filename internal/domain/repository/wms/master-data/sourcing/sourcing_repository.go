@@ -0,0 +1,19 @@
+package sourcing
+
+import (
+	"context"
+
+	sourcingModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/sourcing"
+	"ecosystem.garyle/service/pkg/utils/filter"
+)
+
+type SourcingRepository interface {
+	Create(ctx context.Context, productSupplier *sourcingModel.ProductSupplier) (*sourcingModel.ProductSupplier, error)
+	GetByID(ctx context.Context, id int) (*sourcingModel.ProductSupplier, error)
+	ListByProduct(ctx context.Context, productID, limit, page int, params *filter.Params) ([]*sourcingModel.ProductSupplier, error)
+	CountByProduct(ctx context.Context, productID int, params *filter.Params) (int, error)
+	ListBySupplier(ctx context.Context, supplierID, limit, page int, params *filter.Params) ([]*sourcingModel.ProductSupplier, error)
+	CountBySupplier(ctx context.Context, supplierID int, params *filter.Params) (int, error)
+	UpdateByID(ctx context.Context, productSupplier *sourcingModel.ProductSupplier, id int) error
+	DeleteByID(ctx context.Context, id int) error
+}
@@ -0,0 +1,30 @@
+package stock
+
+import (
+	"context"
+
+	locationModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
+	stockModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/stock"
+)
+
+// PutCheck decides if stock may be put into a location, given the location
+// and its utilization before the put
+type PutCheck func(location *locationModel.Location, utilization *locationModel.Utilization) error
+
+// TakeCheck decides if stock may be taken from a location, location is nil
+// when it does not exist
+type TakeCheck func(location *locationModel.Location) error
+
+type StockRepository interface {
+	ListByLocation(ctx context.Context, locationID int) ([]*stockModel.Stock, error)
+	// Put locks the location, runs check and stores the stock in one
+	// transaction. It returns nil when the location does not exist.
+	Put(ctx context.Context, locationID int, movement *stockModel.Movement, check PutCheck) (*stockModel.Stock, error)
+	// Take locks the location, runs check and takes the stock in one
+	// transaction. It returns nil when the location holds too little stock.
+	Take(ctx context.Context, locationID int, movement *stockModel.Movement, check TakeCheck) (*stockModel.Stock, error)
+	GetUtilization(ctx context.Context, locationID int) (*locationModel.Utilization, error)
+	ListUtilization(ctx context.Context, limit, page int) ([]*locationModel.Utilization, error)
+	CountLocations(ctx context.Context) (int, error)
+	ListWithRoom(ctx context.Context, weight, volume, units float64, limit int) ([]*locationModel.Utilization, error)
+}
@@ -0,0 +1,17 @@
+package supplier
+
+import (
+	"context"
+
+	supplierModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/supplier"
+)
+
+// ScorecardRepository stores supplier receipts and aggregates them into scorecards
+type ScorecardRepository interface {
+	CreateReceipt(ctx context.Context, receipt *supplierModel.Receipt) (*supplierModel.Receipt, error)
+	ListReceipts(ctx context.Context, supplierID int, period supplierModel.Period, limit, page int) ([]*supplierModel.Receipt, error)
+	// Scorecards aggregates the receipts of the period per active supplier, or
+	// of one supplier when supplierID is not 0. Suppliers without receipts in
+	// the period are left out.
+	Scorecards(ctx context.Context, period supplierModel.Period, supplierID int) ([]*supplierModel.Scorecard, error)
+}
@@ -3,14 +3,23 @@ package supplier
 import (
 	"context"
 
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
 	supplierModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/supplier"
+	partyRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/party"
+	"ecosystem.garyle/service/pkg/utils/filter"
 )
 
 type SupplierRepository interface {
+	partyRepo.DetailRepository
+	partyRepo.MergeRepository
+	partyRepo.RegistrationRepository
+	ScorecardRepository
 	Create(ctx context.Context, supplier *supplierModel.Supplier) (*supplierModel.Supplier, error)
+	CreateBatch(ctx context.Context, suppliers []*supplierModel.Supplier, opts imports.Options, report func(index int, err error)) error
+	Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(supplier *supplierModel.Supplier) error) error
 	GetByID(ctx context.Context, id int) (*supplierModel.Supplier, error)
-	List(ctx context.Context, limit, page int) ([]*supplierModel.Supplier, error)
-	Count(ctx context.Context) (int, error)
+	List(ctx context.Context, limit, page int, params *filter.Params) ([]*supplierModel.Supplier, error)
+	Count(ctx context.Context, params *filter.Params) (int, error)
 	UpdateByID(ctx context.Context, supplier *supplierModel.Supplier, id int) error
 	DeleteByID(ctx context.Context, id int) error
 }
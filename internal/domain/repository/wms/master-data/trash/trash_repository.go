@@ -0,0 +1,18 @@
+package trash
+
+import (
+	"context"
+	"time"
+
+	trashModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/trash"
+)
+
+type TrashRepository interface {
+	List(ctx context.Context, entity trashModel.Entity, limit, page int) ([]*trashModel.Item, error)
+	Count(ctx context.Context, entity trashModel.Entity) (int, error)
+	// Restore returns nil when the row is not in the trash
+	Restore(ctx context.Context, entity trashModel.Entity, id int) (*trashModel.Item, error)
+	// Purge returns false when the row is not in the trash
+	Purge(ctx context.Context, entity trashModel.Entity, id int) (bool, error)
+	ListExpired(ctx context.Context, entity trashModel.Entity, deletedBefore time.Time) ([]int, error)
+}
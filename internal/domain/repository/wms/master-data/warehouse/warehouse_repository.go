@@ -0,0 +1,18 @@
+package warehouse
+
+import (
+	"context"
+
+	warehouseModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/warehouse"
+	"ecosystem.garyle/service/pkg/utils/filter"
+)
+
+type WarehouseRepository interface {
+	Create(ctx context.Context, warehouse *warehouseModel.Warehouse) (*warehouseModel.Warehouse, error)
+	GetByID(ctx context.Context, id int) (*warehouseModel.Warehouse, error)
+	List(ctx context.Context, limit, page int, params *filter.Params) ([]*warehouseModel.Warehouse, error)
+	Count(ctx context.Context, params *filter.Params) (int, error)
+	UpdateByID(ctx context.Context, warehouse *warehouseModel.Warehouse, id int) error
+	DeleteByID(ctx context.Context, id int) error
+	CountLocations(ctx context.Context, id int) (int, error)
+}
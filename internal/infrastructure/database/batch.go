@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
+)
+
+// InsertBatch inserts count rows inside a single transaction. Each row runs
+// behind a savepoint so a failing row can be skipped without aborting the
+// transaction. report is called once per row with the row's error (or nil).
+//
+// The transaction is rolled back on a dry run, and on the first failing row
+// in all-or-nothing mode (ErrAborted is returned then). A dry run keeps going
+// after failures so every row error is reported.
+func InsertBatch(ctx context.Context, db *sql.DB, count int, opts imports.Options, insert func(tx *sql.Tx, index int) error, report func(index int, err error)) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	failed := false
+	for i := 0; i < count; i++ {
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT batch_row"); err != nil {
+			return err
+		}
+
+		if err := insert(tx, i); err != nil {
+			if _, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT batch_row"); rollbackErr != nil {
+				return rollbackErr
+			}
+
+			report(i, Conflict(err))
+			failed = true
+
+			if opts.Mode != imports.SkipInvalid && !opts.DryRun {
+				return imports.ErrAborted
+			}
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT batch_row"); err != nil {
+			return err
+		}
+
+		report(i, nil)
+	}
+
+	if opts.DryRun {
+		return nil
+	}
+
+	if failed && opts.Mode != imports.SkipInvalid {
+		return imports.ErrAborted
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"errors"
+
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/conflict"
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the Postgres error code of a unique violation
+const uniqueViolation = "23505"
+
+// uniqueKeys maps the unique indexes of the master-data tables to the key they
+// protect. The products_sku_key and locations_code_key constraints are the
+// ones from before migration 000021.
+var uniqueKeys = map[string]conflict.Error{
+	"uq_products_sku":                       {Entity: "product", Fields: []string{"sku"}},
+	"products_sku_key":                      {Entity: "product", Fields: []string{"sku"}},
+	"uq_locations_code":                     {Entity: "location", Fields: []string{"code"}},
+	"locations_code_key":                    {Entity: "location", Fields: []string{"code"}},
+	"uq_warehouses_code":                    {Entity: "warehouse", Fields: []string{"code"}},
+	"uq_batches_product_batch_no":           {Entity: "batch", Fields: []string{"product_id", "batch_no"}},
+	"uq_product_suppliers_product_supplier": {Entity: "product supplier", Fields: []string{"product_id", "supplier_id"}},
+	"uq_product_suppliers_preferred":        {Entity: "preferred product supplier", Fields: []string{"product_id"}},
+	"uq_suppliers_tax_id":                   {Entity: "supplier", Fields: []string{"tax_country", "tax_id"}},
+	"uq_customers_tax_id":                   {Entity: "customer", Fields: []string{"tax_country", "tax_id"}},
+}
+
+// Conflict turns a unique violation of a known index into a *conflict.Error,
+// any other error is returned as is
+func Conflict(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != uniqueViolation {
+		return err
+	}
+
+	key, ok := uniqueKeys[pqErr.Constraint]
+	if !ok {
+		return err
+	}
+
+	return &conflict.Error{Entity: key.Entity, Fields: key.Fields}
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	historyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/history"
+)
+
+// BeginAuthored begins a transaction that names the author of its changes,
+// see historyModel.WithAuthor. The record_version trigger stores the author
+// with the versions it records for the updated rows.
+func BeginAuthored(ctx context.Context, db *sql.DB) (*sql.Tx, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if author := historyModel.AuthorFrom(ctx); author != "" {
+		if _, err := tx.ExecContext(ctx, `SELECT set_config('app.changed_by', $1, true)`, author); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	return tx, nil
+}
+
+// ExecAuthored runs a single write statement in a transaction begun with
+// BeginAuthored
+func ExecAuthored(ctx context.Context, db *sql.DB, query string, args ...interface{}) (sql.Result, error) {
+	tx, err := BeginAuthored(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, tx.Commit()
+}
@@ -0,0 +1,168 @@
+package batch
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	batchModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/batch"
+	batchRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/batch"
+	"ecosystem.garyle/service/internal/infrastructure/database"
+	"ecosystem.garyle/service/pkg/utils/filter"
+)
+
+const batchColumns = `id, product_id, batch_no, manufacture_date, expire_date, ` + batchModel.StatusColumn + `, created_at, updated_at, deleted_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanBatch(row rowScanner, batch *batchModel.Batch) error {
+	return row.Scan(
+		&batch.ID,
+		&batch.ProductID,
+		&batch.BatchNo,
+		&batch.ManufactureDate,
+		&batch.ExpireDate,
+		&batch.Status,
+		&batch.CreatedAt,
+		&batch.UpdatedAt,
+		&batch.DeletedAt,
+	)
+}
+
+type batchRepository struct {
+	db *sql.DB
+}
+
+func NewBatchRepository(db *sql.DB) batchRepo.BatchRepository {
+	return &batchRepository{db: db}
+}
+
+// Create implements batch.BatchRepository.
+func (b *batchRepository) Create(ctx context.Context, batch *batchModel.Batch) (*batchModel.Batch, error) {
+	query := `
+		INSERT INTO batches (product_id, batch_no, manufacture_date, expire_date, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	now := time.Now()
+	batch.CreatedAt = now
+	batch.UpdatedAt = now
+
+	err := b.db.QueryRowContext(
+		ctx,
+		query,
+		batch.ProductID,
+		batch.BatchNo,
+		batch.ManufactureDate,
+		batch.ExpireDate,
+		batch.Status,
+		batch.CreatedAt,
+		batch.UpdatedAt,
+	).Scan(&batch.ID)
+	if err != nil {
+		return nil, database.Conflict(err)
+	}
+
+	return batch, nil
+}
+
+// GetByID implements batch.BatchRepository.
+func (b *batchRepository) GetByID(ctx context.Context, id int) (*batchModel.Batch, error) {
+	query := `
+		SELECT ` + batchColumns + `
+		FROM batches
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	var batch batchModel.Batch
+	err := scanBatch(b.db.QueryRowContext(ctx, query, id), &batch)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &batch, nil
+}
+
+// List implements batch.BatchRepository.
+func (b *batchRepository) List(ctx context.Context, limit, page int, params *filter.Params) ([]*batchModel.Batch, error) {
+	where, args := params.Where(1)
+
+	query := `
+		SELECT ` + batchColumns + `
+		FROM batches
+		WHERE deleted_at IS NULL
+	` + where + params.OrderBy(batchModel.QuerySpec.DefaultSort...) + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+
+	args = append(args, limit, (page-1)*limit)
+	rows, err := b.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	batches := []*batchModel.Batch{}
+	for rows.Next() {
+		var batch batchModel.Batch
+		if err := scanBatch(rows, &batch); err != nil {
+			return nil, err
+		}
+
+		batches = append(batches, &batch)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return batches, nil
+}
+
+// Count implements batch.BatchRepository.
+func (b *batchRepository) Count(ctx context.Context, params *filter.Params) (int, error) {
+	where, args := params.Where(1)
+
+	query := `
+		SELECT COUNT(*) FROM batches
+		WHERE deleted_at IS NULL
+	` + where
+
+	var total int
+	err := b.db.QueryRowContext(ctx, query, args...).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// UpdateByID implements batch.BatchRepository.
+func (b *batchRepository) UpdateByID(ctx context.Context, batch *batchModel.Batch, id int) error {
+	query := `
+		UPDATE batches
+		SET batch_no = $1, manufacture_date = $2, expire_date = $3, status = $4, updated_at = $5
+		WHERE id = $6 AND deleted_at IS NULL
+	`
+
+	_, err := b.db.ExecContext(ctx, query, batch.BatchNo, batch.ManufactureDate, batch.ExpireDate, batch.Status, time.Now(), id)
+	return database.Conflict(err)
+}
+
+// DeleteByID implements batch.BatchRepository.
+func (b *batchRepository) DeleteByID(ctx context.Context, id int) error {
+	query := `
+		UPDATE batches
+		SET deleted_at = $1
+		WHERE id = $2 AND deleted_at IS NULL
+	`
+
+	_, err := b.db.ExecContext(ctx, query, time.Now(), id)
+	return err
+}
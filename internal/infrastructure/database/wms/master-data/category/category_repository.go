@@ -4,12 +4,34 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 
 	"ecosystem.garyle/service/internal/domain/model/wms/master-data/category"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
 	categoryRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/category"
+	"ecosystem.garyle/service/internal/infrastructure/database"
+	"ecosystem.garyle/service/pkg/utils/filter"
 )
 
+// treeLockQuery serializes the changes to the shape of the category tree. A
+// parent is checked and set while the lock is held, so two concurrent moves
+// cannot both pass the cycle check.
+const treeLockQuery = `SELECT pg_advisory_xact_lock(hashtext('categories'))`
+
+// subtreeQuery reports whether $2 is $1 itself or one of its descendants
+const subtreeQuery = `
+	WITH RECURSIVE subtree AS (
+		SELECT id, ARRAY[id] AS path FROM categories WHERE id = $1
+		UNION ALL
+		SELECT child.id, subtree.path || child.id
+		FROM categories child
+		JOIN subtree ON child.parent_id = subtree.id
+		WHERE NOT child.id = ANY(subtree.path)
+	)
+	SELECT EXISTS (SELECT 1 FROM subtree WHERE id = $2)
+`
+
 type categoryRepository struct {
 	sql *sql.DB
 }
@@ -18,37 +40,99 @@ func NewCategoryRepository(sql *sql.DB) categoryRepo.CategoryRepository {
 	return &categoryRepository{sql: sql}
 }
 
+// beginTreeChange begins an authored transaction that holds the category
+// tree lock until it ends
+func (c *categoryRepository) beginTreeChange(ctx context.Context) (*sql.Tx, error) {
+	tx, err := database.BeginAuthored(ctx, c.sql)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, treeLockQuery); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// checkParent checks, with the tree lock held, that the parent is active and
+// is not the category itself or one of its descendants. An id of 0 is a
+// category that does not exist yet.
+func checkParent(ctx context.Context, tx *sql.Tx, id int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+
+	var active bool
+	err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1 AND deleted_at IS NULL)`, *parentID).Scan(&active)
+	if err != nil {
+		return err
+	}
+
+	if !active {
+		return errors.New("parent category not found")
+	}
+
+	if id == 0 {
+		return nil
+	}
+
+	var cycle bool
+	if err := tx.QueryRowContext(ctx, subtreeQuery, id, *parentID).Scan(&cycle); err != nil {
+		return err
+	}
+
+	if cycle {
+		return errors.New("category cannot be moved under itself or one of its descendants")
+	}
+
+	return nil
+}
+
 // Create implements category.CategoryRepository.
 func (c *categoryRepository) Create(ctx context.Context, category *category.Category) (*category.Category, error) {
 	query := `
-		INSERT INTO categories (name, parent_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO categories (name, parent_id, attributes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id
 	`
 
+	tx, err := c.beginTreeChange(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := checkParent(ctx, tx, 0, category.ParentID); err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 	category.CreatedAt = now
 	category.UpdatedAt = now
 
-	err := c.sql.QueryRowContext(ctx, query, category.Name, category.ParentID, category.CreatedAt, category.UpdatedAt).Scan(&category.ID)
+	err = tx.QueryRowContext(ctx, query, category.Name, category.ParentID, category.Attributes, category.CreatedAt, category.UpdatedAt).Scan(&category.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	return category, nil
+	return category, tx.Commit()
 }
 
 // List implements category.CategoryRepository.
-func (c *categoryRepository) List(ctx context.Context, limit int, page int) ([]*category.Category, error) {
+func (c *categoryRepository) List(ctx context.Context, limit int, page int, params *filter.Params) ([]*category.Category, error) {
+	where, args := params.Where(1)
+
 	query := `
-		SELECT * FROM categories
+		SELECT id, name, parent_id, attributes, created_at, updated_at, deleted_at
+		FROM categories
 		WHERE deleted_at IS NULL
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
+	` + where + params.OrderBy(category.QuerySpec.DefaultSort...) + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
 
 	offset := (page - 1) * limit
-	rows, err := c.sql.QueryContext(ctx, query, limit, offset)
+	args = append(args, limit, offset)
+	rows, err := c.sql.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +141,7 @@ func (c *categoryRepository) List(ctx context.Context, limit int, page int) ([]*
 	var categories []*category.Category
 	for rows.Next() {
 		var category category.Category
-		err := rows.Scan(&category.ID, &category.Name, &category.ParentID, &category.CreatedAt, &category.UpdatedAt, &category.DeletedAt)
+		err := rows.Scan(&category.ID, &category.Name, &category.ParentID, &category.Attributes, &category.CreatedAt, &category.UpdatedAt, &category.DeletedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -77,14 +161,16 @@ func (c *categoryRepository) List(ctx context.Context, limit int, page int) ([]*
 }
 
 // Count implements category.CategoryRepository.
-func (c *categoryRepository) Count(ctx context.Context) (int, error) {
+func (c *categoryRepository) Count(ctx context.Context, params *filter.Params) (int, error) {
+	where, args := params.Where(1)
+
 	query := `
 		SELECT COUNT(*) FROM categories
 		WHERE deleted_at IS NULL
-	`
+	` + where
 
 	var count int
-	err := c.sql.QueryRowContext(ctx, query).Scan(&count)
+	err := c.sql.QueryRowContext(ctx, query, args...).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -94,14 +180,15 @@ func (c *categoryRepository) Count(ctx context.Context) (int, error) {
 // GetByID implements category.CategoryRepository.
 func (c *categoryRepository) GetByID(ctx context.Context, id int) (*category.Category, error) {
 	query := `
-		SELECT * FROM categories
-		WHERE id = ? AND deleted_at IS NULL
+		SELECT id, name, parent_id, attributes, created_at, updated_at, deleted_at
+		FROM categories
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 	var category category.Category
-	err := c.sql.QueryRowContext(ctx, query, id).Scan(&category.ID, &category.Name, &category.ParentID, &category.CreatedAt, &category.UpdatedAt, &category.DeletedAt)
+	err := c.sql.QueryRowContext(ctx, query, id).Scan(&category.ID, &category.Name, &category.ParentID, &category.Attributes, &category.CreatedAt, &category.UpdatedAt, &category.DeletedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("category not found") // Not found
+			return nil, nil
 		}
 		return nil, err
 	}
@@ -113,29 +200,339 @@ func (c *categoryRepository) GetByID(ctx context.Context, id int) (*category.Cat
 func (c *categoryRepository) Update(ctx context.Context, category *category.Category, id int) (*category.Category, error) {
 	query := `
 		UPDATE categories
-		SET name = $1, parent_id = $2, updated_at = $3
-		WHERE id = $4 AND deleted_at IS NULL
+		SET name = $1, parent_id = $2, attributes = COALESCE($3, attributes), updated_at = $4
+		WHERE id = $5 AND deleted_at IS NULL
+		RETURNING id, attributes, created_at
 	`
 
-	_, err := c.sql.ExecContext(ctx, query, category.Name, category.ParentID, category.UpdatedAt, id)
+	tx, err := c.beginTreeChange(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := checkParent(ctx, tx, id, category.ParentID); err != nil {
+		return nil, err
+	}
+
+	category.UpdatedAt = time.Now()
+	err = tx.QueryRowContext(ctx, query, category.Name, category.ParentID, category.Attributes, category.UpdatedAt, id).Scan(&category.ID, &category.Attributes, &category.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
 
-	return category, nil
+	return category, tx.Commit()
 }
 
-// Delete implements category.CategoryRepository.
+// Delete implements category.CategoryRepository. The children are counted
+// again with the tree lock held, so none can be moved under the category
+// while it is deleted.
 func (c *categoryRepository) Delete(ctx context.Context, id int) error {
+	tx, err := c.beginTreeChange(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var children int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM categories WHERE parent_id = $1 AND deleted_at IS NULL`, id).Scan(&children); err != nil {
+		return err
+	}
+
+	if children > 0 {
+		return errors.New("category still has child categories")
+	}
+
 	query := `
 		UPDATE categories
 		SET deleted_at = $2, updated_at = $2
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	if _, err := c.sql.ExecContext(ctx, query, id, time.Now()); err != nil {
+	if _, err := tx.ExecContext(ctx, query, id, time.Now()); err != nil {
 		return err
 	}
 
-	return nil
+	return tx.Commit()
+}
+
+// ListTree implements category.CategoryRepository. Categories under a
+// deleted category are left out with it.
+func (c *categoryRepository) ListTree(ctx context.Context) ([]*category.Node, error) {
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT id, 0 AS depth, ARRAY[id] AS path
+			FROM categories
+			WHERE parent_id IS NULL AND deleted_at IS NULL
+			UNION ALL
+			SELECT child.id, tree.depth + 1, tree.path || child.id
+			FROM categories child
+			JOIN tree ON child.parent_id = tree.id
+			WHERE child.deleted_at IS NULL AND NOT child.id = ANY(tree.path)
+		)
+		SELECT categories.id, categories.name, categories.parent_id, categories.attributes, categories.created_at, categories.updated_at, categories.deleted_at, tree.depth
+		FROM tree
+		JOIN categories ON categories.id = tree.id
+		ORDER BY tree.path
+	`
+
+	return c.queryNodes(ctx, query)
+}
+
+// GetSubtree implements category.CategoryRepository. The depth of every node
+// counts from the root of the whole tree.
+func (c *categoryRepository) GetSubtree(ctx context.Context, id int) ([]*category.Node, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, ARRAY[id] AS path
+			FROM categories
+			WHERE id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT parent.id, parent.parent_id, ancestors.path || parent.id
+			FROM categories parent
+			JOIN ancestors ON parent.id = ancestors.parent_id
+			WHERE parent.deleted_at IS NULL AND NOT parent.id = ANY(ancestors.path)
+		), subtree AS (
+			SELECT id, (SELECT COUNT(*) - 1 FROM ancestors)::int AS depth, ARRAY[id] AS path
+			FROM categories
+			WHERE id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT child.id, subtree.depth + 1, subtree.path || child.id
+			FROM categories child
+			JOIN subtree ON child.parent_id = subtree.id
+			WHERE child.deleted_at IS NULL AND NOT child.id = ANY(subtree.path)
+		)
+		SELECT categories.id, categories.name, categories.parent_id, categories.attributes, categories.created_at, categories.updated_at, categories.deleted_at, subtree.depth
+		FROM subtree
+		JOIN categories ON categories.id = subtree.id
+		ORDER BY subtree.path
+	`
+
+	return c.queryNodes(ctx, query, id)
+}
+
+// ListAncestors implements category.CategoryRepository. The path runs from
+// the root down to the category itself.
+func (c *categoryRepository) ListAncestors(ctx context.Context, id int) ([]*category.Node, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, ARRAY[id] AS path
+			FROM categories
+			WHERE id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT parent.id, parent.parent_id, ancestors.path || parent.id
+			FROM categories parent
+			JOIN ancestors ON parent.id = ancestors.parent_id
+			WHERE parent.deleted_at IS NULL AND NOT parent.id = ANY(ancestors.path)
+		)
+		SELECT categories.id, categories.name, categories.parent_id, categories.attributes, categories.created_at, categories.updated_at, categories.deleted_at,
+			((SELECT COUNT(*) FROM ancestors) - cardinality(ancestors.path))::int AS depth
+		FROM ancestors
+		JOIN categories ON categories.id = ancestors.id
+		ORDER BY depth
+	`
+
+	return c.queryNodes(ctx, query, id)
+}
+
+func (c *categoryRepository) queryNodes(ctx context.Context, query string, args ...interface{}) ([]*category.Node, error) {
+	rows, err := c.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	nodes := []*category.Node{}
+	for rows.Next() {
+		node := &category.Node{Category: &category.Category{}}
+		if err := rows.Scan(&node.ID, &node.Name, &node.ParentID, &node.Attributes, &node.CreatedAt, &node.UpdatedAt, &node.DeletedAt, &node.Depth); err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
+
+// Move implements category.CategoryRepository. The new parent is checked
+// again with the tree lock held.
+func (c *categoryRepository) Move(ctx context.Context, id int, parentID *int) error {
+	query := `
+		UPDATE categories
+		SET parent_id = $1, updated_at = $2
+		WHERE id = $3 AND deleted_at IS NULL
+	`
+
+	tx, err := c.beginTreeChange(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkParent(ctx, tx, id, parentID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, query, parentID, time.Now(), id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// IsInSubtree implements category.CategoryRepository. It reports whether id
+// is rootID itself or one of its descendants.
+func (c *categoryRepository) IsInSubtree(ctx context.Context, rootID, id int) (bool, error) {
+	var found bool
+	if err := c.sql.QueryRowContext(ctx, subtreeQuery, rootID, id).Scan(&found); err != nil {
+		return false, err
+	}
+
+	return found, nil
+}
+
+// CountChildren implements category.CategoryRepository.
+func (c *categoryRepository) CountChildren(ctx context.Context, id int) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM categories
+		WHERE parent_id = $1 AND deleted_at IS NULL
+	`
+
+	var total int
+	if err := c.sql.QueryRowContext(ctx, query, id).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// DeleteSubtree implements category.CategoryRepository. The category and all
+// of its descendants are deleted at once.
+func (c *categoryRepository) DeleteSubtree(ctx context.Context, id int) error {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id, ARRAY[id] AS path FROM categories WHERE id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT child.id, subtree.path || child.id
+			FROM categories child
+			JOIN subtree ON child.parent_id = subtree.id
+			WHERE child.deleted_at IS NULL AND NOT child.id = ANY(subtree.path)
+		)
+		UPDATE categories
+		SET deleted_at = $2, updated_at = $2
+		WHERE id IN (SELECT id FROM subtree)
+	`
+
+	tx, err := c.beginTreeChange(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, query, id, time.Now()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteAndReparent implements category.CategoryRepository. The children move
+// up to the parent of the category in the same transaction as the delete.
+func (c *categoryRepository) DeleteAndReparent(ctx context.Context, id int) error {
+	tx, err := c.beginTreeChange(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	query := `
+		UPDATE categories
+		SET parent_id = (SELECT parent_id FROM categories WHERE id = $1), updated_at = $2
+		WHERE parent_id = $1 AND deleted_at IS NULL
+	`
+
+	if _, err := tx.ExecContext(ctx, query, id, now); err != nil {
+		return err
+	}
+
+	query = `
+		UPDATE categories
+		SET deleted_at = $2, updated_at = $2
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	if _, err := tx.ExecContext(ctx, query, id, now); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateBatch inserts categories in one transaction, see database.InsertBatch.
+// The parent of each row is checked again with the tree lock held.
+func (c *categoryRepository) CreateBatch(ctx context.Context, categories []*category.Category, opts imports.Options, report func(index int, err error)) error {
+	query := `
+		INSERT INTO categories (name, parent_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	now := time.Now()
+	return database.InsertBatch(ctx, c.sql, len(categories), opts, func(tx *sql.Tx, index int) error {
+		item := categories[index]
+		item.CreatedAt = now
+		item.UpdatedAt = now
+
+		if _, err := tx.ExecContext(ctx, treeLockQuery); err != nil {
+			return err
+		}
+
+		if err := checkParent(ctx, tx, 0, item.ParentID); err != nil {
+			return err
+		}
+
+		return tx.QueryRowContext(ctx, query, item.Name, item.ParentID, item.CreatedAt, item.UpdatedAt).Scan(&item.ID)
+	}, report)
+}
+
+// Export streams the categories matching the list filters to fn one row at a time
+func (c *categoryRepository) Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(category *category.Category) error) error {
+	where, args := params.Where(1)
+
+	scope := "deleted_at IS NULL"
+	if includeDeleted {
+		scope = "TRUE"
+	}
+
+	query := `
+		SELECT id, name, parent_id, created_at, updated_at, deleted_at
+		FROM categories
+		WHERE ` + scope + where + params.OrderBy(category.QuerySpec.DefaultSort...)
+
+	rows, err := c.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var item category.Category
+		if err := rows.Scan(&item.ID, &item.Name, &item.ParentID, &item.CreatedAt, &item.UpdatedAt, &item.DeletedAt); err != nil {
+			return err
+		}
+
+		if err := fn(&item); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
 }
@@ -3,38 +3,90 @@ package customer
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
 	customerModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/customer"
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
+	partyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
 	customerRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/customer"
+	"ecosystem.garyle/service/internal/infrastructure/database"
+	partyRepo "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/party"
+	"ecosystem.garyle/service/pkg/utils/fieldcrypt"
+	"ecosystem.garyle/service/pkg/utils/filter"
 )
 
+const customerColumns = `id, name, address, contact, ` + partyRepo.RegistrationColumns + `, erased_at, created_at, updated_at, deleted_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCustomer(row rowScanner, item *customerModel.Customer) error {
+	dest := []interface{}{&item.ID, &item.Name, &item.Address, &item.Contact}
+	dest = append(dest, partyRepo.RegistrationFields(&item.Registration)...)
+	return row.Scan(append(dest, &item.ErasedAt, &item.CreatedAt, &item.UpdatedAt, &item.DeletedAt)...)
+}
+
+// customerRepository encrypts the personal data of customers with its keyring:
+// the free-text address and contact, see customerPersonalColumns, and the
+// personal columns of addresses, contacts and merge snapshots. Callers only
+// see plain values.
 type customerRepository struct {
+	*partyRepo.DetailRepository
 	db *sql.DB
 }
 
-func NewCustomerRepository(db *sql.DB) customerRepo.CustomerRepository {
-	return &customerRepository{db: db}
+// customerPersonalColumns are the encrypted columns of the customers table
+var customerPersonalColumns = []string{"address", "contact"}
+
+// NewCustomerRepository creates the customer repository. A nil keyring stores
+// personal data in plain text.
+func NewCustomerRepository(db *sql.DB, keyring *fieldcrypt.Keyring) customerRepo.CustomerRepository {
+	return &customerRepository{DetailRepository: partyRepo.NewDetailRepository(db, "customer", keyring), db: db}
+}
+
+func (c *customerRepository) scan(row rowScanner, item *customerModel.Customer) error {
+	if err := scanCustomer(row, item); err != nil {
+		return err
+	}
+
+	return c.Open(&item.Address, &item.Contact)
 }
 
-func (c *customerRepository) Count(ctx context.Context) (int, error) {
+func (c *customerRepository) Count(ctx context.Context, params *filter.Params) (int, error) {
+	where, args := params.Where(1)
+
 	query := `
 		SELECT COUNT(*) FROM customers
 		WHERE deleted_at IS NULL
-	`
+	` + where
 
 	var count int
-	if err := c.db.QueryRowContext(ctx, query).Scan(&count); err != nil {
+	if err := c.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
 		return 0, err
 	}
 
 	return count, nil
 }
 
+// Create implements customer.CustomerRepository. The addresses and contacts
+// of the customer are saved in the same transaction.
 func (c *customerRepository) Create(ctx context.Context, customer *customerModel.Customer) (*customerModel.Customer, error) {
+	sealed, err := c.Seal(customer.Address, customer.Contact)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO customers (name, address, contact, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO customers (name, address, contact, tax_country, tax_id, registration_number, payment_terms, currency, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id
 	`
 
@@ -42,20 +94,32 @@ func (c *customerRepository) Create(ctx context.Context, customer *customerModel
 	customer.CreatedAt = now
 	customer.UpdatedAt = now
 
-	var newCustomer customerModel.Customer
-	if err := c.db.QueryRowContext(
+	if err := tx.QueryRowContext(
 		ctx,
 		query,
 		customer.Name,
-		customer.Address,
-		customer.Contact,
+		sealed[0],
+		sealed[1],
+		customer.TaxCountry,
+		customer.TaxID,
+		customer.RegistrationNumber,
+		customer.PaymentTerms,
+		customer.Currency,
 		customer.CreatedAt,
 		customer.UpdatedAt,
-	).Scan(&newCustomer.ID); err != nil {
+	).Scan(&customer.ID); err != nil {
+		return nil, database.Conflict(err)
+	}
+
+	if err := c.SaveDetails(ctx, tx, customer.ID, customer.Addresses, customer.Contacts); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
-	return &newCustomer, nil
+	return customer, nil
 }
 
 // DeleteByID implements customer.CustomerRepository.
@@ -66,7 +130,7 @@ func (c *customerRepository) DeleteByID(ctx context.Context, id int) error {
 		WHERE id = $2 AND deleted_at IS NULL
 	`
 
-	if _, err := c.db.ExecContext(ctx, query, time.Now(), id); err != nil {
+	if _, err := database.ExecAuthored(ctx, c.db, query, time.Now(), id); err != nil {
 		return err
 	}
 
@@ -76,20 +140,13 @@ func (c *customerRepository) DeleteByID(ctx context.Context, id int) error {
 // GetByID implements customer.CustomerRepository.
 func (c *customerRepository) GetByID(ctx context.Context, id int) (*customerModel.Customer, error) {
 	query := `
-		SELECT * FROM customers
+		SELECT ` + customerColumns + `
+		FROM customers
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var customer customerModel.Customer
-	if err := c.db.QueryRowContext(ctx, query, id).Scan(
-		&customer.ID,
-		&customer.Name,
-		&customer.Address,
-		&customer.Contact,
-		&customer.CreatedAt,
-		&customer.UpdatedAt,
-		&customer.DeletedAt,
-	); err != nil {
+	if err := c.scan(c.db.QueryRowContext(ctx, query, id), &customer); err != nil {
 		// data not found
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -102,15 +159,18 @@ func (c *customerRepository) GetByID(ctx context.Context, id int) (*customerMode
 }
 
 // List implements customer.CustomerRepository.
-func (c *customerRepository) List(ctx context.Context, limit int, page int) ([]*customerModel.Customer, error) {
+func (c *customerRepository) List(ctx context.Context, limit int, page int, params *filter.Params) ([]*customerModel.Customer, error) {
+	where, args := params.Where(1)
+
 	query := `
-		SELECT * FROM customers
+		SELECT ` + customerColumns + `
+		FROM customers
 		WHERE deleted_at IS NULL
-		LIMIT $1 OFFSET $2
-	`
+	` + where + params.OrderBy(customerModel.QuerySpec.DefaultSort...) + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
 
 	offset := (page - 1) * limit
-	rows, err := c.db.QueryContext(ctx, query, limit, offset)
+	args = append(args, limit, offset)
+	rows, err := c.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -121,15 +181,7 @@ func (c *customerRepository) List(ctx context.Context, limit int, page int) ([]*
 	// jika rows masih bisa di next (masih ada data di page selanjutnya dengan limit tertentu)
 	for rows.Next() {
 		var customer customerModel.Customer
-		if err := rows.Scan(
-			&customer.ID,
-			&customer.Name,
-			&customer.Address,
-			&customer.Contact,
-			&customer.CreatedAt,
-			&customer.UpdatedAt,
-			&customer.DeletedAt,
-		); err != nil {
+		if err := c.scan(rows, &customer); err != nil {
 			return nil, err
 		}
 
@@ -151,13 +203,113 @@ func (c *customerRepository) List(ctx context.Context, limit int, page int) ([]*
 func (c *customerRepository) UpdateByID(ctx context.Context, customer *customerModel.Customer, id int) error {
 	query := `
 		UPDATE customers
-		SET name = $1, address = $2, contact = $3, updated_at = $4
-		WHERE id = $5 AND deleted_at IS NULL
+		SET name = $1, address = $2, contact = $3, tax_country = $4, tax_id = $5, registration_number = $6, payment_terms = $7, currency = $8, updated_at = $9
+		WHERE id = $10 AND deleted_at IS NULL
 	`
 
-	if _, err := c.db.ExecContext(ctx, query, customer.Name, customer.Address, customer.Contact, customer.UpdatedAt, id); err != nil {
+	sealed, err := c.Seal(customer.Address, customer.Contact)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	tx, err := database.BeginAuthored(ctx, c.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	customer.UpdatedAt = time.Now()
+	if _, err := tx.ExecContext(ctx, query, customer.Name, sealed[0], sealed[1], customer.TaxCountry, customer.TaxID, customer.RegistrationNumber, customer.PaymentTerms, customer.Currency, customer.UpdatedAt, id); err != nil {
+		return database.Conflict(err)
+	}
+
+	return tx.Commit()
+}
+
+// CreateBatch inserts customers in one transaction, see database.InsertBatch
+func (c *customerRepository) CreateBatch(ctx context.Context, customers []*customerModel.Customer, opts imports.Options, report func(index int, err error)) error {
+	query := `
+		INSERT INTO customers (name, address, contact, tax_country, tax_id, registration_number, payment_terms, currency, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+
+	now := time.Now()
+	return database.InsertBatch(ctx, c.db, len(customers), opts, func(tx *sql.Tx, index int) error {
+		item := customers[index]
+		item.CreatedAt = now
+		item.UpdatedAt = now
+
+		sealed, err := c.Seal(item.Address, item.Contact)
+		if err != nil {
+			return err
+		}
+
+		return tx.QueryRowContext(ctx, query, item.Name, sealed[0], sealed[1], item.TaxCountry, item.TaxID, item.RegistrationNumber, item.PaymentTerms, item.Currency, item.CreatedAt, item.UpdatedAt).Scan(&item.ID)
+	}, report)
+}
+
+// Export streams the customers matching the list filters to fn one row at a time
+func (c *customerRepository) Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(customer *customerModel.Customer) error) error {
+	where, args := params.Where(1)
+
+	scope := "deleted_at IS NULL"
+	if includeDeleted {
+		scope = "TRUE"
+	}
+
+	query := `
+		SELECT ` + customerColumns + `
+		FROM customers
+		WHERE ` + scope + where + params.OrderBy(customerModel.QuerySpec.DefaultSort...)
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var item customerModel.Customer
+		if err := c.scan(rows, &item); err != nil {
+			return err
+		}
+
+		if err := fn(&item); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Merge implements customer.CustomerRepository. The service profile of the
+// duplicate is kept when the survivor has none.
+func (c *customerRepository) Merge(ctx context.Context, record *partyModel.MergeRecord) error {
+	return c.MergeParty(ctx, record, moveServiceProfile)
+}
+
+func moveServiceProfile(ctx context.Context, tx *sql.Tx, fromID, toID int, now time.Time) (map[string]int64, error) {
+	query := `
+		UPDATE customer_service_profiles
+		SET customer_id = $2, updated_at = $3
+		WHERE customer_id = $1 AND NOT EXISTS (SELECT 1 FROM customer_service_profiles WHERE customer_id = $2)
+	`
+
+	result, err := tx.ExecContext(ctx, query, fromID, toID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM customer_service_profiles WHERE customer_id = $1`, fromID); err != nil {
+		return nil, err
+	}
+
+	return map[string]int64{"customer_service_profiles": moved}, nil
 }
@@ -0,0 +1,332 @@
+package customer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	partyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
+	"ecosystem.garyle/service/internal/infrastructure/database"
+	partyRepo "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/party"
+)
+
+// rotateBatchSize is the number of rows re-encrypted per transaction
+const rotateBatchSize = 500
+
+// Erase implements customer.CustomerRepository. Deleted customers are erased
+// too, the rows are kept so orders keep referring to them. The personal data
+// is removed from the change history as well.
+func (c *customerRepository) Erase(ctx context.Context, id int) (bool, error) {
+	tx, err := database.BeginAuthored(ctx, c.db)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	query := `
+		UPDATE customers
+		SET name = $2, address = '', contact = '', tax_id = '', registration_number = '', erased_at = $3, updated_at = $3
+		WHERE id = $1
+	`
+
+	erased, err := execCount(ctx, tx, query, id, fmt.Sprintf("Erased customer %d", id), now)
+	if err != nil {
+		return false, err
+	}
+
+	if erased == 0 {
+		return false, nil
+	}
+
+	query = `
+		UPDATE customer_addresses
+		SET label = '', street = '', postal_code = '', latitude = NULL, longitude = NULL, updated_at = $2
+		WHERE customer_id = $1
+	`
+
+	if _, err := tx.ExecContext(ctx, query, id, now); err != nil {
+		return false, err
+	}
+
+	query = `
+		UPDATE customer_contacts
+		SET name = 'Erased contact', phone = '', email = '', updated_at = $2
+		WHERE customer_id = $1
+	`
+
+	if _, err := tx.ExecContext(ctx, query, id, now); err != nil {
+		return false, err
+	}
+
+	query = `
+		UPDATE party_merges
+		SET snapshot = '{"erased": true}'
+		WHERE party_type = 'customer' AND (survivor_id = $1 OR duplicate_id = $1)
+	`
+
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		return false, err
+	}
+
+	query = `
+		UPDATE record_versions
+		SET snapshot = snapshot || jsonb_build_object('name', $2::text, 'address', '', 'contact', '', 'tax_id', '', 'registration_number', '')
+		WHERE entity = 'customers' AND record_id = $1
+	`
+
+	if _, err := tx.ExecContext(ctx, query, id, fmt.Sprintf("Erased customer %d", id)); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// ListProfiles implements customer.CustomerRepository. Erased customers are
+// left out of duplicate detection.
+func (c *customerRepository) ListProfiles(ctx context.Context) ([]partyModel.Profile, error) {
+	profiles, err := c.DetailRepository.ListProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.db.QueryContext(ctx, `SELECT id FROM customers WHERE erased_at IS NOT NULL AND deleted_at IS NULL`)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	erased := map[int]bool{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		erased[id] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	kept := profiles[:0]
+	for _, profile := range profiles {
+		if !erased[profile.ID] {
+			kept = append(kept, profile)
+		}
+	}
+
+	return kept, nil
+}
+
+// RotateKeys implements customer.CustomerRepository. Plain values written
+// before encryption was turned on are encrypted as well.
+func (c *customerRepository) RotateKeys(ctx context.Context) (map[string]int, error) {
+	if !c.Keyring().Enabled() {
+		return nil, errors.New("customer encryption keys are not configured")
+	}
+
+	tables := []struct {
+		table   string
+		columns []string
+	}{
+		{"customers", customerPersonalColumns},
+		{"customer_addresses", partyRepo.AddressPersonalColumns},
+		{"customer_contacts", partyRepo.ContactPersonalColumns},
+	}
+
+	rotated := map[string]int{}
+	for _, t := range tables {
+		count, err := c.rotateTable(ctx, t.table, t.columns)
+		if err != nil {
+			return nil, err
+		}
+		rotated[t.table] = count
+	}
+
+	count, err := c.rotateSnapshots(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rotated["party_merges"] = count
+
+	if count, err = c.rotateVersions(ctx); err != nil {
+		return nil, err
+	}
+	rotated["record_versions"] = count
+
+	return rotated, nil
+}
+
+// rotateTable re-encrypts the columns of a table in batches ordered by id
+func (c *customerRepository) rotateTable(ctx context.Context, table string, columns []string) (int, error) {
+	selectQuery := `SELECT id, ` + strings.Join(columns, ", ") + ` FROM ` + table + ` WHERE id > $1 ORDER BY id LIMIT $2`
+
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = fmt.Sprintf("%s = $%d", column, i+2)
+	}
+	updateQuery := `UPDATE ` + table + ` SET ` + strings.Join(assignments, ", ") + ` WHERE id = $1`
+
+	return c.rotateBatches(ctx, selectQuery, updateQuery, len(columns))
+}
+
+// rotateSnapshots re-encrypts the merge snapshots of customers, which are
+// stored as a JSON string when encrypted, see partyRepo.DetailRepository
+func (c *customerRepository) rotateSnapshots(ctx context.Context) (int, error) {
+	selectQuery := `
+		SELECT id, snapshot #>> '{}'
+		FROM party_merges
+		WHERE party_type = 'customer' AND id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+	updateQuery := `UPDATE party_merges SET snapshot = to_jsonb($2::text) WHERE id = $1`
+
+	return c.rotateBatches(ctx, selectQuery, updateQuery, 1)
+}
+
+// rotateVersions re-encrypts the address and contact of the customer
+// versions in the change history
+func (c *customerRepository) rotateVersions(ctx context.Context) (int, error) {
+	selectQuery := `
+		SELECT id, COALESCE(snapshot->>'address', ''), COALESCE(snapshot->>'contact', '')
+		FROM record_versions
+		WHERE entity = 'customers' AND id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+	updateQuery := `UPDATE record_versions SET snapshot = snapshot || jsonb_build_object('address', $2::text, 'contact', $3::text) WHERE id = $1`
+
+	return c.rotateBatches(ctx, selectQuery, updateQuery, 2)
+}
+
+// rotateBatches pages through the rows of selectQuery, which selects the id
+// and the encrypted columns, and rewrites the rows that need rotation with
+// updateQuery. Each batch is committed on its own so a long rotation can be
+// resumed by running it again.
+func (c *customerRepository) rotateBatches(ctx context.Context, selectQuery, updateQuery string, columns int) (int, error) {
+	keyring := c.Keyring()
+	rotated, lastID := 0, 0
+	for {
+		tx, err := c.db.BeginTx(ctx, nil)
+		if err != nil {
+			return rotated, err
+		}
+
+		// re-encrypting does not change the data, so it is not a new version
+		if _, err := tx.ExecContext(ctx, `SELECT set_config('app.record_history', 'off', true)`); err != nil {
+			tx.Rollback()
+			return rotated, err
+		}
+
+		count, last, done, err := rotateBatch(ctx, tx, selectQuery, updateQuery, columns, lastID, func(values []string) (bool, error) {
+			changed := false
+			for i, value := range values {
+				if !keyring.NeedsRotation(value) {
+					continue
+				}
+
+				plain, err := keyring.Decrypt(value)
+				if err != nil {
+					return false, err
+				}
+
+				if values[i], err = keyring.Encrypt(plain); err != nil {
+					return false, err
+				}
+				changed = true
+			}
+
+			return changed, nil
+		})
+		if err != nil {
+			tx.Rollback()
+			return rotated, err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return rotated, err
+		}
+
+		rotated += count
+		lastID = last
+		if done {
+			return rotated, nil
+		}
+	}
+}
+
+func rotateBatch(ctx context.Context, tx *sql.Tx, selectQuery, updateQuery string, columns, afterID int, rotate func(values []string) (bool, error)) (int, int, bool, error) {
+	rows, err := tx.QueryContext(ctx, selectQuery+` FOR UPDATE`, afterID, rotateBatchSize)
+	if err != nil {
+		return 0, afterID, false, err
+	}
+
+	type row struct {
+		id     int
+		values []string
+	}
+
+	batch := []row{}
+	for rows.Next() {
+		item := row{values: make([]string, columns)}
+		dest := []interface{}{&item.id}
+		for i := range item.values {
+			dest = append(dest, &item.values[i])
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close()
+			return 0, afterID, false, err
+		}
+
+		batch = append(batch, item)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return 0, afterID, false, err
+	}
+
+	rotated := 0
+	for _, item := range batch {
+		changed, err := rotate(item.values)
+		if err != nil {
+			return 0, afterID, false, fmt.Errorf("row %d: %w", item.id, err)
+		}
+
+		if !changed {
+			continue
+		}
+
+		args := []interface{}{item.id}
+		for _, value := range item.values {
+			args = append(args, value)
+		}
+
+		if _, err := tx.ExecContext(ctx, updateQuery, args...); err != nil {
+			return 0, afterID, false, err
+		}
+		rotated++
+	}
+
+	if len(batch) == 0 {
+		return 0, afterID, true, nil
+	}
+
+	return rotated, batch[len(batch)-1].id, len(batch) < rotateBatchSize, nil
+}
+
+func execCount(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (int64, error) {
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
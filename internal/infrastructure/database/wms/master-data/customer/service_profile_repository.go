@@ -0,0 +1,102 @@
+package customer
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	customerModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/customer"
+	"github.com/lib/pq"
+)
+
+const serviceProfileColumns = `customer_id, order_cutoff, timezone, dispatch_lead_days, dispatch_days, allowed_carriers, preferred_carrier, split_policy, labeling_instructions, packing_instructions, priority_tier, created_at, updated_at`
+
+func scanServiceProfile(row rowScanner, profile *customerModel.ServiceProfile) error {
+	return row.Scan(
+		&profile.CustomerID,
+		&profile.OrderCutoff,
+		&profile.Timezone,
+		&profile.DispatchLeadDays,
+		pq.Array(&profile.DispatchDays),
+		pq.Array(&profile.AllowedCarriers),
+		&profile.PreferredCarrier,
+		&profile.SplitPolicy,
+		&profile.LabelingInstructions,
+		&profile.PackingInstructions,
+		&profile.PriorityTier,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+	)
+}
+
+// GetServiceProfile implements customer.CustomerRepository.
+func (c *customerRepository) GetServiceProfile(ctx context.Context, customerID int) (*customerModel.ServiceProfile, error) {
+	query := `SELECT ` + serviceProfileColumns + ` FROM customer_service_profiles WHERE customer_id = $1`
+
+	var profile customerModel.ServiceProfile
+	if err := scanServiceProfile(c.db.QueryRowContext(ctx, query, customerID), &profile); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+// SaveServiceProfile implements customer.CustomerRepository. The profile is
+// created on the first save and replaced on the next ones.
+func (c *customerRepository) SaveServiceProfile(ctx context.Context, profile *customerModel.ServiceProfile) (*customerModel.ServiceProfile, error) {
+	query := `
+		INSERT INTO customer_service_profiles (` + serviceProfileColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $12)
+		ON CONFLICT (customer_id) DO UPDATE SET
+			order_cutoff = EXCLUDED.order_cutoff,
+			timezone = EXCLUDED.timezone,
+			dispatch_lead_days = EXCLUDED.dispatch_lead_days,
+			dispatch_days = EXCLUDED.dispatch_days,
+			allowed_carriers = EXCLUDED.allowed_carriers,
+			preferred_carrier = EXCLUDED.preferred_carrier,
+			split_policy = EXCLUDED.split_policy,
+			labeling_instructions = EXCLUDED.labeling_instructions,
+			packing_instructions = EXCLUDED.packing_instructions,
+			priority_tier = EXCLUDED.priority_tier,
+			updated_at = EXCLUDED.updated_at
+		RETURNING ` + serviceProfileColumns
+
+	var saved customerModel.ServiceProfile
+	err := scanServiceProfile(c.db.QueryRowContext(ctx, query,
+		profile.CustomerID,
+		profile.OrderCutoff,
+		profile.Timezone,
+		profile.DispatchLeadDays,
+		pq.Array(profile.DispatchDays),
+		pq.Array(profile.AllowedCarriers),
+		profile.PreferredCarrier,
+		profile.SplitPolicy,
+		profile.LabelingInstructions,
+		profile.PackingInstructions,
+		profile.PriorityTier,
+		time.Now(),
+	), &saved)
+	if err != nil {
+		return nil, err
+	}
+
+	return &saved, nil
+}
+
+// DeleteServiceProfile implements customer.CustomerRepository.
+func (c *customerRepository) DeleteServiceProfile(ctx context.Context, customerID int) (bool, error) {
+	result, err := c.db.ExecContext(ctx, `DELETE FROM customer_service_profiles WHERE customer_id = $1`, customerID)
+	if err != nil {
+		return false, err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return deleted > 0, nil
+}
@@ -0,0 +1,74 @@
+package floormap
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	floorMapModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/floormap"
+	floorMapRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/floormap"
+	"ecosystem.garyle/service/internal/infrastructure/database"
+)
+
+type floorMapRepository struct {
+	db *sql.DB
+}
+
+func NewFloorMapRepository(db *sql.DB) floorMapRepo.FloorMapRepository {
+	return &floorMapRepository{db: db}
+}
+
+// Get implements floormap.FloorMapRepository. The locations are not loaded.
+func (f *floorMapRepository) Get(ctx context.Context, warehouseID int) (*floorMapModel.FloorMap, error) {
+	query := `
+		SELECT warehouse_id, graph, updated_at
+		FROM warehouse_floor_maps
+		WHERE warehouse_id = $1
+	`
+
+	var floorMap floorMapModel.FloorMap
+	err := f.db.QueryRowContext(ctx, query, warehouseID).Scan(&floorMap.WarehouseID, &floorMap.Graph, &floorMap.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &floorMap, nil
+}
+
+// Save implements floormap.FloorMapRepository. The graph is replaced and the
+// listed locations are moved in one transaction.
+func (f *floorMapRepository) Save(ctx context.Context, warehouseID int, graph floorMapModel.Graph, positions []floorMapModel.LocationPosition) error {
+	tx, err := database.BeginAuthored(ctx, f.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO warehouse_floor_maps (warehouse_id, graph, created_at, updated_at)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (warehouse_id) DO UPDATE SET graph = EXCLUDED.graph, updated_at = EXCLUDED.updated_at
+	`
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, query, warehouseID, graph, now); err != nil {
+		return err
+	}
+
+	for _, position := range positions {
+		query := `
+			UPDATE locations
+			SET x = $1, y = $2, z = $3, updated_at = $4
+			WHERE id = $5 AND warehouse_id = $6 AND deleted_at IS NULL
+		`
+
+		if _, err := tx.ExecContext(ctx, query, position.X, position.Y, position.Z, now, position.LocationID, warehouseID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
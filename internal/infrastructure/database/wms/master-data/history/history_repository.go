@@ -0,0 +1,153 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	historyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/history"
+	historyRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/history"
+	"ecosystem.garyle/service/pkg/utils/fieldcrypt"
+)
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// historyRepository reads the record_versions table. The versions are
+// written by the record_version trigger on every update of a versioned
+// table, see migration 000025.
+type historyRepository struct {
+	db *sql.DB
+	// keyring decrypts the Encrypted fields of customer snapshots
+	keyring *fieldcrypt.Keyring
+}
+
+// NewHistoryRepository creates the history repository. The keyring is the one
+// of the customer personal data and may be nil.
+func NewHistoryRepository(db *sql.DB, keyring *fieldcrypt.Keyring) historyRepo.HistoryRepository {
+	return &historyRepository{db: db, keyring: keyring}
+}
+
+// versions selects the stored versions of a row, or its current state as
+// version 1 when it was never updated. $1 is the entity and $2 the row id.
+func versions(entity historyModel.Entity) string {
+	return `
+		WITH versions AS (
+			SELECT version, changed_by, changed_at, snapshot
+			FROM record_versions
+			WHERE entity = $1 AND record_id = $2
+			UNION ALL
+			SELECT 1, '', t.updated_at, to_jsonb(t)
+			FROM ` + entity.Table + ` t
+			WHERE t.id = $2 AND NOT EXISTS (SELECT 1 FROM record_versions WHERE entity = $1 AND record_id = $2)
+		)
+	`
+}
+
+func (r *historyRepository) scan(row rowScanner, entity historyModel.Entity, id int) (*historyModel.Version, error) {
+	version := historyModel.Version{Entity: entity.Name, RecordID: id}
+	var snapshot []byte
+	if err := row.Scan(&version.Version, &version.ChangedBy, &version.ChangedAt, &snapshot); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(snapshot, &version.Snapshot); err != nil {
+		return nil, err
+	}
+
+	for _, field := range entity.Encrypted {
+		value, ok := version.Snapshot[field].(string)
+		if !ok {
+			continue
+		}
+
+		plain, err := r.keyring.Decrypt(value)
+		if err != nil {
+			return nil, err
+		}
+		version.Snapshot[field] = plain
+	}
+
+	return &version, nil
+}
+
+// List implements history.HistoryRepository.
+func (r *historyRepository) List(ctx context.Context, entity historyModel.Entity, id, limit, page int) ([]*historyModel.Version, error) {
+	query := versions(entity) + `
+		SELECT version, changed_by, changed_at, snapshot
+		FROM versions
+		ORDER BY version DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, entity.Name, id, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	items := []*historyModel.Version{}
+	for rows.Next() {
+		version, err := r.scan(rows, entity, id)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, version)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Count implements history.HistoryRepository.
+func (r *historyRepository) Count(ctx context.Context, entity historyModel.Entity, id int) (int, error) {
+	query := versions(entity) + `SELECT COUNT(*) FROM versions`
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, query, entity.Name, id).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// Get implements history.HistoryRepository.
+func (r *historyRepository) Get(ctx context.Context, entity historyModel.Entity, id, version int) (*historyModel.Version, error) {
+	query := versions(entity) + `
+		SELECT version, changed_by, changed_at, snapshot
+		FROM versions
+		WHERE version = $3
+	`
+
+	item, err := r.scan(r.db.QueryRowContext(ctx, query, entity.Name, id, version), entity, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return item, err
+}
+
+// GetAt implements history.HistoryRepository.
+func (r *historyRepository) GetAt(ctx context.Context, entity historyModel.Entity, id int, at time.Time) (*historyModel.Version, error) {
+	query := versions(entity) + `
+		SELECT version, changed_by, changed_at, snapshot
+		FROM versions
+		WHERE changed_at <= $3
+		ORDER BY version DESC
+		LIMIT 1
+	`
+
+	item, err := r.scan(r.db.QueryRowContext(ctx, query, entity.Name, id, at), entity, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return item, err
+}
@@ -3,12 +3,106 @@ package location
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
 	"ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
 	locationRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/location"
+	"ecosystem.garyle/service/internal/infrastructure/database"
+	"ecosystem.garyle/service/pkg/utils/filter"
+	"github.com/lib/pq"
 )
 
+const locationColumns = `id, warehouse_id, parent_id, level, segment, code, zone, type, capacity, min_temperature, max_temperature, hazmat_classes, is_block_stacked, accepts_fragile, max_weight, max_volume, max_units, x, y, z, ` +
+	location.StatusColumn + ` AS status, status_reason, status_note, status_set_by, status_set_at, status_expires_at, created_at, updated_at, deleted_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLocation(row rowScanner, item *location.Location) error {
+	capabilities := &location.StorageCapabilities{}
+	limits := &location.Limits{}
+	var x, y, z sql.NullFloat64
+	change := &location.StatusChange{}
+	err := row.Scan(
+		&item.ID,
+		&item.WarehouseID,
+		&item.ParentID,
+		&item.Level,
+		&item.Segment,
+		&item.Code,
+		&item.Zone,
+		&item.Type,
+		&item.Capacity,
+		&capabilities.MinTemperature,
+		&capabilities.MaxTemperature,
+		pq.Array(&capabilities.HazmatClasses),
+		&capabilities.IsBlockStacked,
+		&capabilities.AcceptsFragile,
+		&limits.MaxWeight,
+		&limits.MaxVolume,
+		&limits.MaxUnits,
+		&x,
+		&y,
+		&z,
+		&item.Status,
+		&change.Reason,
+		&change.Note,
+		&change.SetBy,
+		&change.SetAt,
+		&change.ExpiresAt,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+		&item.DeletedAt,
+	)
+	item.Capabilities = capabilities
+	item.Limits = limits
+	item.Coordinates = nil
+	if x.Valid && y.Valid {
+		item.Coordinates = &location.Coordinates{X: x.Float64, Y: y.Float64, Z: z.Float64}
+	}
+
+	item.StatusChange = nil
+	if item.Status != location.StatusActive {
+		change.Status = item.Status
+		item.StatusChange = change
+	}
+
+	return err
+}
+
+// storageArgs returns the capability, limit and coordinate column values of a location in the order of locationColumns
+func storageArgs(item *location.Location) []interface{} {
+	capabilities := item.Capabilities
+	if capabilities == nil {
+		capabilities = &location.StorageCapabilities{}
+	}
+
+	limits := item.Limits
+	if limits == nil {
+		limits = &location.Limits{}
+	}
+
+	args := []interface{}{
+		capabilities.MinTemperature,
+		capabilities.MaxTemperature,
+		pq.Array(capabilities.HazmatClasses),
+		capabilities.IsBlockStacked,
+		capabilities.AllowsFragile(),
+		limits.MaxWeight,
+		limits.MaxVolume,
+		limits.MaxUnits,
+	}
+
+	if item.Coordinates == nil {
+		return append(args, nil, nil, nil)
+	}
+
+	return append(args, item.Coordinates.X, item.Coordinates.Y, item.Coordinates.Z)
+}
+
 type locationRepository struct {
 	db *sql.DB
 }
@@ -20,45 +114,37 @@ func NewLocationRepository(db *sql.DB) locationRepo.LocationRepository {
 // Create implements location.LocationRepository.
 func (l *locationRepository) Create(ctx context.Context, location *location.Location) (*location.Location, error) {
 	query := `
-		INSERT INTO locations (code, zone, type, capacity, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, code, zone, type, capacity, created_at, updated_at
+		INSERT INTO locations (warehouse_id, parent_id, level, segment, code, zone, type, capacity,
+			min_temperature, max_temperature, hazmat_classes, is_block_stacked, accepts_fragile, max_weight, max_volume, max_units, x, y, z, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+		RETURNING ` + locationColumns + `
 	`
 
 	now := time.Now()
-	location.CreatedAt = now
-	location.UpdatedAt = now
-
-	err := l.db.QueryRowContext(ctx, query, location.Code, location.Zone, location.Type, location.Capacity, location.CreatedAt, location.UpdatedAt).Scan(
-		&location.ID,
-		&location.Code,
-		&location.Zone,
-		&location.Type,
-		&location.Capacity,
-		&location.CreatedAt,
-		&location.UpdatedAt,
-	)
+	args := append([]interface{}{location.WarehouseID, location.ParentID, location.Level, location.Segment, location.Code, location.Zone, location.Type, location.Capacity}, storageArgs(location)...)
+	args = append(args, now, now)
 
-	if err != nil {
-		return nil, err
+	if err := scanLocation(l.db.QueryRowContext(ctx, query, args...), location); err != nil {
+		return nil, database.Conflict(err)
 	}
 
 	return location, nil
 }
 
 // List implements location.LocationRepository.
-func (l *locationRepository) List(ctx context.Context, limit int, page int) ([]*location.Location, error) {
+func (l *locationRepository) List(ctx context.Context, limit int, page int, params *filter.Params) ([]*location.Location, error) {
+	where, args := params.Where(1)
+
 	query := `
-		SELECT id, code, zone, type, capacity, created_at, updated_at
+		SELECT ` + locationColumns + `
 		FROM locations
 		WHERE deleted_at IS NULL
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
+	` + where + params.OrderBy(location.QuerySpec.DefaultSort...) + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
 
 	offset := (page - 1) * limit
+	args = append(args, limit, offset)
 
-	rows, err := l.db.QueryContext(ctx, query, limit, offset)
+	rows, err := l.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -68,15 +154,7 @@ func (l *locationRepository) List(ctx context.Context, limit int, page int) ([]*
 	var locations []*location.Location
 	for rows.Next() {
 		var location location.Location
-		err := rows.Scan(
-			&location.ID,
-			&location.Code,
-			&location.Zone,
-			&location.Type,
-			&location.Capacity,
-			&location.CreatedAt,
-			&location.UpdatedAt,
-		)
+		err := scanLocation(rows, &location)
 		if err != nil {
 			return nil, err
 		}
@@ -96,14 +174,16 @@ func (l *locationRepository) List(ctx context.Context, limit int, page int) ([]*
 }
 
 // Count implements location.LocationRepository.
-func (l *locationRepository) Count(ctx context.Context) (int, error) {
+func (l *locationRepository) Count(ctx context.Context, params *filter.Params) (int, error) {
+	where, args := params.Where(1)
+
 	query := `
 		SELECT COUNT(*) FROM locations
 		WHERE deleted_at IS NULL
-	`
+	` + where
 
 	var count int
-	err := l.db.QueryRowContext(ctx, query).Scan(&count)
+	err := l.db.QueryRowContext(ctx, query, args...).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -114,22 +194,14 @@ func (l *locationRepository) Count(ctx context.Context) (int, error) {
 // GetByID implements location.LocationRepository.
 func (l *locationRepository) GetByID(ctx context.Context, id int) (*location.Location, error) {
 	query := `
-		SELECT id, code, zone, type, capacity, created_at, updated_at
+		SELECT ` + locationColumns + `
 		FROM locations
 		WHERE id = $1
 		AND deleted_at IS NULL
 	`
 
 	var location location.Location
-	err := l.db.QueryRowContext(ctx, query, id).Scan(
-		&location.ID,
-		&location.Code,
-		&location.Zone,
-		&location.Type,
-		&location.Capacity,
-		&location.CreatedAt,
-		&location.UpdatedAt,
-	)
+	err := scanLocation(l.db.QueryRowContext(ctx, query, id), &location)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -141,35 +213,223 @@ func (l *locationRepository) GetByID(ctx context.Context, id int) (*location.Loc
 	return &location, nil
 }
 
-// Update implements location.LocationRepository.
+// LockByID reads a location and locks its row until the transaction ends,
+// other writers that lock the location wait for it. It returns nil when the
+// location does not exist.
+func LockByID(ctx context.Context, tx *sql.Tx, id int) (*location.Location, error) {
+	query := `
+		SELECT ` + locationColumns + `
+		FROM locations
+		WHERE id = $1
+		AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	var item location.Location
+	if err := scanLocation(tx.QueryRowContext(ctx, query, id), &item); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// Update implements location.LocationRepository. The place of a location in
+// the hierarchy does not change, a new code is carried over to the full-path
+// codes of its descendants.
 func (l *locationRepository) Update(ctx context.Context, dataLocation *location.Location, id int) (*location.Location, error) {
-	// Allow updating all fields
+	tx, err := database.BeginAuthored(ctx, l.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var oldCode string
+	err = tx.QueryRowContext(ctx, `SELECT code FROM locations WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&oldCode)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		UPDATE locations
-		SET code = $1, zone = $2, type = $3, capacity = $4, updated_at = $5
-		WHERE id = $6
+		SET segment = $1, code = $2, zone = $3, type = $4, capacity = $5,
+			min_temperature = $6, max_temperature = $7, hazmat_classes = $8, is_block_stacked = $9, accepts_fragile = $10,
+			max_weight = $11, max_volume = $12, max_units = $13, x = $14, y = $15, z = $16, updated_at = $17
+		WHERE id = $18
 		AND deleted_at IS NULL
-		RETURNING id, code, zone, type, capacity, created_at, updated_at
+		RETURNING ` + locationColumns + `
 	`
 
 	now := time.Now()
+	args := append([]interface{}{dataLocation.Segment, dataLocation.Code, dataLocation.Zone, dataLocation.Type, dataLocation.Capacity}, storageArgs(dataLocation)...)
+	args = append(args, now, id)
 
 	var updatedLocation location.Location
-	if err := l.db.QueryRowContext(ctx, query, dataLocation.Code, dataLocation.Zone, dataLocation.Type, dataLocation.Capacity, now, id).Scan(
-		&updatedLocation.ID,
-		&updatedLocation.Code,
-		&updatedLocation.Zone,
-		&updatedLocation.Type,
-		&updatedLocation.Capacity,
-		&updatedLocation.CreatedAt,
-		&updatedLocation.UpdatedAt,
-	); err != nil {
+	if err := scanLocation(tx.QueryRowContext(ctx, query, args...), &updatedLocation); err != nil {
+		return nil, database.Conflict(err)
+	}
+
+	if oldCode != updatedLocation.Code {
+		query := `
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM locations WHERE parent_id = $1 AND deleted_at IS NULL
+				UNION ALL
+				SELECT child.id FROM locations child JOIN subtree ON child.parent_id = subtree.id
+				WHERE child.deleted_at IS NULL
+			)
+			UPDATE locations
+			SET code = $2 || SUBSTRING(code FROM $4), updated_at = $5
+			WHERE id IN (SELECT id FROM subtree) AND segment <> '' AND code LIKE $3 || '-%'
+		`
+
+		if _, err := tx.ExecContext(ctx, query, id, updatedLocation.Code, oldCode, len(oldCode)+1, now); err != nil {
+			return nil, database.Conflict(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
 	return &updatedLocation, nil
 }
 
+// GetSubtree implements location.LocationRepository.
+func (l *locationRepository) GetSubtree(ctx context.Context, id int) ([]*location.Location, error) {
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id, ARRAY[id] AS path FROM locations WHERE id = $1 AND deleted_at IS NULL
+			UNION ALL
+			SELECT child.id, subtree.path || child.id FROM locations child JOIN subtree ON child.parent_id = subtree.id
+			WHERE child.deleted_at IS NULL AND NOT child.id = ANY(subtree.path)
+		)
+		SELECT ` + locationColumns + `
+		FROM locations
+		WHERE id IN (SELECT id FROM subtree)
+		ORDER BY code
+	`
+
+	return l.query(ctx, query, id)
+}
+
+// ListByWarehouse implements location.LocationRepository.
+func (l *locationRepository) ListByWarehouse(ctx context.Context, warehouseID int) ([]*location.Location, error) {
+	query := `
+		SELECT ` + locationColumns + `
+		FROM locations
+		WHERE warehouse_id = $1 AND deleted_at IS NULL
+		ORDER BY code
+	`
+
+	return l.query(ctx, query, warehouseID)
+}
+
+// CountChildren implements location.LocationRepository.
+func (l *locationRepository) CountChildren(ctx context.Context, id int) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM locations
+		WHERE parent_id = $1 AND deleted_at IS NULL
+	`
+
+	var total int
+	if err := l.db.QueryRowContext(ctx, query, id).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// UpdateStatus implements location.LocationRepository.
+func (l *locationRepository) UpdateStatus(ctx context.Context, id int, change *location.StatusChange) (*location.Location, error) {
+	query := `
+		UPDATE locations
+		SET status = $1, status_reason = $2, status_note = $3, status_set_by = $4, status_set_at = $5, status_expires_at = $6, updated_at = $5
+		WHERE id = $7
+		AND deleted_at IS NULL
+		RETURNING ` + locationColumns + `
+	`
+
+	tx, err := database.BeginAuthored(ctx, l.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var item location.Location
+	err = scanLocation(tx.QueryRowContext(ctx, query, change.Status, change.Reason, change.Note, change.SetBy, change.SetAt, change.ExpiresAt, id), &item)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &item, tx.Commit()
+}
+
+// FindExistingCodes implements location.LocationRepository. Codes of deleted
+// locations are free to be used again.
+func (l *locationRepository) FindExistingCodes(ctx context.Context, codes []string) ([]string, error) {
+	rows, err := l.db.QueryContext(ctx, `SELECT code FROM locations WHERE code = ANY($1) AND deleted_at IS NULL`, pq.Array(codes))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	existing := []string{}
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+
+		existing = append(existing, code)
+	}
+
+	return existing, rows.Err()
+}
+
+// ListForLabels implements location.LocationRepository.
+func (l *locationRepository) ListForLabels(ctx context.Context, ids []int, zone string, limit int) ([]*location.Location, error) {
+	query := `
+		SELECT ` + locationColumns + `
+		FROM locations
+		WHERE deleted_at IS NULL AND (id = ANY($1) OR ($2 <> '' AND zone = $2))
+		ORDER BY code
+		LIMIT $3
+	`
+
+	return l.query(ctx, query, pq.Array(ids), zone, limit)
+}
+
+func (l *locationRepository) query(ctx context.Context, query string, args ...interface{}) ([]*location.Location, error) {
+	rows, err := l.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	locations := []*location.Location{}
+	for rows.Next() {
+		var item location.Location
+		if err := scanLocation(rows, &item); err != nil {
+			return nil, err
+		}
+
+		locations = append(locations, &item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return locations, nil
+}
+
 // Delete implements location.LocationRepository.
 func (l *locationRepository) Delete(ctx context.Context, id int) error {
 	query := `
@@ -181,9 +441,66 @@ func (l *locationRepository) Delete(ctx context.Context, id int) error {
 
 	now := time.Now()
 
-	if _, err := l.db.ExecContext(ctx, query, now, id); err != nil {
+	if _, err := database.ExecAuthored(ctx, l.db, query, now, id); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// CreateBatch inserts locations in one transaction, see database.InsertBatch
+func (l *locationRepository) CreateBatch(ctx context.Context, locations []*location.Location, opts imports.Options, report func(index int, err error)) error {
+	query := `
+		INSERT INTO locations (warehouse_id, parent_id, level, segment, code, zone, type, capacity,
+			min_temperature, max_temperature, hazmat_classes, is_block_stacked, accepts_fragile, max_weight, max_volume, max_units, x, y, z, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+		RETURNING id
+	`
+
+	now := time.Now()
+	return database.InsertBatch(ctx, l.db, len(locations), opts, func(tx *sql.Tx, index int) error {
+		item := locations[index]
+		item.CreatedAt = now
+		item.UpdatedAt = now
+
+		args := append([]interface{}{item.WarehouseID, item.ParentID, item.Level, item.Segment, item.Code, item.Zone, item.Type, item.Capacity}, storageArgs(item)...)
+		args = append(args, item.CreatedAt, item.UpdatedAt)
+
+		return tx.QueryRowContext(ctx, query, args...).Scan(&item.ID)
+	}, report)
+}
+
+// Export streams the locations matching the list filters to fn one row at a time
+func (l *locationRepository) Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(location *location.Location) error) error {
+	where, args := params.Where(1)
+
+	scope := "deleted_at IS NULL"
+	if includeDeleted {
+		scope = "TRUE"
+	}
+
+	query := `
+		SELECT ` + locationColumns + `
+		FROM locations
+		WHERE ` + scope + where + params.OrderBy(location.QuerySpec.DefaultSort...)
+
+	rows, err := l.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var item location.Location
+		if err := scanLocation(rows, &item); err != nil {
+			return err
+		}
+
+		if err := fn(&item); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,329 @@
+package party
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	partyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
+	"ecosystem.garyle/service/internal/infrastructure/database"
+	"ecosystem.garyle/service/pkg/utils/fieldcrypt"
+)
+
+// MoveReferences moves the rows of other tables that refer to a merged party
+// and returns the number of rows moved per table
+type MoveReferences func(ctx context.Context, tx *sql.Tx, fromID, toID int, now time.Time) (map[string]int64, error)
+
+// ListProfiles implements party.MergeRepository. Structured addresses and
+// contacts are joined into the free-text fields. They are joined here rather
+// than in SQL since the personal data columns may be encrypted.
+func (r *DetailRepository) ListProfiles(ctx context.Context) ([]partyModel.Profile, error) {
+	query := `
+		SELECT id, name, address, contact
+		FROM ` + r.partyTable + `
+		WHERE deleted_at IS NULL
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	profiles := []partyModel.Profile{}
+	index := map[int]int{}
+	for rows.Next() {
+		var profile partyModel.Profile
+		if err := rows.Scan(&profile.ID, &profile.Name, &profile.Address, &profile.Contact); err != nil {
+			return nil, err
+		}
+
+		if err := r.Open(&profile.Address, &profile.Contact); err != nil {
+			return nil, err
+		}
+
+		index[profile.ID] = len(profiles)
+		profiles = append(profiles, profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = `
+		SELECT a.` + r.ownerColumn + `, a.street, a.city, a.postal_code
+		FROM ` + r.addressTable + ` a
+		JOIN ` + r.partyTable + ` p ON p.id = a.` + r.ownerColumn + ` AND p.deleted_at IS NULL
+		WHERE a.deleted_at IS NULL
+		ORDER BY a.id
+	`
+
+	err = r.appendProfileDetails(ctx, query, func(ownerID int, values []string) {
+		profile := &profiles[index[ownerID]]
+		profile.Address = joinNonEmpty(append([]string{profile.Address}, values...)...)
+	}, AddressPersonalColumns, "street", "city", "postal_code")
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+		SELECT c.` + r.ownerColumn + `, c.phone, c.email
+		FROM ` + r.contactTable + ` c
+		JOIN ` + r.partyTable + ` p ON p.id = c.` + r.ownerColumn + ` AND p.deleted_at IS NULL
+		WHERE c.deleted_at IS NULL
+		ORDER BY c.id
+	`
+
+	err = r.appendProfileDetails(ctx, query, func(ownerID int, values []string) {
+		profile := &profiles[index[ownerID]]
+		profile.Contact = joinNonEmpty(append([]string{profile.Contact}, values...)...)
+	}, ContactPersonalColumns, "phone", "email")
+	if err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+// appendProfileDetails scans the owner ID and the named columns of a detail
+// query, decrypts the personal ones and hands them to add
+func (r *DetailRepository) appendProfileDetails(ctx context.Context, query string, add func(ownerID int, values []string), personal []string, columns ...string) error {
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var ownerID int
+		values := make([]string, len(columns))
+		dest := []interface{}{&ownerID}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+
+		for i, column := range columns {
+			if contains(personal, column) {
+				if err := r.Open(&values[i]); err != nil {
+					return err
+				}
+			}
+		}
+
+		add(ownerID, values)
+	}
+
+	return rows.Err()
+}
+
+func joinNonEmpty(values ...string) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MergeParty moves the addresses, contacts and other references of the
+// duplicate to the survivor, deletes the duplicate and writes the audit
+// record, all in one transaction
+func (r *DetailRepository) MergeParty(ctx context.Context, record *partyModel.MergeRecord, moveReferences MoveReferences) error {
+	tx, err := database.BeginAuthored(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// lock both parties so neither is changed or merged elsewhere meanwhile
+	var locked int
+	query := `SELECT COUNT(*) FROM (SELECT id FROM ` + r.partyTable + ` WHERE id IN ($1, $2) AND deleted_at IS NULL FOR UPDATE) p`
+	if err := tx.QueryRowContext(ctx, query, record.SurvivorID, record.DuplicateID).Scan(&locked); err != nil {
+		return err
+	}
+
+	if locked != 2 {
+		return errors.New(r.owner + " not found")
+	}
+
+	now := time.Now()
+	moved := map[string]int64{}
+
+	// a moved default or primary only stays one when the survivor has none
+	query = `
+		UPDATE ` + r.addressTable + ` moved
+		SET ` + r.ownerColumn + ` = $1, updated_at = $3,
+			is_default = moved.is_default AND NOT EXISTS (
+				SELECT 1 FROM ` + r.addressTable + ` kept
+				WHERE kept.` + r.ownerColumn + ` = $1 AND kept.type = moved.type AND kept.is_default AND kept.deleted_at IS NULL
+			)
+		WHERE moved.` + r.ownerColumn + ` = $2
+	`
+	if moved[r.addressTable], err = execCount(ctx, tx, query, record.SurvivorID, record.DuplicateID, now); err != nil {
+		return err
+	}
+
+	query = `
+		UPDATE ` + r.contactTable + ` moved
+		SET ` + r.ownerColumn + ` = $1, updated_at = $3,
+			is_primary = moved.is_primary AND NOT EXISTS (
+				SELECT 1 FROM ` + r.contactTable + ` kept
+				WHERE kept.` + r.ownerColumn + ` = $1 AND kept.is_primary AND kept.deleted_at IS NULL
+			)
+		WHERE moved.` + r.ownerColumn + ` = $2
+	`
+	if moved[r.contactTable], err = execCount(ctx, tx, query, record.SurvivorID, record.DuplicateID, now); err != nil {
+		return err
+	}
+
+	if moveReferences != nil {
+		references, err := moveReferences(ctx, tx, record.DuplicateID, record.SurvivorID, now)
+		if err != nil {
+			return err
+		}
+
+		for table, count := range references {
+			moved[table] = count
+		}
+	}
+
+	query = `
+		UPDATE ` + r.partyTable + `
+		SET deleted_at = $1, updated_at = $1
+		WHERE id = $2
+	`
+	if _, err := tx.ExecContext(ctx, query, now, record.DuplicateID); err != nil {
+		return err
+	}
+
+	movedJSON, err := json.Marshal(moved)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO party_merges (party_type, survivor_id, duplicate_id, merged_by, reason, moved, snapshot, merged_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	snapshot, err := r.sealSnapshot(record.Snapshot)
+	if err != nil {
+		return err
+	}
+
+	record.PartyType = r.owner
+	record.Moved = moved
+	record.MergedAt = now
+	err = tx.QueryRowContext(ctx, query, record.PartyType, record.SurvivorID, record.DuplicateID, record.MergedBy, record.Reason, movedJSON, snapshot, record.MergedAt).
+		Scan(&record.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListMerges implements party.MergeRepository.
+func (r *DetailRepository) ListMerges(ctx context.Context, survivorID int) ([]*partyModel.MergeRecord, error) {
+	query := `
+		SELECT id, party_type, survivor_id, duplicate_id, merged_by, reason, moved, snapshot, merged_at
+		FROM party_merges
+		WHERE party_type = $1 AND survivor_id = $2
+		ORDER BY merged_at DESC, id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, r.owner, survivorID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	records := []*partyModel.MergeRecord{}
+	for rows.Next() {
+		var record partyModel.MergeRecord
+		var moved, snapshot []byte
+		if err := rows.Scan(&record.ID, &record.PartyType, &record.SurvivorID, &record.DuplicateID, &record.MergedBy, &record.Reason, &moved, &snapshot, &record.MergedAt); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(moved, &record.Moved); err != nil {
+			return nil, err
+		}
+
+		if record.Snapshot, err = r.openSnapshot(snapshot); err != nil {
+			return nil, err
+		}
+
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func execCount(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (int64, error) {
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// sealSnapshot stores the snapshot of a merged party as an encrypted JSON
+// string when the repository has a keyring, since it holds personal data
+func (r *DetailRepository) sealSnapshot(snapshot json.RawMessage) ([]byte, error) {
+	if !r.keyring.Enabled() {
+		return snapshot, nil
+	}
+
+	sealed, err := r.keyring.Encrypt(string(snapshot))
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(sealed)
+}
+
+// openSnapshot reverses sealSnapshot, snapshots stored before encryption was
+// turned on are returned as they are
+func (r *DetailRepository) openSnapshot(snapshot []byte) (json.RawMessage, error) {
+	var sealed string
+	if err := json.Unmarshal(snapshot, &sealed); err != nil || !fieldcrypt.IsEncrypted(sealed) {
+		return snapshot, nil
+	}
+
+	plain, err := r.keyring.Decrypt(sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(plain), nil
+}
@@ -0,0 +1,515 @@
+package party
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	partyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
+	"ecosystem.garyle/service/pkg/utils/fieldcrypt"
+)
+
+const addressColumns = `id, type, label, street, city, province, postal_code, country, latitude, longitude, is_default, created_at, updated_at, deleted_at`
+
+const contactColumns = `id, name, role, phone, email, is_primary, created_at, updated_at, deleted_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAddress(row rowScanner, address *partyModel.Address) error {
+	return row.Scan(
+		&address.ID,
+		&address.Type,
+		&address.Label,
+		&address.Street,
+		&address.City,
+		&address.Province,
+		&address.PostalCode,
+		&address.Country,
+		&address.Latitude,
+		&address.Longitude,
+		&address.IsDefault,
+		&address.CreatedAt,
+		&address.UpdatedAt,
+		&address.DeletedAt,
+	)
+}
+
+func scanContact(row rowScanner, contact *partyModel.Contact) error {
+	return row.Scan(
+		&contact.ID,
+		&contact.Name,
+		&contact.Role,
+		&contact.Phone,
+		&contact.Email,
+		&contact.IsPrimary,
+		&contact.CreatedAt,
+		&contact.UpdatedAt,
+		&contact.DeletedAt,
+	)
+}
+
+// DetailRepository stores the addresses and contacts of one kind of party in
+// the <owner>_addresses and <owner>_contacts tables. It is embedded by the
+// supplier and customer repositories.
+type DetailRepository struct {
+	db           *sql.DB
+	owner        string
+	partyTable   string
+	addressTable string
+	contactTable string
+	ownerColumn  string
+	// keyring encrypts the personal data columns, see AddressPersonalColumns
+	// and ContactPersonalColumns. Nil stores them in plain text.
+	keyring *fieldcrypt.Keyring
+}
+
+// AddressPersonalColumns and ContactPersonalColumns are the detail columns
+// that are encrypted when the repository has a keyring
+var (
+	AddressPersonalColumns = []string{"street", "postal_code"}
+	ContactPersonalColumns = []string{"name", "phone", "email"}
+)
+
+// NewDetailRepository creates the detail repository of an owner, e.g. "supplier".
+// The keyring may be nil.
+func NewDetailRepository(db *sql.DB, owner string, keyring *fieldcrypt.Keyring) *DetailRepository {
+	return &DetailRepository{
+		db:           db,
+		owner:        owner,
+		partyTable:   owner + "s",
+		addressTable: owner + "_addresses",
+		contactTable: owner + "_contacts",
+		ownerColumn:  owner + "_id",
+		keyring:      keyring,
+	}
+}
+
+// Keyring returns the keyring of the personal data columns, nil when they are not encrypted
+func (r *DetailRepository) Keyring() *fieldcrypt.Keyring {
+	return r.keyring
+}
+
+// Seal encrypts values for a write, in the order given
+func (r *DetailRepository) Seal(values ...string) ([]string, error) {
+	sealed := make([]string, len(values))
+	for i, value := range values {
+		encrypted, err := r.keyring.Encrypt(value)
+		if err != nil {
+			return nil, err
+		}
+		sealed[i] = encrypted
+	}
+
+	return sealed, nil
+}
+
+// Open decrypts scanned values in place
+func (r *DetailRepository) Open(values ...*string) error {
+	for _, value := range values {
+		plain, err := r.keyring.Decrypt(*value)
+		if err != nil {
+			return err
+		}
+		*value = plain
+	}
+
+	return nil
+}
+
+func (r *DetailRepository) scanAddress(row rowScanner, address *partyModel.Address) error {
+	if err := scanAddress(row, address); err != nil {
+		return err
+	}
+
+	return r.Open(&address.Street, &address.PostalCode)
+}
+
+func (r *DetailRepository) scanContact(row rowScanner, contact *partyModel.Contact) error {
+	if err := scanContact(row, contact); err != nil {
+		return err
+	}
+
+	return r.Open(&contact.Name, &contact.Phone, &contact.Email)
+}
+
+// ListAddresses implements party.DetailRepository.
+func (r *DetailRepository) ListAddresses(ctx context.Context, ownerID int) ([]*partyModel.Address, error) {
+	query := `
+		SELECT ` + addressColumns + `
+		FROM ` + r.addressTable + `
+		WHERE ` + r.ownerColumn + ` = $1 AND deleted_at IS NULL
+		ORDER BY type, is_default DESC, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	addresses := []*partyModel.Address{}
+	for rows.Next() {
+		var address partyModel.Address
+		if err := r.scanAddress(rows, &address); err != nil {
+			return nil, err
+		}
+
+		addresses = append(addresses, &address)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return addresses, nil
+}
+
+// GetAddress implements party.DetailRepository.
+func (r *DetailRepository) GetAddress(ctx context.Context, ownerID, id int) (*partyModel.Address, error) {
+	query := `
+		SELECT ` + addressColumns + `
+		FROM ` + r.addressTable + `
+		WHERE id = $1 AND ` + r.ownerColumn + ` = $2 AND deleted_at IS NULL
+	`
+
+	var address partyModel.Address
+	if err := r.scanAddress(r.db.QueryRowContext(ctx, query, id, ownerID), &address); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &address, nil
+}
+
+// CreateAddress implements party.DetailRepository.
+func (r *DetailRepository) CreateAddress(ctx context.Context, ownerID int, address *partyModel.Address) (*partyModel.Address, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := r.insertAddress(ctx, tx, ownerID, address, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+// UpdateAddress implements party.DetailRepository. It returns nil when the
+// address does not exist.
+func (r *DetailRepository) UpdateAddress(ctx context.Context, ownerID, id int, address *partyModel.Address) (*partyModel.Address, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	sealed, err := r.Seal(address.Street, address.PostalCode)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if address.IsDefault {
+		if err := r.clearDefaultAddress(ctx, tx, ownerID, address.Type, now); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		UPDATE ` + r.addressTable + `
+		SET type = $1, label = $2, street = $3, city = $4, province = $5, postal_code = $6, country = $7,
+			latitude = $8, longitude = $9, is_default = $10, updated_at = $11
+		WHERE id = $12 AND ` + r.ownerColumn + ` = $13 AND deleted_at IS NULL
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		address.Type,
+		address.Label,
+		sealed[0],
+		address.City,
+		address.Province,
+		sealed[1],
+		address.Country,
+		address.Latitude,
+		address.Longitude,
+		address.IsDefault,
+		now,
+		id,
+		ownerID,
+	).Scan(&address.ID, &address.CreatedAt, &address.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+// DeleteAddress implements party.DetailRepository.
+func (r *DetailRepository) DeleteAddress(ctx context.Context, ownerID, id int) error {
+	query := `
+		UPDATE ` + r.addressTable + `
+		SET deleted_at = $1, updated_at = $1, is_default = FALSE
+		WHERE id = $2 AND ` + r.ownerColumn + ` = $3 AND deleted_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, time.Now(), id, ownerID)
+	return err
+}
+
+// ListContacts implements party.DetailRepository.
+func (r *DetailRepository) ListContacts(ctx context.Context, ownerID int) ([]*partyModel.Contact, error) {
+	query := `
+		SELECT ` + contactColumns + `
+		FROM ` + r.contactTable + `
+		WHERE ` + r.ownerColumn + ` = $1 AND deleted_at IS NULL
+		ORDER BY is_primary DESC, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	contacts := []*partyModel.Contact{}
+	for rows.Next() {
+		var contact partyModel.Contact
+		if err := r.scanContact(rows, &contact); err != nil {
+			return nil, err
+		}
+
+		contacts = append(contacts, &contact)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
+// GetContact implements party.DetailRepository.
+func (r *DetailRepository) GetContact(ctx context.Context, ownerID, id int) (*partyModel.Contact, error) {
+	query := `
+		SELECT ` + contactColumns + `
+		FROM ` + r.contactTable + `
+		WHERE id = $1 AND ` + r.ownerColumn + ` = $2 AND deleted_at IS NULL
+	`
+
+	var contact partyModel.Contact
+	if err := r.scanContact(r.db.QueryRowContext(ctx, query, id, ownerID), &contact); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &contact, nil
+}
+
+// CreateContact implements party.DetailRepository.
+func (r *DetailRepository) CreateContact(ctx context.Context, ownerID int, contact *partyModel.Contact) (*partyModel.Contact, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := r.insertContact(ctx, tx, ownerID, contact, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return contact, nil
+}
+
+// UpdateContact implements party.DetailRepository. It returns nil when the
+// contact does not exist.
+func (r *DetailRepository) UpdateContact(ctx context.Context, ownerID, id int, contact *partyModel.Contact) (*partyModel.Contact, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	sealed, err := r.Seal(contact.Name, contact.Phone, contact.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if contact.IsPrimary {
+		if err := r.clearPrimaryContact(ctx, tx, ownerID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		UPDATE ` + r.contactTable + `
+		SET name = $1, role = $2, phone = $3, email = $4, is_primary = $5, updated_at = $6
+		WHERE id = $7 AND ` + r.ownerColumn + ` = $8 AND deleted_at IS NULL
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query, sealed[0], contact.Role, sealed[1], sealed[2], contact.IsPrimary, now, id, ownerID).
+		Scan(&contact.ID, &contact.CreatedAt, &contact.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return contact, nil
+}
+
+// DeleteContact implements party.DetailRepository.
+func (r *DetailRepository) DeleteContact(ctx context.Context, ownerID, id int) error {
+	query := `
+		UPDATE ` + r.contactTable + `
+		SET deleted_at = $1, updated_at = $1, is_primary = FALSE
+		WHERE id = $2 AND ` + r.ownerColumn + ` = $3 AND deleted_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, time.Now(), id, ownerID)
+	return err
+}
+
+// SaveDetails inserts the addresses and contacts of a new party inside the
+// transaction that creates the party
+func (r *DetailRepository) SaveDetails(ctx context.Context, tx *sql.Tx, ownerID int, addresses []*partyModel.Address, contacts []*partyModel.Contact) error {
+	now := time.Now()
+	for _, address := range addresses {
+		if err := r.insertAddress(ctx, tx, ownerID, address, now); err != nil {
+			return err
+		}
+	}
+
+	for _, contact := range contacts {
+		if err := r.insertContact(ctx, tx, ownerID, contact, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertAddress inserts an address, a new default address replaces the
+// current default of the same type
+func (r *DetailRepository) insertAddress(ctx context.Context, tx *sql.Tx, ownerID int, address *partyModel.Address, now time.Time) error {
+	sealed, err := r.Seal(address.Street, address.PostalCode)
+	if err != nil {
+		return err
+	}
+
+	if address.IsDefault {
+		if err := r.clearDefaultAddress(ctx, tx, ownerID, address.Type, now); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		INSERT INTO ` + r.addressTable + ` (` + r.ownerColumn + `, type, label, street, city, province, postal_code, country,
+			latitude, longitude, is_default, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id
+	`
+
+	address.CreatedAt = now
+	address.UpdatedAt = now
+
+	return tx.QueryRowContext(
+		ctx,
+		query,
+		ownerID,
+		address.Type,
+		address.Label,
+		sealed[0],
+		address.City,
+		address.Province,
+		sealed[1],
+		address.Country,
+		address.Latitude,
+		address.Longitude,
+		address.IsDefault,
+		address.CreatedAt,
+		address.UpdatedAt,
+	).Scan(&address.ID)
+}
+
+// insertContact inserts a contact, a new primary contact replaces the current one
+func (r *DetailRepository) insertContact(ctx context.Context, tx *sql.Tx, ownerID int, contact *partyModel.Contact, now time.Time) error {
+	sealed, err := r.Seal(contact.Name, contact.Phone, contact.Email)
+	if err != nil {
+		return err
+	}
+
+	if contact.IsPrimary {
+		if err := r.clearPrimaryContact(ctx, tx, ownerID, now); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		INSERT INTO ` + r.contactTable + ` (` + r.ownerColumn + `, name, role, phone, email, is_primary, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	contact.CreatedAt = now
+	contact.UpdatedAt = now
+
+	return tx.QueryRowContext(ctx, query, ownerID, sealed[0], contact.Role, sealed[1], sealed[2], contact.IsPrimary, contact.CreatedAt, contact.UpdatedAt).
+		Scan(&contact.ID)
+}
+
+func (r *DetailRepository) clearDefaultAddress(ctx context.Context, tx *sql.Tx, ownerID int, addressType partyModel.AddressType, now time.Time) error {
+	query := `
+		UPDATE ` + r.addressTable + `
+		SET is_default = FALSE, updated_at = $1
+		WHERE ` + r.ownerColumn + ` = $2 AND type = $3 AND is_default AND deleted_at IS NULL
+	`
+
+	_, err := tx.ExecContext(ctx, query, now, ownerID, addressType)
+	return err
+}
+
+func (r *DetailRepository) clearPrimaryContact(ctx context.Context, tx *sql.Tx, ownerID int, now time.Time) error {
+	query := `
+		UPDATE ` + r.contactTable + `
+		SET is_primary = FALSE, updated_at = $1
+		WHERE ` + r.ownerColumn + ` = $2 AND is_primary AND deleted_at IS NULL
+	`
+
+	_, err := tx.ExecContext(ctx, query, now, ownerID)
+	return err
+}
@@ -0,0 +1,39 @@
+package party
+
+import (
+	"context"
+
+	partyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
+)
+
+// RegistrationColumns are the columns of partyModel.Registration, in the
+// order of RegistrationFields
+const RegistrationColumns = `tax_country, tax_id, registration_number, payment_terms, currency`
+
+// RegistrationFields returns the scan destinations of RegistrationColumns
+func RegistrationFields(registration *partyModel.Registration) []interface{} {
+	return []interface{}{
+		&registration.TaxCountry,
+		&registration.TaxID,
+		&registration.RegistrationNumber,
+		&registration.PaymentTerms,
+		&registration.Currency,
+	}
+}
+
+// TaxIDTaken implements party.RegistrationRepository.
+func (r *DetailRepository) TaxIDTaken(ctx context.Context, country, taxID string, excludeID int) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM ` + r.partyTable + `
+			WHERE tax_country = $1 AND tax_id = $2 AND id <> $3 AND deleted_at IS NULL
+		)
+	`
+
+	var taken bool
+	if err := r.db.QueryRowContext(ctx, query, country, taxID, excludeID).Scan(&taken); err != nil {
+		return false, err
+	}
+
+	return taken, nil
+}
@@ -3,65 +3,129 @@ package product
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
 	productModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/product"
 	productRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/product"
+	"ecosystem.garyle/service/internal/infrastructure/database"
+	"ecosystem.garyle/service/pkg/utils/filter"
+	"github.com/lib/pq"
 )
 
-type productRepository struct {
-	db *sql.DB
+// productColumns is the select list used by every product read, a variant
+// gets the description, category and attributes of its parent when it has none
+var productColumns = `id, parent_id, ` + productModel.CategoryIDColumn + `, sku, name, ` + productModel.DescriptionColumn + `,
+	unit, weight, dimension, is_batch_tracked, min_temperature, max_temperature, hazmat_class, un_number, is_stackable, is_fragile, ` + productModel.AttributesColumn + `,
+	variant_attributes, variant_values, created_at, updated_at, deleted_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
 }
 
-func NewProductRepository(db *sql.DB) productRepo.ProductRepository {
-	return &productRepository{db: db}
+func scanProduct(row rowScanner, product *productModel.Product) error {
+	return row.Scan(
+		&product.ID,
+		&product.ParentID,
+		&product.CategoryID,
+		&product.Sku,
+		&product.Name,
+		&product.Description,
+		&product.Unit,
+		&product.Weight,
+		&product.Dimension,
+		&product.IsBatchTracked,
+		&product.Storage.MinTemperature,
+		&product.Storage.MaxTemperature,
+		&product.Storage.HazmatClass,
+		&product.Storage.UNNumber,
+		&product.Storage.IsStackable,
+		&product.Storage.IsFragile,
+		&product.Attributes,
+		&product.VariantAttributes,
+		&product.VariantValues,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+		&product.DeletedAt,
+	)
 }
 
-// endpoint create product
-func (r *productRepository) Create(ctx context.Context, product *productModel.Product) (*productModel.Product, error) {
-	// query insert product
+// queryRower runs a single-row query on the database or in a transaction
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// insertProduct inserts a product with all of its columns
+func insertProduct(ctx context.Context, q queryRower, product *productModel.Product) error {
 	query := `
-		INSERT INTO products (sku, name, description, unit, weight, dimension, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO products (parent_id, category_id, sku, name, description, unit, weight, dimension, is_batch_tracked,
+			min_temperature, max_temperature, hazmat_class, un_number, is_stackable, is_fragile, attributes, variant_attributes, variant_values, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 		RETURNING id
 	`
 
-	now := time.Now()
-	product.CreatedAt = now
-	product.UpdatedAt = now
-
-	// execute query
-	err := r.db.QueryRowContext(
+	return q.QueryRowContext(
 		ctx,
 		query,
+		product.ParentID,
+		product.CategoryID,
 		product.Sku,
 		product.Name,
 		product.Description,
 		product.Unit,
 		product.Weight,
 		product.Dimension,
+		product.IsBatchTracked,
+		product.Storage.MinTemperature,
+		product.Storage.MaxTemperature,
+		product.Storage.HazmatClass,
+		product.Storage.UNNumber,
+		product.Storage.Stackable(),
+		product.Storage.IsFragile,
+		product.Attributes,
+		product.VariantAttributes,
+		product.VariantValues,
 		product.CreatedAt,
 		product.UpdatedAt,
 	).Scan(&product.ID)
+}
 
-	if err != nil {
-		return nil, err
+type productRepository struct {
+	db *sql.DB
+}
+
+func NewProductRepository(db *sql.DB) productRepo.ProductRepository {
+	return &productRepository{db: db}
+}
+
+// endpoint create product
+func (r *productRepository) Create(ctx context.Context, product *productModel.Product) (*productModel.Product, error) {
+	now := time.Now()
+	product.CreatedAt = now
+	product.UpdatedAt = now
+
+	// execute query
+	if err := insertProduct(ctx, r.db, product); err != nil {
+		return nil, database.Conflict(err)
 	}
 
 	return product, nil
 }
 
-// count total products
-func (r *productRepository) Count(ctx context.Context) (int, error) {
+// count total products matching the list filters
+func (r *productRepository) Count(ctx context.Context, params *filter.Params) (int, error) {
+	where, args := params.Where(1)
+
 	// query get total products
 	query := `
 		SELECT COUNT(*) FROM products
 		WHERE deleted_at IS NULL
-	`
+	` + where
 
 	// execute query
 	var total int
-	err := r.db.QueryRowContext(ctx, query).Scan(&total)
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&total)
 	if err != nil {
 		return 0, err
 	}
@@ -70,16 +134,19 @@ func (r *productRepository) Count(ctx context.Context) (int, error) {
 }
 
 // get all products
-func (r *productRepository) List(ctx context.Context, limit, page int) ([]*productModel.Product, error) {
+func (r *productRepository) List(ctx context.Context, limit, page int, params *filter.Params) ([]*productModel.Product, error) {
+	where, args := params.Where(1)
+
 	// query get all products
 	query := `
-		SELECT * FROM products
+		SELECT ` + productColumns + `
+		FROM products
 		WHERE deleted_at IS NULL
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
+	` + where + params.OrderBy(productModel.QuerySpec.DefaultSort...) + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+
 	// execute query
-	rows, err := r.db.QueryContext(ctx, query, limit, (page-1)*limit)
+	args = append(args, limit, (page-1)*limit)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +158,7 @@ func (r *productRepository) List(ctx context.Context, limit, page int) ([]*produ
 	products := []*productModel.Product{}
 	for rows.Next() {
 		var product productModel.Product
-		err := rows.Scan(&product.ID, &product.Sku, &product.Name, &product.Description, &product.Unit, &product.Weight, &product.Dimension, &product.CreatedAt, &product.UpdatedAt, &product.DeletedAt)
+		err := scanProduct(rows, &product)
 		if err != nil {
 			return nil, err
 		}
@@ -115,13 +182,14 @@ func (r *productRepository) List(ctx context.Context, limit, page int) ([]*produ
 func (r *productRepository) GetByID(ctx context.Context, id int) (*productModel.Product, error) {
 	// query get product by id
 	query := `
-		SELECT * FROM products
+		SELECT ` + productColumns + `
+		FROM products
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	// execute query
 	var product productModel.Product
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&product.ID, &product.Sku, &product.Name, &product.Description, &product.Unit, &product.Weight, &product.Dimension, &product.CreatedAt, &product.UpdatedAt, &product.DeletedAt)
+	err := scanProduct(r.db.QueryRowContext(ctx, query, id), &product)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -132,22 +200,70 @@ func (r *productRepository) GetByID(ctx context.Context, id int) (*productModel.
 	return &product, nil
 }
 
-// update product by id
+// update product by id. Reads give a variant the description, category and
+// attributes of its parent, so a value equal to the inherited one is not
+// stored on the variant and it keeps following its parent.
 func (r *productRepository) UpdateByID(ctx context.Context, product *productModel.Product, id int) error {
 	// query update product by id
 	query := `
 		UPDATE products
-		SET sku = $1, name = $2, description = $3, unit = $4, weight = $5, dimension = $6, updated_at = $7
-		WHERE id = $8
+		SET category_id = CASE WHEN $1::int IS NOT DISTINCT FROM ` + parentValue("category_id") + ` THEN NULL ELSE $1::int END,
+			sku = $2, name = $3,
+			description = CASE WHEN $4::text = COALESCE(` + parentValue("description") + `, '') THEN '' ELSE $4::text END,
+			unit = $5, weight = $6, dimension = $7, is_batch_tracked = $8,
+			min_temperature = $9, max_temperature = $10, hazmat_class = $11, un_number = $12, is_stackable = $13, is_fragile = $14,
+			attributes = CASE
+				WHEN $15::jsonb IS NULL THEN attributes
+				WHEN parent_id IS NULL THEN $15::jsonb
+				ELSE (
+					SELECT COALESCE(jsonb_object_agg(own.key, own.value), '{}'::jsonb)
+					FROM jsonb_each($15::jsonb) own
+					WHERE own.value IS DISTINCT FROM ` + parentValue("attributes->own.key") + `
+				)
+			END,
+			updated_at = $16
+		WHERE id = $17
 	`
 
-	// execute query
-	_, err := r.db.ExecContext(ctx, query, product.Sku, product.Name, product.Description, product.Unit, product.Weight, product.Dimension, time.Now(), id)
+	tx, err := database.BeginAuthored(ctx, r.db)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	return nil
+	// execute query
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		product.CategoryID,
+		product.Sku,
+		product.Name,
+		product.Description,
+		product.Unit,
+		product.Weight,
+		product.Dimension,
+		product.IsBatchTracked,
+		product.Storage.MinTemperature,
+		product.Storage.MaxTemperature,
+		product.Storage.HazmatClass,
+		product.Storage.UNNumber,
+		product.Storage.Stackable(),
+		product.Storage.IsFragile,
+		product.Attributes,
+		time.Now(),
+		id,
+	)
+	if err != nil {
+		return database.Conflict(err)
+	}
+
+	return tx.Commit()
+}
+
+// parentValue selects a column of the parent of the updated product, NULL
+// when it has no parent
+func parentValue(column string) string {
+	return `(SELECT pp.` + column + ` FROM products pp WHERE pp.id = products.parent_id)`
 }
 
 // delete product by id
@@ -160,10 +276,123 @@ func (r *productRepository) DeleteByID(ctx context.Context, id int) error {
 	`
 
 	// execute query
-	_, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	_, err := database.ExecAuthored(ctx, r.db, query, time.Now(), id)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// CreateBatch inserts products in one transaction with the columns of
+// Create, see database.InsertBatch
+func (r *productRepository) CreateBatch(ctx context.Context, products []*productModel.Product, opts imports.Options, report func(index int, err error)) error {
+	now := time.Now()
+	return database.InsertBatch(ctx, r.db, len(products), opts, func(tx *sql.Tx, index int) error {
+		item := products[index]
+		item.CreatedAt = now
+		item.UpdatedAt = now
+
+		return insertProduct(ctx, tx, item)
+	}, report)
+}
+
+// Export streams the products matching the list filters to fn one row at a time
+func (r *productRepository) Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(product *productModel.Product) error) error {
+	where, args := params.Where(1)
+
+	scope := "deleted_at IS NULL"
+	if includeDeleted {
+		scope = "TRUE"
+	}
+
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE ` + scope + where + params.OrderBy(productModel.QuerySpec.DefaultSort...)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var item productModel.Product
+		if err := scanProduct(rows, &item); err != nil {
+			return err
+		}
+
+		if err := fn(&item); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// ListVariants returns the variants of the given parent products
+func (r *productRepository) ListVariants(ctx context.Context, parentIDs []int) ([]*productModel.Product, error) {
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE parent_id = ANY($1) AND deleted_at IS NULL
+		ORDER BY parent_id, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(parentIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	variants := []*productModel.Product{}
+	for rows.Next() {
+		var variant productModel.Product
+		if err := scanProduct(rows, &variant); err != nil {
+			return nil, err
+		}
+
+		variants = append(variants, &variant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+// SaveVariants stores the attribute definitions of a parent product and
+// inserts its new variants in the same transaction, so the definitions
+// always match the variants
+func (r *productRepository) SaveVariants(ctx context.Context, parentID int, attributes productModel.VariantAttributes, variants []*productModel.Product) error {
+	tx, err := database.BeginAuthored(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, variant := range variants {
+		variant.CreatedAt = now
+		variant.UpdatedAt = now
+		if err := insertProduct(ctx, tx, variant); err != nil {
+			return database.Conflict(err)
+		}
+	}
+
+	query := `
+		UPDATE products
+		SET variant_attributes = $1, updated_at = $2
+		WHERE id = $3 AND deleted_at IS NULL
+	`
+
+	if _, err := tx.ExecContext(ctx, query, attributes, now, parentID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,258 @@
+package sourcing
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	sourcingModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/sourcing"
+	sourcingRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/sourcing"
+	"ecosystem.garyle/service/internal/infrastructure/database"
+	"ecosystem.garyle/service/pkg/utils/filter"
+)
+
+// sourcingColumns also selects the product and supplier names for display
+const sourcingColumns = `id, product_id, supplier_id, supplier_item_code, unit_cost, currency, min_order_qty, lead_time_days, is_preferred,
+	(SELECT p.sku FROM products p WHERE p.id = product_suppliers.product_id),
+	(SELECT p.name FROM products p WHERE p.id = product_suppliers.product_id),
+	(SELECT s.name FROM suppliers s WHERE s.id = product_suppliers.supplier_id),
+	created_at, updated_at, deleted_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanProductSupplier(row rowScanner, productSupplier *sourcingModel.ProductSupplier) error {
+	return row.Scan(
+		&productSupplier.ID,
+		&productSupplier.ProductID,
+		&productSupplier.SupplierID,
+		&productSupplier.SupplierItemCode,
+		&productSupplier.UnitCost,
+		&productSupplier.Currency,
+		&productSupplier.MinOrderQty,
+		&productSupplier.LeadTimeDays,
+		&productSupplier.IsPreferred,
+		&productSupplier.ProductSku,
+		&productSupplier.ProductName,
+		&productSupplier.SupplierName,
+		&productSupplier.CreatedAt,
+		&productSupplier.UpdatedAt,
+		&productSupplier.DeletedAt,
+	)
+}
+
+type sourcingRepository struct {
+	db *sql.DB
+}
+
+func NewSourcingRepository(db *sql.DB) sourcingRepo.SourcingRepository {
+	return &sourcingRepository{db: db}
+}
+
+// Create implements sourcing.SourcingRepository.
+func (s *sourcingRepository) Create(ctx context.Context, productSupplier *sourcingModel.ProductSupplier) (*sourcingModel.ProductSupplier, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if productSupplier.IsPreferred {
+		if err := clearPreferred(ctx, tx, productSupplier.ProductID, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		INSERT INTO product_suppliers (product_id, supplier_id, supplier_item_code, unit_cost, currency, min_order_qty, lead_time_days, is_preferred, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+
+	now := time.Now()
+	productSupplier.CreatedAt = now
+	productSupplier.UpdatedAt = now
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		productSupplier.ProductID,
+		productSupplier.SupplierID,
+		productSupplier.SupplierItemCode,
+		productSupplier.UnitCost,
+		productSupplier.Currency,
+		productSupplier.MinOrderQty,
+		productSupplier.LeadTimeDays,
+		productSupplier.IsPreferred,
+		productSupplier.CreatedAt,
+		productSupplier.UpdatedAt,
+	).Scan(&productSupplier.ID)
+	if err != nil {
+		return nil, database.Conflict(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return productSupplier, nil
+}
+
+// clearPreferred removes the preferred flag from the other suppliers of a product
+func clearPreferred(ctx context.Context, tx *sql.Tx, productID, exceptID int) error {
+	query := `
+		UPDATE product_suppliers
+		SET is_preferred = FALSE, updated_at = $1
+		WHERE product_id = $2 AND id <> $3 AND is_preferred AND deleted_at IS NULL
+	`
+
+	_, err := tx.ExecContext(ctx, query, time.Now(), productID, exceptID)
+	return err
+}
+
+// GetByID implements sourcing.SourcingRepository.
+func (s *sourcingRepository) GetByID(ctx context.Context, id int) (*sourcingModel.ProductSupplier, error) {
+	query := `
+		SELECT ` + sourcingColumns + `
+		FROM product_suppliers
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	var productSupplier sourcingModel.ProductSupplier
+	err := scanProductSupplier(s.db.QueryRowContext(ctx, query, id), &productSupplier)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &productSupplier, nil
+}
+
+// ListByProduct implements sourcing.SourcingRepository.
+func (s *sourcingRepository) ListByProduct(ctx context.Context, productID, limit, page int, params *filter.Params) ([]*sourcingModel.ProductSupplier, error) {
+	return s.list(ctx, "product_id", productID, limit, page, params)
+}
+
+// CountByProduct implements sourcing.SourcingRepository.
+func (s *sourcingRepository) CountByProduct(ctx context.Context, productID int, params *filter.Params) (int, error) {
+	return s.count(ctx, "product_id", productID, params)
+}
+
+// ListBySupplier implements sourcing.SourcingRepository.
+func (s *sourcingRepository) ListBySupplier(ctx context.Context, supplierID, limit, page int, params *filter.Params) ([]*sourcingModel.ProductSupplier, error) {
+	return s.list(ctx, "supplier_id", supplierID, limit, page, params)
+}
+
+// CountBySupplier implements sourcing.SourcingRepository.
+func (s *sourcingRepository) CountBySupplier(ctx context.Context, supplierID int, params *filter.Params) (int, error) {
+	return s.count(ctx, "supplier_id", supplierID, params)
+}
+
+// list returns the rows where column equals ownerID, column is product_id or supplier_id
+func (s *sourcingRepository) list(ctx context.Context, column string, ownerID, limit, page int, params *filter.Params) ([]*sourcingModel.ProductSupplier, error) {
+	where, args := params.Where(2)
+
+	query := `
+		SELECT ` + sourcingColumns + `
+		FROM product_suppliers
+		WHERE ` + column + ` = $1 AND deleted_at IS NULL
+	` + where + params.OrderBy(sourcingModel.QuerySpec.DefaultSort...) + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+2, len(args)+3)
+
+	args = append([]interface{}{ownerID}, args...)
+	args = append(args, limit, (page-1)*limit)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	productSuppliers := []*sourcingModel.ProductSupplier{}
+	for rows.Next() {
+		var productSupplier sourcingModel.ProductSupplier
+		if err := scanProductSupplier(rows, &productSupplier); err != nil {
+			return nil, err
+		}
+
+		productSuppliers = append(productSuppliers, &productSupplier)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return productSuppliers, nil
+}
+
+func (s *sourcingRepository) count(ctx context.Context, column string, ownerID int, params *filter.Params) (int, error) {
+	where, args := params.Where(2)
+
+	query := `
+		SELECT COUNT(*) FROM product_suppliers
+		WHERE ` + column + ` = $1 AND deleted_at IS NULL
+	` + where
+
+	var total int
+	err := s.db.QueryRowContext(ctx, query, append([]interface{}{ownerID}, args...)...).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// UpdateByID implements sourcing.SourcingRepository. The product and supplier
+// of a sourcing record cannot change, only its terms.
+func (s *sourcingRepository) UpdateByID(ctx context.Context, productSupplier *sourcingModel.ProductSupplier, id int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if productSupplier.IsPreferred {
+		if err := clearPreferred(ctx, tx, productSupplier.ProductID, id); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		UPDATE product_suppliers
+		SET supplier_item_code = $1, unit_cost = $2, currency = $3, min_order_qty = $4, lead_time_days = $5, is_preferred = $6, updated_at = $7
+		WHERE id = $8 AND deleted_at IS NULL
+	`
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		productSupplier.SupplierItemCode,
+		productSupplier.UnitCost,
+		productSupplier.Currency,
+		productSupplier.MinOrderQty,
+		productSupplier.LeadTimeDays,
+		productSupplier.IsPreferred,
+		time.Now(),
+		id,
+	)
+	if err != nil {
+		return database.Conflict(err)
+	}
+
+	return tx.Commit()
+}
+
+// DeleteByID implements sourcing.SourcingRepository.
+func (s *sourcingRepository) DeleteByID(ctx context.Context, id int) error {
+	query := `
+		UPDATE product_suppliers
+		SET deleted_at = $1
+		WHERE id = $2 AND deleted_at IS NULL
+	`
+
+	_, err := s.db.ExecContext(ctx, query, time.Now(), id)
+	return err
+}
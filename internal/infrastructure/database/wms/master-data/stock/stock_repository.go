@@ -0,0 +1,263 @@
+package stock
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	locationModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/location"
+	productModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/product"
+	stockModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/stock"
+	stockRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/stock"
+	locationRepo "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/location"
+)
+
+const stockColumns = `id, location_id, product_id, batch_id, quantity, created_at, updated_at`
+
+// utilizationQuery sums the weight, volume and units of the stock of every
+// location, the limits of a location are compared against these sums
+const utilizationQuery = `
+	SELECT locations.id, locations.code, locations.max_weight, locations.max_volume, locations.max_units,
+		COALESCE(SUM(location_stock.quantity * products.weight), 0) AS used_weight,
+		COALESCE(SUM(location_stock.quantity * ` + productModel.UnitVolumeColumn + `), 0) AS used_volume,
+		COALESCE(SUM(location_stock.quantity), 0) AS used_units
+	FROM locations
+	LEFT JOIN location_stock ON location_stock.location_id = locations.id
+	LEFT JOIN products ON products.id = location_stock.product_id
+	WHERE locations.deleted_at IS NULL
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUtilization(row rowScanner, utilization *locationModel.Utilization) error {
+	err := row.Scan(
+		&utilization.LocationID,
+		&utilization.Code,
+		&utilization.MaxWeight,
+		&utilization.MaxVolume,
+		&utilization.MaxUnits,
+		&utilization.UsedWeight,
+		&utilization.UsedVolume,
+		&utilization.UsedUnits,
+	)
+	utilization.Calculate()
+
+	return err
+}
+
+type stockRepository struct {
+	db *sql.DB
+}
+
+func NewStockRepository(db *sql.DB) stockRepo.StockRepository {
+	return &stockRepository{db: db}
+}
+
+// ListByLocation implements stock.StockRepository.
+func (s *stockRepository) ListByLocation(ctx context.Context, locationID int) ([]*stockModel.Stock, error) {
+	query := `
+		SELECT location_stock.id, location_stock.location_id, location_stock.product_id, location_stock.batch_id,
+			location_stock.quantity, location_stock.created_at, location_stock.updated_at, products.sku, products.name
+		FROM location_stock
+		JOIN products ON products.id = location_stock.product_id
+		WHERE location_stock.location_id = $1 AND location_stock.quantity > 0
+		ORDER BY products.sku, location_stock.batch_id
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	stocks := []*stockModel.Stock{}
+	for rows.Next() {
+		var stock stockModel.Stock
+		if err := rows.Scan(&stock.ID, &stock.LocationID, &stock.ProductID, &stock.BatchID, &stock.Quantity, &stock.CreatedAt, &stock.UpdatedAt, &stock.ProductSku, &stock.ProductName); err != nil {
+			return nil, err
+		}
+
+		stocks = append(stocks, &stock)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stocks, nil
+}
+
+// Put implements stock.StockRepository. The quantity is added to the stock
+// already stored for the product and batch. The location row stays locked
+// until the stock is stored, so concurrent puts into the same location are
+// checked one after the other against the utilization they leave behind.
+func (s *stockRepository) Put(ctx context.Context, locationID int, movement *stockModel.Movement, check stockRepo.PutCheck) (*stockModel.Stock, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	location, err := locationRepo.LockByID(ctx, tx, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if location == nil {
+		return nil, nil
+	}
+
+	var utilization locationModel.Utilization
+	if err := scanUtilization(tx.QueryRowContext(ctx, utilizationQuery+` AND locations.id = $1 GROUP BY locations.id`, locationID), &utilization); err != nil {
+		return nil, err
+	}
+
+	if err := check(location, &utilization); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO location_stock (location_id, product_id, batch_id, quantity, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (location_id, product_id, (COALESCE(batch_id, 0)))
+		DO UPDATE SET quantity = location_stock.quantity + EXCLUDED.quantity, updated_at = EXCLUDED.updated_at
+		RETURNING ` + stockColumns + `
+	`
+
+	var stock stockModel.Stock
+	err = tx.QueryRowContext(ctx, query, locationID, movement.ProductID, movement.BatchID, movement.Quantity, time.Now()).
+		Scan(&stock.ID, &stock.LocationID, &stock.ProductID, &stock.BatchID, &stock.Quantity, &stock.CreatedAt, &stock.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &stock, nil
+}
+
+// Take implements stock.StockRepository. It returns nil when the location
+// does not hold enough of the product and batch. The location row stays
+// locked until the stock is taken, so a status change waits for the take.
+func (s *stockRepository) Take(ctx context.Context, locationID int, movement *stockModel.Movement, check stockRepo.TakeCheck) (*stockModel.Stock, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	location, err := locationRepo.LockByID(ctx, tx, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := check(location); err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE location_stock
+		SET quantity = quantity - $4, updated_at = $5
+		WHERE location_id = $1 AND product_id = $2 AND COALESCE(batch_id, 0) = COALESCE($3, 0) AND quantity >= $4
+		RETURNING ` + stockColumns + `
+	`
+
+	var stock stockModel.Stock
+	err = tx.QueryRowContext(ctx, query, locationID, movement.ProductID, movement.BatchID, movement.Quantity, time.Now()).
+		Scan(&stock.ID, &stock.LocationID, &stock.ProductID, &stock.BatchID, &stock.Quantity, &stock.CreatedAt, &stock.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &stock, nil
+}
+
+// GetUtilization implements stock.StockRepository.
+func (s *stockRepository) GetUtilization(ctx context.Context, locationID int) (*locationModel.Utilization, error) {
+	query := utilizationQuery + ` AND locations.id = $1 GROUP BY locations.id`
+
+	var utilization locationModel.Utilization
+	if err := scanUtilization(s.db.QueryRowContext(ctx, query, locationID), &utilization); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &utilization, nil
+}
+
+// ListUtilization implements stock.StockRepository.
+func (s *stockRepository) ListUtilization(ctx context.Context, limit, page int) ([]*locationModel.Utilization, error) {
+	query := utilizationQuery + ` GROUP BY locations.id ORDER BY locations.code LIMIT $1 OFFSET $2`
+
+	return s.queryUtilization(ctx, query, limit, (page-1)*limit)
+}
+
+// CountLocations implements stock.StockRepository.
+func (s *stockRepository) CountLocations(ctx context.Context) (int, error) {
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM locations WHERE deleted_at IS NULL`).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// ListWithRoom implements stock.StockRepository. Only locations that accept
+// inbound stock, have no child locations and have at least one limit are
+// considered, the fullest locations that still fit come first.
+func (s *stockRepository) ListWithRoom(ctx context.Context, weight, volume, units float64, limit int) ([]*locationModel.Utilization, error) {
+	query := `
+		SELECT * FROM (` + utilizationQuery + `
+			AND (locations.max_weight IS NOT NULL OR locations.max_volume IS NOT NULL OR locations.max_units IS NOT NULL)
+			AND NOT EXISTS (SELECT 1 FROM locations child WHERE child.parent_id = locations.id AND child.deleted_at IS NULL)
+			AND (` + locationModel.StatusColumn + `) IN ('active', 'blocked_outbound')
+			GROUP BY locations.id
+		) utilization
+		WHERE (max_weight IS NULL OR used_weight + $1 <= max_weight)
+		AND (max_volume IS NULL OR used_volume + $2 <= max_volume)
+		AND (max_units IS NULL OR used_units + $3 <= max_units)
+		ORDER BY used_units DESC, code
+		LIMIT $4
+	`
+
+	return s.queryUtilization(ctx, query, weight, volume, units, limit)
+}
+
+func (s *stockRepository) queryUtilization(ctx context.Context, query string, args ...interface{}) ([]*locationModel.Utilization, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	utilizations := []*locationModel.Utilization{}
+	for rows.Next() {
+		var utilization locationModel.Utilization
+		if err := scanUtilization(rows, &utilization); err != nil {
+			return nil, err
+		}
+
+		utilizations = append(utilizations, &utilization)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return utilizations, nil
+}
@@ -0,0 +1,129 @@
+package supplier
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/supplier"
+)
+
+const receiptColumns = `id, supplier_id, product_id, reference, ordered_qty, received_qty, rejected_qty, ordered_at, expected_at, received_at, created_at`
+
+// CreateReceipt implements supplier.SupplierRepository. It returns nil when the
+// product does not exist.
+func (s *supplierRepository) CreateReceipt(ctx context.Context, receipt *supplier.Receipt) (*supplier.Receipt, error) {
+	query := `
+		INSERT INTO supplier_receipts (supplier_id, product_id, reference, ordered_qty, received_qty, rejected_qty, ordered_at, expected_at, received_at, created_at)
+		SELECT $1::integer, id, $3::text, $4::numeric, $5::numeric, $6::numeric, $7::timestamptz, $8::timestamptz, $9::timestamptz, $10::timestamptz
+		FROM products
+		WHERE id = $2 AND deleted_at IS NULL
+		RETURNING id
+	`
+
+	receipt.CreatedAt = time.Now()
+	err := s.db.QueryRowContext(ctx, query,
+		receipt.SupplierID,
+		receipt.ProductID,
+		receipt.Reference,
+		receipt.OrderedQty,
+		receipt.ReceivedQty,
+		receipt.RejectedQty,
+		receipt.OrderedAt,
+		receipt.ExpectedAt,
+		receipt.ReceivedAt,
+		receipt.CreatedAt,
+	).Scan(&receipt.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// ListReceipts implements supplier.SupplierRepository.
+func (s *supplierRepository) ListReceipts(ctx context.Context, supplierID int, period supplier.Period, limit, page int) ([]*supplier.Receipt, error) {
+	query := `
+		SELECT ` + receiptColumns + `
+		FROM supplier_receipts
+		WHERE supplier_id = $1 AND received_at >= $2 AND received_at < $3
+		ORDER BY received_at DESC, id DESC
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, supplierID, period.From, period.To, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	receipts := []*supplier.Receipt{}
+	for rows.Next() {
+		var r supplier.Receipt
+		if err := rows.Scan(&r.ID, &r.SupplierID, &r.ProductID, &r.Reference, &r.OrderedQty, &r.ReceivedQty, &r.RejectedQty, &r.OrderedAt, &r.ExpectedAt, &r.ReceivedAt, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		receipts = append(receipts, &r)
+	}
+
+	return receipts, rows.Err()
+}
+
+// Scorecards implements supplier.SupplierRepository. A receipt is on time when
+// it arrived on or before the day it was expected, the time of day is not
+// counted. The ::date casts use the session time zone for the day boundary.
+// Over-deliveries count as a full fill.
+func (s *supplierRepository) Scorecards(ctx context.Context, period supplier.Period, supplierID int) ([]*supplier.Scorecard, error) {
+	query := `
+		SELECT
+			s.id,
+			s.name,
+			COUNT(*),
+			AVG(CASE WHEN r.received_at::date <= r.expected_at::date THEN 1.0 ELSE 0.0 END),
+			SUM(LEAST(r.received_qty, r.ordered_qty)) / NULLIF(SUM(r.ordered_qty), 0),
+			SUM(r.rejected_qty) / NULLIF(SUM(r.received_qty), 0),
+			AVG(EXTRACT(EPOCH FROM r.received_at - r.ordered_at) / 86400)
+		FROM supplier_receipts r
+		JOIN suppliers s ON s.id = r.supplier_id
+		WHERE r.received_at >= $1 AND r.received_at < $2 AND s.deleted_at IS NULL AND ($3 = 0 OR s.id = $3)
+		GROUP BY s.id, s.name
+		ORDER BY s.id
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, period.From, period.To, supplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	scorecards := []*supplier.Scorecard{}
+	for rows.Next() {
+		scorecard := supplier.Scorecard{Period: period}
+		var onTime, fill, rejection, leadTime sql.NullFloat64
+		if err := rows.Scan(&scorecard.SupplierID, &scorecard.SupplierName, &scorecard.Receipts, &onTime, &fill, &rejection, &leadTime); err != nil {
+			return nil, err
+		}
+
+		scorecard.OnTimeRate = nullableFloat(onTime)
+		scorecard.FillRate = nullableFloat(fill)
+		scorecard.RejectionRate = nullableFloat(rejection)
+		scorecard.AverageLeadTimeDays = nullableFloat(leadTime)
+		scorecards = append(scorecards, &scorecard)
+	}
+
+	return scorecards, rows.Err()
+}
+
+func nullableFloat(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+
+	return &value.Float64
+}
@@ -3,25 +3,52 @@ package supplier
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"time"
 
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/imports"
+	partyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/party"
 	"ecosystem.garyle/service/internal/domain/model/wms/master-data/supplier"
 	supplierRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/supplier"
+	"ecosystem.garyle/service/internal/infrastructure/database"
+	partyRepo "ecosystem.garyle/service/internal/infrastructure/database/wms/master-data/party"
+	"ecosystem.garyle/service/pkg/utils/filter"
+	"github.com/lib/pq"
 )
 
+const supplierColumns = `id, name, address, contact, ` + partyRepo.RegistrationColumns + `, created_at, updated_at, deleted_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSupplier(row rowScanner, item *supplier.Supplier) error {
+	dest := []interface{}{&item.ID, &item.Name, &item.Address, &item.Contact}
+	dest = append(dest, partyRepo.RegistrationFields(&item.Registration)...)
+	return row.Scan(append(dest, &item.CreatedAt, &item.UpdatedAt, &item.DeletedAt)...)
+}
+
 type supplierRepository struct {
+	*partyRepo.DetailRepository
 	db *sql.DB
 }
 
 func NewSupplierRepository(db *sql.DB) supplierRepo.SupplierRepository {
-	return &supplierRepository{db: db}
+	return &supplierRepository{DetailRepository: partyRepo.NewDetailRepository(db, "supplier", nil), db: db}
 }
 
-// Create implements supplier.SupplierRepository.
+// Create implements supplier.SupplierRepository. The addresses and contacts
+// of the supplier are saved in the same transaction.
 func (s *supplierRepository) Create(ctx context.Context, supplier *supplier.Supplier) (*supplier.Supplier, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO suppliers (name, address, contact, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO suppliers (name, address, contact, tax_country, tax_id, registration_number, payment_terms, currency, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id
 	`
 
@@ -29,17 +56,30 @@ func (s *supplierRepository) Create(ctx context.Context, supplier *supplier.Supp
 	supplier.CreatedAt = now
 	supplier.UpdatedAt = now
 
-	err := s.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		supplier.Name,
 		supplier.Address,
 		supplier.Contact,
+		supplier.TaxCountry,
+		supplier.TaxID,
+		supplier.RegistrationNumber,
+		supplier.PaymentTerms,
+		supplier.Currency,
 		supplier.CreatedAt,
 		supplier.UpdatedAt,
 	).Scan(&supplier.ID)
 
 	if err != nil {
+		return nil, database.Conflict(err)
+	}
+
+	if err := s.SaveDetails(ctx, tx, supplier.ID, supplier.Addresses, supplier.Contacts); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
@@ -47,16 +87,18 @@ func (s *supplierRepository) Create(ctx context.Context, supplier *supplier.Supp
 }
 
 // List implements supplier.SupplierRepository.
-func (s *supplierRepository) List(ctx context.Context, limit int, page int) ([]*supplier.Supplier, error) {
+func (s *supplierRepository) List(ctx context.Context, limit int, page int, params *filter.Params) ([]*supplier.Supplier, error) {
+	where, args := params.Where(1)
+
 	query := `
-		SELECT * FROM suppliers
+		SELECT ` + supplierColumns + `
+		FROM suppliers
 		WHERE deleted_at IS NULL
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
+	` + where + params.OrderBy(supplier.QuerySpec.DefaultSort...) + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
 
 	var offset int = (page - 1) * limit
-	rows, err := s.db.QueryContext(ctx, query, limit, offset)
+	args = append(args, limit, offset)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -66,8 +108,7 @@ func (s *supplierRepository) List(ctx context.Context, limit int, page int) ([]*
 	suppliers := []*supplier.Supplier{}
 	for rows.Next() {
 		var supplier supplier.Supplier
-		err := rows.Scan(&supplier.ID, &supplier.Name, &supplier.Address, &supplier.Contact, &supplier.CreatedAt, &supplier.UpdatedAt, &supplier.DeletedAt)
-		if err != nil {
+		if err := scanSupplier(rows, &supplier); err != nil {
 			return nil, err
 		}
 
@@ -82,14 +123,16 @@ func (s *supplierRepository) List(ctx context.Context, limit int, page int) ([]*
 }
 
 // Count implements supplier.SupplierRepository.
-func (s *supplierRepository) Count(ctx context.Context) (int, error) {
+func (s *supplierRepository) Count(ctx context.Context, params *filter.Params) (int, error) {
+	where, args := params.Where(1)
+
 	query := `
 		SELECT COUNT(*) FROM suppliers
 		WHERE deleted_at IS NULL
-	`
+	` + where
 
 	var total int
-	err := s.db.QueryRowContext(ctx, query).Scan(&total)
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&total)
 	if err != nil {
 		return 0, err
 	}
@@ -100,13 +143,17 @@ func (s *supplierRepository) Count(ctx context.Context) (int, error) {
 // GetByID implements supplier.SupplierRepository.
 func (s *supplierRepository) GetByID(ctx context.Context, id int) (*supplier.Supplier, error) {
 	query := `
-		SELECT * FROM suppliers
+		SELECT ` + supplierColumns + `
+		FROM suppliers
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var supplier supplier.Supplier
-	err := s.db.QueryRowContext(ctx, query, id).Scan(&supplier.ID, &supplier.Name, &supplier.Address, &supplier.Contact, &supplier.CreatedAt, &supplier.UpdatedAt, &supplier.DeletedAt)
+	err := scanSupplier(s.db.QueryRowContext(ctx, query, id), &supplier)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
 		return nil, err
 	}
 
@@ -117,16 +164,23 @@ func (s *supplierRepository) GetByID(ctx context.Context, id int) (*supplier.Sup
 func (s *supplierRepository) UpdateByID(ctx context.Context, supplier *supplier.Supplier, id int) error {
 	query := `
 		UPDATE suppliers
-		SET name = $1, address = $2, contact = $3, updated_at = $4
-		WHERE id = $5 AND deleted_at IS NULL
+		SET name = $1, address = $2, contact = $3, tax_country = $4, tax_id = $5, registration_number = $6, payment_terms = $7, currency = $8, updated_at = $9
+		WHERE id = $10 AND deleted_at IS NULL
 	`
 
-	_, err := s.db.ExecContext(ctx, query, supplier.Name, supplier.Address, supplier.Contact, supplier.UpdatedAt, id)
+	tx, err := database.BeginAuthored(ctx, s.db)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	return nil
+	supplier.UpdatedAt = time.Now()
+	_, err = tx.ExecContext(ctx, query, supplier.Name, supplier.Address, supplier.Contact, supplier.TaxCountry, supplier.TaxID, supplier.RegistrationNumber, supplier.PaymentTerms, supplier.Currency, supplier.UpdatedAt, id)
+	if err != nil {
+		return database.Conflict(err)
+	}
+
+	return tx.Commit()
 }
 
 // DeleteByID implements supplier.SupplierRepository.
@@ -137,10 +191,142 @@ func (s *supplierRepository) DeleteByID(ctx context.Context, id int) error {
 		WHERE id = $2 AND deleted_at IS NULL
 	`
 
-	_, err := s.db.ExecContext(ctx, query, time.Now(), id)
+	_, err := database.ExecAuthored(ctx, s.db, query, time.Now(), id)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// CreateBatch inserts suppliers in one transaction, see database.InsertBatch
+func (s *supplierRepository) CreateBatch(ctx context.Context, suppliers []*supplier.Supplier, opts imports.Options, report func(index int, err error)) error {
+	query := `
+		INSERT INTO suppliers (name, address, contact, tax_country, tax_id, registration_number, payment_terms, currency, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+
+	now := time.Now()
+	return database.InsertBatch(ctx, s.db, len(suppliers), opts, func(tx *sql.Tx, index int) error {
+		item := suppliers[index]
+		item.CreatedAt = now
+		item.UpdatedAt = now
+
+		return tx.QueryRowContext(ctx, query, item.Name, item.Address, item.Contact, item.TaxCountry, item.TaxID, item.RegistrationNumber, item.PaymentTerms, item.Currency, item.CreatedAt, item.UpdatedAt).Scan(&item.ID)
+	}, report)
+}
+
+// Export streams the suppliers matching the list filters to fn one row at a time
+func (s *supplierRepository) Export(ctx context.Context, params *filter.Params, includeDeleted bool, fn func(supplier *supplier.Supplier) error) error {
+	where, args := params.Where(1)
+
+	scope := "deleted_at IS NULL"
+	if includeDeleted {
+		scope = "TRUE"
+	}
+
+	query := `
+		SELECT ` + supplierColumns + `
+		FROM suppliers
+		WHERE ` + scope + where + params.OrderBy(supplier.QuerySpec.DefaultSort...)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var item supplier.Supplier
+		if err := scanSupplier(rows, &item); err != nil {
+			return err
+		}
+
+		if err := fn(&item); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Merge implements supplier.SupplierRepository. The sourcing rows and receipts
+// of the duplicate move to the survivor as well.
+func (s *supplierRepository) Merge(ctx context.Context, record *partyModel.MergeRecord) error {
+	return s.MergeParty(ctx, record, func(ctx context.Context, tx *sql.Tx, fromID, toID int, now time.Time) (map[string]int64, error) {
+		moved, err := moveProductSuppliers(ctx, tx, fromID, toID, now)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := tx.ExecContext(ctx, `UPDATE supplier_receipts SET supplier_id = $2 WHERE supplier_id = $1`, fromID, toID)
+		if err != nil {
+			return nil, err
+		}
+
+		if moved["supplier_receipts"], err = result.RowsAffected(); err != nil {
+			return nil, err
+		}
+
+		return moved, nil
+	})
+}
+
+// moveProductSuppliers moves the product sourcing of a merged supplier. When
+// both suppliers source the same product the survivor's row is kept, and it
+// becomes preferred when the duplicate's row was.
+func moveProductSuppliers(ctx context.Context, tx *sql.Tx, fromID, toID int, now time.Time) (map[string]int64, error) {
+	query := `
+		SELECT COALESCE(array_agg(duplicate.product_id), '{}')
+		FROM product_suppliers duplicate
+		JOIN product_suppliers kept ON kept.product_id = duplicate.product_id AND kept.supplier_id = $2 AND kept.deleted_at IS NULL
+		WHERE duplicate.supplier_id = $1 AND duplicate.is_preferred AND duplicate.deleted_at IS NULL
+	`
+
+	var preferred pq.Int64Array
+	if err := tx.QueryRowContext(ctx, query, fromID, toID).Scan(&preferred); err != nil {
+		return nil, err
+	}
+
+	query = `
+		UPDATE product_suppliers duplicate
+		SET deleted_at = $3, updated_at = $3, is_preferred = FALSE
+		FROM product_suppliers kept
+		WHERE duplicate.supplier_id = $1 AND duplicate.deleted_at IS NULL
+			AND kept.supplier_id = $2 AND kept.product_id = duplicate.product_id AND kept.deleted_at IS NULL
+	`
+
+	if _, err := tx.ExecContext(ctx, query, fromID, toID, now); err != nil {
+		return nil, err
+	}
+
+	query = `
+		UPDATE product_suppliers
+		SET is_preferred = TRUE, updated_at = $3
+		WHERE supplier_id = $1 AND product_id = ANY($2) AND deleted_at IS NULL
+	`
+
+	if _, err := tx.ExecContext(ctx, query, toID, preferred, now); err != nil {
+		return nil, err
+	}
+
+	query = `
+		UPDATE product_suppliers
+		SET supplier_id = $2, updated_at = $3
+		WHERE supplier_id = $1
+	`
+
+	result, err := tx.ExecContext(ctx, query, fromID, toID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]int64{"product_suppliers": moved}, nil
+}
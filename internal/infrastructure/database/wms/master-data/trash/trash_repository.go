@@ -0,0 +1,271 @@
+package trash
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"ecosystem.garyle/service/internal/domain/model/wms/master-data/conflict"
+	trashModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/trash"
+	trashRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/trash"
+	"ecosystem.garyle/service/internal/infrastructure/database"
+)
+
+// trashRepository works on any table of trashModel.Entities. Table and column
+// names come from those definitions, never from the request.
+type trashRepository struct {
+	db *sql.DB
+}
+
+func NewTrashRepository(db *sql.DB) trashRepo.TrashRepository {
+	return &trashRepository{db: db}
+}
+
+// List implements trash.TrashRepository.
+func (r *trashRepository) List(ctx context.Context, entity trashModel.Entity, limit, page int) ([]*trashModel.Item, error) {
+	query := `
+		SELECT id, COALESCE((` + entity.Label + `)::text, ''), deleted_at
+		FROM ` + entity.Table + `
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	items := []*trashModel.Item{}
+	for rows.Next() {
+		item := trashModel.Item{Entity: entity.Name}
+		if err := rows.Scan(&item.ID, &item.Label, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+
+		items = append(items, &item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Count implements trash.TrashRepository.
+func (r *trashRepository) Count(ctx context.Context, entity trashModel.Entity) (int, error) {
+	query := `SELECT COUNT(*) FROM ` + entity.Table + ` WHERE deleted_at IS NOT NULL`
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, query).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// Restore implements trash.TrashRepository. The unique keys and parents are
+// checked while the row is locked.
+func (r *trashRepository) Restore(ctx context.Context, entity trashModel.Entity, id int) (*trashModel.Item, error) {
+	tx, err := database.BeginAuthored(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	item, err := lockDeleted(ctx, tx, entity, id)
+	if err != nil || item == nil {
+		return nil, err
+	}
+
+	for _, key := range entity.Unique {
+		taken, err := hasConflict(ctx, tx, entity, key, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if taken {
+			return nil, &conflict.Error{Entity: entity.Singular, Fields: key.Columns}
+		}
+	}
+
+	for _, parent := range entity.Parents {
+		parentEntity, _ := trashModel.FindEntity(parent.Entity)
+
+		query := `
+			SELECT EXISTS (
+				SELECT 1 FROM ` + entity.Table + ` t
+				JOIN ` + parentEntity.Table + ` p ON p.id = t.` + parent.Column + `
+				WHERE t.id = $1 AND p.deleted_at IS NOT NULL
+			)
+		`
+
+		var deleted bool
+		if err := tx.QueryRowContext(ctx, query, id).Scan(&deleted); err != nil {
+			return nil, err
+		}
+
+		if deleted {
+			return nil, &trashModel.ParentDeletedError{Entity: entity.Singular, Column: parent.Column, Parent: parentEntity.Singular}
+		}
+	}
+
+	query := `UPDATE ` + entity.Table + ` SET deleted_at = NULL, updated_at = $1 WHERE id = $2`
+	if _, err := tx.ExecContext(ctx, query, time.Now(), id); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// Purge implements trash.TrashRepository. The row is only removed when no
+// other row refers to it, and its owned rows are removed with it.
+func (r *trashRepository) Purge(ctx context.Context, entity trashModel.Entity, id int) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	item, err := lockDeleted(ctx, tx, entity, id)
+	if err != nil || item == nil {
+		return false, err
+	}
+
+	if entity.Retain != "" {
+		var retained bool
+		query := `SELECT EXISTS (SELECT 1 FROM ` + entity.Table + ` WHERE id = $1` + and(entity.Retain) + `)`
+		if err := tx.QueryRowContext(ctx, query, id).Scan(&retained); err != nil {
+			return false, err
+		}
+
+		if retained {
+			return false, &trashModel.RetainedError{Entity: entity.Singular, ID: id, Reason: entity.RetainReason}
+		}
+	}
+
+	references := map[string]int{}
+	for _, reference := range entity.References {
+		query := `SELECT COUNT(*) FROM ` + reference.Table + ` WHERE ` + reference.Column + ` = $1` + and(reference.Where)
+
+		var count int
+		if err := tx.QueryRowContext(ctx, query, id).Scan(&count); err != nil {
+			return false, err
+		}
+
+		if count > 0 {
+			references[reference.Table] += count
+		}
+	}
+
+	if len(references) > 0 {
+		return false, &trashModel.ReferencedError{Entity: entity.Singular, ID: id, References: references}
+	}
+
+	for _, owned := range entity.Owned {
+		query := `DELETE FROM ` + owned.Table + ` WHERE ` + owned.Column + ` = $1` + and(owned.Where)
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return false, err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM `+entity.Table+` WHERE id = $1`, id); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// ListExpired implements trash.TrashRepository. Newer rows come first, so a
+// variant or child is purged before the row it refers to. Retained rows are
+// left out.
+func (r *trashRepository) ListExpired(ctx context.Context, entity trashModel.Entity, deletedBefore time.Time) ([]int, error) {
+	retain := ""
+	if entity.Retain != "" {
+		retain = " AND NOT (" + entity.Retain + ")"
+	}
+
+	query := `
+		SELECT id FROM ` + entity.Table + `
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1` + retain + `
+		ORDER BY id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, deletedBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// lockDeleted locks a deleted row, it returns nil when the row is not in the trash
+func lockDeleted(ctx context.Context, tx *sql.Tx, entity trashModel.Entity, id int) (*trashModel.Item, error) {
+	query := `
+		SELECT id, COALESCE((` + entity.Label + `)::text, ''), deleted_at
+		FROM ` + entity.Table + `
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		FOR UPDATE
+	`
+
+	item := trashModel.Item{Entity: entity.Name}
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&item.ID, &item.Label, &item.DeletedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// hasConflict checks if an active row has the same key as the restored row
+func hasConflict(ctx context.Context, tx *sql.Tx, entity trashModel.Entity, key trashModel.UniqueKey, id int) (bool, error) {
+	conditions := make([]string, 0, len(key.Columns))
+	for _, column := range key.Columns {
+		conditions = append(conditions, "t."+column+" = r."+column)
+	}
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM ` + entity.Table + ` t
+			JOIN (SELECT * FROM ` + entity.Table + ` WHERE id = $1` + and(key.Where) + `) r ON ` + strings.Join(conditions, " AND ") + `
+			WHERE t.id <> r.id AND t.deleted_at IS NULL
+		)
+	`
+
+	var taken bool
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&taken); err != nil {
+		return false, err
+	}
+
+	return taken, nil
+}
+
+func and(condition string) string {
+	if condition == "" {
+		return ""
+	}
+
+	return " AND (" + condition + ")"
+}
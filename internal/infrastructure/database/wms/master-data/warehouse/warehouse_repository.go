@@ -0,0 +1,182 @@
+package warehouse
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	warehouseModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/warehouse"
+	warehouseRepo "ecosystem.garyle/service/internal/domain/repository/wms/master-data/warehouse"
+	"ecosystem.garyle/service/internal/infrastructure/database"
+	"ecosystem.garyle/service/pkg/utils/filter"
+)
+
+type warehouseRepository struct {
+	db *sql.DB
+}
+
+func NewWarehouseRepository(db *sql.DB) warehouseRepo.WarehouseRepository {
+	return &warehouseRepository{db: db}
+}
+
+// Create implements warehouse.WarehouseRepository.
+func (w *warehouseRepository) Create(ctx context.Context, warehouse *warehouseModel.Warehouse) (*warehouseModel.Warehouse, error) {
+	query := `
+		INSERT INTO warehouses (code, name, address, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	now := time.Now()
+	warehouse.CreatedAt = now
+	warehouse.UpdatedAt = now
+
+	err := w.db.QueryRowContext(ctx, query, warehouse.Code, warehouse.Name, warehouse.Address, warehouse.CreatedAt, warehouse.UpdatedAt).Scan(&warehouse.ID)
+	if err != nil {
+		return nil, database.Conflict(err)
+	}
+
+	return warehouse, nil
+}
+
+// GetByID implements warehouse.WarehouseRepository.
+func (w *warehouseRepository) GetByID(ctx context.Context, id int) (*warehouseModel.Warehouse, error) {
+	query := `
+		SELECT id, code, name, address, created_at, updated_at, deleted_at
+		FROM warehouses
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	var warehouse warehouseModel.Warehouse
+	err := w.db.QueryRowContext(ctx, query, id).Scan(&warehouse.ID, &warehouse.Code, &warehouse.Name, &warehouse.Address, &warehouse.CreatedAt, &warehouse.UpdatedAt, &warehouse.DeletedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &warehouse, nil
+}
+
+// List implements warehouse.WarehouseRepository.
+func (w *warehouseRepository) List(ctx context.Context, limit, page int, params *filter.Params) ([]*warehouseModel.Warehouse, error) {
+	where, args := params.Where(1)
+
+	query := `
+		SELECT id, code, name, address, created_at, updated_at, deleted_at
+		FROM warehouses
+		WHERE deleted_at IS NULL
+	` + where + params.OrderBy(warehouseModel.QuerySpec.DefaultSort...) + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+
+	args = append(args, limit, (page-1)*limit)
+	rows, err := w.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	warehouses := []*warehouseModel.Warehouse{}
+	for rows.Next() {
+		var warehouse warehouseModel.Warehouse
+		if err := rows.Scan(&warehouse.ID, &warehouse.Code, &warehouse.Name, &warehouse.Address, &warehouse.CreatedAt, &warehouse.UpdatedAt, &warehouse.DeletedAt); err != nil {
+			return nil, err
+		}
+
+		warehouses = append(warehouses, &warehouse)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return warehouses, nil
+}
+
+// Count implements warehouse.WarehouseRepository.
+func (w *warehouseRepository) Count(ctx context.Context, params *filter.Params) (int, error) {
+	where, args := params.Where(1)
+
+	query := `
+		SELECT COUNT(*) FROM warehouses
+		WHERE deleted_at IS NULL
+	` + where
+
+	var total int
+	err := w.db.QueryRowContext(ctx, query, args...).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// UpdateByID implements warehouse.WarehouseRepository. A new code is carried
+// over to the full-path codes of the warehouse's locations.
+func (w *warehouseRepository) UpdateByID(ctx context.Context, warehouse *warehouseModel.Warehouse, id int) error {
+	tx, err := database.BeginAuthored(ctx, w.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var oldCode string
+	err = tx.QueryRowContext(ctx, `SELECT code FROM warehouses WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&oldCode)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE warehouses
+		SET code = $1, name = $2, address = $3, updated_at = $4
+		WHERE id = $5 AND deleted_at IS NULL
+	`
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, query, warehouse.Code, warehouse.Name, warehouse.Address, now, id); err != nil {
+		return database.Conflict(err)
+	}
+
+	if oldCode != warehouse.Code {
+		query := `
+			UPDATE locations
+			SET code = $1 || SUBSTRING(code FROM $3), updated_at = $4
+			WHERE warehouse_id = $5 AND segment <> '' AND code LIKE $2 || '-%'
+		`
+
+		if _, err := tx.ExecContext(ctx, query, warehouse.Code, oldCode, len(oldCode)+1, now, id); err != nil {
+			return database.Conflict(err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DeleteByID implements warehouse.WarehouseRepository.
+func (w *warehouseRepository) DeleteByID(ctx context.Context, id int) error {
+	query := `
+		UPDATE warehouses
+		SET deleted_at = $1
+		WHERE id = $2 AND deleted_at IS NULL
+	`
+
+	_, err := w.db.ExecContext(ctx, query, time.Now(), id)
+	return err
+}
+
+// CountLocations implements warehouse.WarehouseRepository.
+func (w *warehouseRepository) CountLocations(ctx context.Context, id int) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM locations
+		WHERE warehouse_id = $1 AND deleted_at IS NULL
+	`
+
+	var total int
+	if err := w.db.QueryRowContext(ctx, query, id).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	historyModel "ecosystem.garyle/service/internal/domain/model/wms/master-data/history"
+	"github.com/gin-gonic/gin"
+)
+
+// ChangeAuthor passes the author named in the X-Changed-By header on to the
+// repositories, which record it in the change history
+func ChangeAuthor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if author := c.GetHeader(historyModel.AuthorHeader); author != "" {
+			c.Request = c.Request.WithContext(historyModel.WithAuthor(c.Request.Context(), author))
+		}
+
+		c.Next()
+	}
+}
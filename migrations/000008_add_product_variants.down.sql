@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_products_category_id;
+DROP INDEX IF EXISTS idx_products_parent_id;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS variant_values,
+    DROP COLUMN IF EXISTS variant_attributes,
+    DROP COLUMN IF EXISTS category_id,
+    DROP COLUMN IF EXISTS parent_id;
\ No newline at end of file
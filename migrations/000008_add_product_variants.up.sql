@@ -0,0 +1,8 @@
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES products(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS category_id INTEGER REFERENCES categories(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS variant_attributes JSONB,
+    ADD COLUMN IF NOT EXISTS variant_values JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_products_parent_id ON products(parent_id);
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products(category_id);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS product_suppliers;
\ No newline at end of file
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS product_suppliers (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id),
+    supplier_id INTEGER NOT NULL REFERENCES suppliers(id),
+    supplier_item_code VARCHAR(100) NOT NULL DEFAULT '',
+    unit_cost NUMERIC(18, 4) NOT NULL DEFAULT 0,
+    currency CHAR(3) NOT NULL,
+    min_order_qty NUMERIC(18, 4) NOT NULL DEFAULT 0,
+    lead_time_days INTEGER NOT NULL DEFAULT 0,
+    is_preferred BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_suppliers_product_supplier ON product_suppliers(product_id, supplier_id) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_suppliers_preferred ON product_suppliers(product_id) WHERE is_preferred AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_product_suppliers_supplier_id ON product_suppliers(supplier_id);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS batches;
+
+ALTER TABLE products DROP COLUMN IF EXISTS is_batch_tracked;
\ No newline at end of file
@@ -0,0 +1,18 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS is_batch_tracked BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS batches (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id),
+    batch_no VARCHAR(100) NOT NULL,
+    manufacture_date DATE,
+    expire_date DATE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT chk_batches_status CHECK (status IN ('active', 'quarantined', 'expired')),
+    CONSTRAINT chk_batches_dates CHECK (manufacture_date IS NULL OR expire_date IS NULL OR manufacture_date <= expire_date)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_batches_product_batch_no ON batches(product_id, batch_no) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_batches_expire_date ON batches(expire_date) WHERE deleted_at IS NULL;
\ No newline at end of file
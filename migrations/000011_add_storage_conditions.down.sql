@@ -0,0 +1,14 @@
+ALTER TABLE locations
+    DROP COLUMN IF EXISTS accepts_fragile,
+    DROP COLUMN IF EXISTS is_block_stacked,
+    DROP COLUMN IF EXISTS hazmat_classes,
+    DROP COLUMN IF EXISTS max_temperature,
+    DROP COLUMN IF EXISTS min_temperature;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS is_fragile,
+    DROP COLUMN IF EXISTS is_stackable,
+    DROP COLUMN IF EXISTS un_number,
+    DROP COLUMN IF EXISTS hazmat_class,
+    DROP COLUMN IF EXISTS max_temperature,
+    DROP COLUMN IF EXISTS min_temperature;
\ No newline at end of file
@@ -0,0 +1,14 @@
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS min_temperature NUMERIC(6, 2),
+    ADD COLUMN IF NOT EXISTS max_temperature NUMERIC(6, 2),
+    ADD COLUMN IF NOT EXISTS hazmat_class VARCHAR(5) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS un_number VARCHAR(6) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS is_stackable BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS is_fragile BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE locations
+    ADD COLUMN IF NOT EXISTS min_temperature NUMERIC(6, 2),
+    ADD COLUMN IF NOT EXISTS max_temperature NUMERIC(6, 2),
+    ADD COLUMN IF NOT EXISTS hazmat_classes TEXT[],
+    ADD COLUMN IF NOT EXISTS is_block_stacked BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS accepts_fragile BOOLEAN NOT NULL DEFAULT TRUE;
\ No newline at end of file
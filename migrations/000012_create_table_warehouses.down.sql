@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_locations_parent_id;
+DROP INDEX IF EXISTS idx_locations_warehouse_id;
+
+ALTER TABLE locations
+    DROP COLUMN IF EXISTS segment,
+    DROP COLUMN IF EXISTS level,
+    DROP COLUMN IF EXISTS parent_id,
+    DROP COLUMN IF EXISTS warehouse_id;
+
+DROP TABLE IF EXISTS warehouses;
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS warehouses (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_warehouses_code ON warehouses(code) WHERE deleted_at IS NULL;
+
+ALTER TABLE locations
+    ADD COLUMN IF NOT EXISTS warehouse_id INTEGER REFERENCES warehouses(id),
+    ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES locations(id),
+    ADD COLUMN IF NOT EXISTS level VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS segment VARCHAR(50) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_locations_warehouse_id ON locations(warehouse_id);
+CREATE INDEX IF NOT EXISTS idx_locations_parent_id ON locations(parent_id);
\ No newline at end of file
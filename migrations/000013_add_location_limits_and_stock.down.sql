@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_location_stock_product_id;
+DROP INDEX IF EXISTS uq_location_stock_location_product_batch;
+DROP TABLE IF EXISTS location_stock;
+
+ALTER TABLE locations
+    DROP COLUMN IF EXISTS max_units,
+    DROP COLUMN IF EXISTS max_volume,
+    DROP COLUMN IF EXISTS max_weight;
\ No newline at end of file
@@ -0,0 +1,18 @@
+ALTER TABLE locations
+    ADD COLUMN IF NOT EXISTS max_weight NUMERIC(14, 3),
+    ADD COLUMN IF NOT EXISTS max_volume NUMERIC(14, 4),
+    ADD COLUMN IF NOT EXISTS max_units INTEGER;
+
+CREATE TABLE IF NOT EXISTS location_stock (
+    id SERIAL PRIMARY KEY,
+    location_id INTEGER NOT NULL REFERENCES locations(id),
+    product_id INTEGER NOT NULL REFERENCES products(id),
+    batch_id INTEGER REFERENCES batches(id),
+    quantity NUMERIC(14, 3) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CONSTRAINT chk_location_stock_quantity CHECK (quantity >= 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_location_stock_location_product_batch ON location_stock(location_id, product_id, (COALESCE(batch_id, 0)));
+CREATE INDEX IF NOT EXISTS idx_location_stock_product_id ON location_stock(product_id);
\ No newline at end of file
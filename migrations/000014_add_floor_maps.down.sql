@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS warehouse_floor_maps;
+
+ALTER TABLE locations
+    DROP COLUMN IF EXISTS z,
+    DROP COLUMN IF EXISTS y,
+    DROP COLUMN IF EXISTS x;
\ No newline at end of file
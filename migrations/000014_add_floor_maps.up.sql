@@ -0,0 +1,11 @@
+ALTER TABLE locations
+    ADD COLUMN IF NOT EXISTS x NUMERIC(10, 3),
+    ADD COLUMN IF NOT EXISTS y NUMERIC(10, 3),
+    ADD COLUMN IF NOT EXISTS z NUMERIC(10, 3);
+
+CREATE TABLE IF NOT EXISTS warehouse_floor_maps (
+    warehouse_id INTEGER PRIMARY KEY REFERENCES warehouses(id),
+    graph JSONB NOT NULL DEFAULT '{"nodes": [], "edges": []}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
\ No newline at end of file
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_locations_status;
+
+ALTER TABLE locations
+    DROP CONSTRAINT IF EXISTS chk_locations_status,
+    DROP COLUMN IF EXISTS status_expires_at,
+    DROP COLUMN IF EXISTS status_set_at,
+    DROP COLUMN IF EXISTS status_set_by,
+    DROP COLUMN IF EXISTS status_note,
+    DROP COLUMN IF EXISTS status_reason,
+    DROP COLUMN IF EXISTS status;
\ No newline at end of file
@@ -0,0 +1,10 @@
+ALTER TABLE locations
+    ADD COLUMN IF NOT EXISTS status VARCHAR(30) NOT NULL DEFAULT 'active',
+    ADD COLUMN IF NOT EXISTS status_reason VARCHAR(30) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS status_note TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS status_set_by VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS status_set_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS status_expires_at TIMESTAMP WITH TIME ZONE,
+    ADD CONSTRAINT chk_locations_status CHECK (status IN ('active', 'blocked_inbound', 'blocked_outbound', 'blocked', 'under_count'));
+
+CREATE INDEX IF NOT EXISTS idx_locations_status ON locations(status) WHERE status <> 'active';
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_categories_parent_id;
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id) WHERE deleted_at IS NULL;
\ No newline at end of file
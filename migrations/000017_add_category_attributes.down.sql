@@ -0,0 +1,2 @@
+ALTER TABLE products DROP COLUMN IF EXISTS attributes;
+ALTER TABLE categories DROP COLUMN IF EXISTS attributes;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS attributes JSONB;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS attributes JSONB;
\ No newline at end of file
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS customer_contacts;
+DROP TABLE IF EXISTS customer_addresses;
+DROP TABLE IF EXISTS supplier_contacts;
+DROP TABLE IF EXISTS supplier_addresses;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS party_merges;
\ No newline at end of file
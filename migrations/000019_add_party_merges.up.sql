@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS party_merges (
+    id SERIAL PRIMARY KEY,
+    party_type VARCHAR(20) NOT NULL,
+    survivor_id INTEGER NOT NULL,
+    duplicate_id INTEGER NOT NULL,
+    merged_by VARCHAR(100) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    moved JSONB NOT NULL DEFAULT '{}',
+    snapshot JSONB NOT NULL,
+    merged_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CONSTRAINT chk_party_merges_party_type CHECK (party_type IN ('supplier', 'customer'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_party_merges_survivor ON party_merges(party_type, survivor_id);
\ No newline at end of file
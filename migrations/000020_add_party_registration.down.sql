@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS uq_customers_tax_id;
+DROP INDEX IF EXISTS uq_suppliers_tax_id;
+
+ALTER TABLE customers
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS payment_terms,
+    DROP COLUMN IF EXISTS registration_number,
+    DROP COLUMN IF EXISTS tax_id,
+    DROP COLUMN IF EXISTS tax_country;
+
+ALTER TABLE suppliers
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS payment_terms,
+    DROP COLUMN IF EXISTS registration_number,
+    DROP COLUMN IF EXISTS tax_id,
+    DROP COLUMN IF EXISTS tax_country;
\ No newline at end of file
@@ -0,0 +1,16 @@
+ALTER TABLE suppliers
+    ADD COLUMN IF NOT EXISTS tax_country CHAR(2) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS tax_id VARCHAR(30) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS registration_number VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS payment_terms VARCHAR(10) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT '';
+
+ALTER TABLE customers
+    ADD COLUMN IF NOT EXISTS tax_country CHAR(2) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS tax_id VARCHAR(30) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS registration_number VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS payment_terms VARCHAR(10) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_suppliers_tax_id ON suppliers(tax_country, tax_id) WHERE tax_id <> '' AND deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_customers_tax_id ON customers(tax_country, tax_id) WHERE tax_id <> '' AND deleted_at IS NULL;
\ No newline at end of file
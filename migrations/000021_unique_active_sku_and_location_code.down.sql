@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS uq_products_sku;
+DROP INDEX IF EXISTS uq_locations_code;
+
+UPDATE products p SET sku = p.sku || '-deleted-' || p.id
+WHERE p.deleted_at IS NOT NULL AND EXISTS (SELECT 1 FROM products o WHERE o.sku = p.sku AND o.id <> p.id);
+
+UPDATE locations l SET code = l.code || '-deleted-' || l.id
+WHERE l.deleted_at IS NOT NULL AND EXISTS (SELECT 1 FROM locations o WHERE o.code = l.code AND o.id <> l.id);
+
+ALTER TABLE products ADD CONSTRAINT products_sku_key UNIQUE (sku);
+ALTER TABLE locations ADD CONSTRAINT locations_code_key UNIQUE (code);
\ No newline at end of file
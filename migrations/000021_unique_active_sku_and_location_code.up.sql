@@ -0,0 +1,5 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_sku_key;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_products_sku ON products(sku) WHERE deleted_at IS NULL;
+
+ALTER TABLE locations DROP CONSTRAINT IF EXISTS locations_code_key;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_locations_code ON locations(code) WHERE deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS supplier_receipts;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS supplier_receipts (
+    id SERIAL PRIMARY KEY,
+    supplier_id INTEGER NOT NULL REFERENCES suppliers(id),
+    product_id INTEGER NOT NULL REFERENCES products(id),
+    reference VARCHAR(100) NOT NULL DEFAULT '',
+    ordered_qty NUMERIC(18, 4) NOT NULL,
+    received_qty NUMERIC(18, 4) NOT NULL,
+    rejected_qty NUMERIC(18, 4) NOT NULL DEFAULT 0,
+    ordered_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expected_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CONSTRAINT chk_supplier_receipts_quantities CHECK (ordered_qty > 0 AND received_qty >= 0 AND rejected_qty BETWEEN 0 AND received_qty)
+);
+
+CREATE INDEX IF NOT EXISTS idx_supplier_receipts_supplier_received ON supplier_receipts(supplier_id, received_at);
+CREATE INDEX IF NOT EXISTS idx_supplier_receipts_received ON supplier_receipts(received_at);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS customer_service_profiles;
\ No newline at end of file
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS customer_service_profiles (
+    customer_id INTEGER PRIMARY KEY REFERENCES customers(id),
+    order_cutoff VARCHAR(5) NOT NULL DEFAULT '',
+    timezone VARCHAR(64) NOT NULL,
+    dispatch_lead_days INTEGER NOT NULL DEFAULT 0,
+    dispatch_days TEXT[] NOT NULL,
+    allowed_carriers TEXT[] NOT NULL DEFAULT '{}',
+    preferred_carrier VARCHAR(20) NOT NULL DEFAULT '',
+    split_policy VARCHAR(20) NOT NULL DEFAULT 'allowed',
+    labeling_instructions TEXT NOT NULL DEFAULT '',
+    packing_instructions TEXT NOT NULL DEFAULT '',
+    priority_tier VARCHAR(20) NOT NULL DEFAULT 'standard',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CONSTRAINT chk_customer_service_profiles_split_policy CHECK (split_policy IN ('allowed', 'line_complete', 'ship_complete')),
+    CONSTRAINT chk_customer_service_profiles_priority_tier CHECK (priority_tier IN ('low', 'standard', 'high', 'critical'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_service_profiles_priority_tier ON customer_service_profiles(priority_tier);
\ No newline at end of file
@@ -0,0 +1,8 @@
+ALTER TABLE customer_contacts ALTER COLUMN email TYPE VARCHAR(255);
+ALTER TABLE customer_contacts ALTER COLUMN phone TYPE VARCHAR(30);
+ALTER TABLE customer_contacts ALTER COLUMN name TYPE VARCHAR(255);
+
+ALTER TABLE customer_addresses ALTER COLUMN postal_code TYPE VARCHAR(10);
+
+ALTER TABLE customers ALTER COLUMN contact TYPE VARCHAR(255);
+ALTER TABLE customers DROP COLUMN IF EXISTS erased_at;
\ No newline at end of file
@@ -0,0 +1,8 @@
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS erased_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE customers ALTER COLUMN contact TYPE TEXT;
+
+ALTER TABLE customer_addresses ALTER COLUMN postal_code TYPE TEXT;
+
+ALTER TABLE customer_contacts ALTER COLUMN name TYPE TEXT;
+ALTER TABLE customer_contacts ALTER COLUMN phone TYPE TEXT;
+ALTER TABLE customer_contacts ALTER COLUMN email TYPE TEXT;
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS trg_categories_record_version ON categories;
+DROP TRIGGER IF EXISTS trg_customers_record_version ON customers;
+DROP TRIGGER IF EXISTS trg_suppliers_record_version ON suppliers;
+DROP TRIGGER IF EXISTS trg_locations_record_version ON locations;
+DROP TRIGGER IF EXISTS trg_products_record_version ON products;
+DROP FUNCTION IF EXISTS record_version();
+DROP TABLE IF EXISTS record_versions;
\ No newline at end of file
@@ -0,0 +1,67 @@
+CREATE TABLE IF NOT EXISTS record_versions (
+    id SERIAL PRIMARY KEY,
+    entity VARCHAR(20) NOT NULL,
+    record_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    snapshot JSONB NOT NULL,
+    changed_by VARCHAR(100) NOT NULL DEFAULT '',
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CONSTRAINT uq_record_versions_version UNIQUE (entity, record_id, version),
+    CONSTRAINT chk_record_versions_entity CHECK (entity IN ('products', 'locations', 'suppliers', 'customers', 'categories'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_record_versions_changed_at ON record_versions(entity, record_id, changed_at);
+
+CREATE OR REPLACE FUNCTION record_version() RETURNS TRIGGER AS $$
+DECLARE
+    last_version INTEGER;
+BEGIN
+    IF current_setting('app.record_history', true) = 'off' THEN
+        RETURN NEW;
+    END IF;
+
+    PERFORM pg_advisory_xact_lock(hashtext(TG_ARGV[0]), NEW.id);
+
+    SELECT COALESCE(MAX(version), 0) INTO last_version
+    FROM record_versions
+    WHERE entity = TG_ARGV[0] AND record_id = NEW.id;
+
+    IF last_version = 0 THEN
+        INSERT INTO record_versions (entity, record_id, version, snapshot, changed_by, changed_at)
+        VALUES (TG_ARGV[0], OLD.id, 1, to_jsonb(OLD), '', OLD.updated_at);
+        last_version := 1;
+    END IF;
+
+    INSERT INTO record_versions (entity, record_id, version, snapshot, changed_by, changed_at)
+    VALUES (
+        TG_ARGV[0],
+        NEW.id,
+        last_version + 1,
+        to_jsonb(NEW),
+        COALESCE(current_setting('app.changed_by', true), ''),
+        CASE WHEN NEW.updated_at > OLD.updated_at THEN NEW.updated_at ELSE now() END
+    );
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_products_record_version ON products;
+CREATE TRIGGER trg_products_record_version AFTER UPDATE ON products
+    FOR EACH ROW WHEN (OLD.* IS DISTINCT FROM NEW.*) EXECUTE FUNCTION record_version('products');
+
+DROP TRIGGER IF EXISTS trg_locations_record_version ON locations;
+CREATE TRIGGER trg_locations_record_version AFTER UPDATE ON locations
+    FOR EACH ROW WHEN (OLD.* IS DISTINCT FROM NEW.*) EXECUTE FUNCTION record_version('locations');
+
+DROP TRIGGER IF EXISTS trg_suppliers_record_version ON suppliers;
+CREATE TRIGGER trg_suppliers_record_version AFTER UPDATE ON suppliers
+    FOR EACH ROW WHEN (OLD.* IS DISTINCT FROM NEW.*) EXECUTE FUNCTION record_version('suppliers');
+
+DROP TRIGGER IF EXISTS trg_customers_record_version ON customers;
+CREATE TRIGGER trg_customers_record_version AFTER UPDATE ON customers
+    FOR EACH ROW WHEN (OLD.* IS DISTINCT FROM NEW.*) EXECUTE FUNCTION record_version('customers');
+
+DROP TRIGGER IF EXISTS trg_categories_record_version ON categories;
+CREATE TRIGGER trg_categories_record_version AFTER UPDATE ON categories
+    FOR EACH ROW WHEN (OLD.* IS DISTINCT FROM NEW.*) EXECUTE FUNCTION record_version('categories');
\ No newline at end of file
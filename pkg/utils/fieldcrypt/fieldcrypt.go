@@ -0,0 +1,193 @@
+// Package fieldcrypt encrypts single column values with AES-256-GCM. An
+// encrypted value is written as "enc:v<version>:<base64 nonce and ciphertext>",
+// so values encrypted with an older key version can still be read while they
+// are rotated to the active one.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const prefix = "enc:v"
+
+// KeySize is the size of a key in bytes, AES-256
+const KeySize = 32
+
+// Keyring holds the key versions of one kind of data. A nil Keyring leaves
+// values as they are, so encryption can be turned off in development.
+type Keyring struct {
+	keys   map[int]cipher.AEAD
+	active int
+}
+
+// ParseKeys reads key versions written as "1:<base64 key>,2:<base64 key>"
+func ParseKeys(spec string) (map[int][]byte, error) {
+	keys := map[int][]byte{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		version, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, errors.New("keys must be written as <version>:<base64 key>")
+		}
+
+		v, err := strconv.Atoi(version)
+		if err != nil || v <= 0 {
+			return nil, fmt.Errorf("key version %q must be a positive integer", version)
+		}
+
+		if _, exists := keys[v]; exists {
+			return nil, fmt.Errorf("key version %d is given twice", v)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key version %d is not valid base64", v)
+		}
+
+		keys[v] = key
+	}
+
+	return keys, nil
+}
+
+// New creates a keyring that encrypts with the active version and decrypts
+// with any version. Active 0 picks the highest version.
+func New(keys map[int][]byte, active int) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one key is required")
+	}
+
+	k := &Keyring{keys: map[int]cipher.AEAD{}, active: active}
+	for version, key := range keys {
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("key version %d must be %d bytes", version, KeySize)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		k.keys[version] = aead
+		if active == 0 && version > k.active {
+			k.active = version
+		}
+	}
+
+	if _, ok := k.keys[k.active]; !ok {
+		return nil, fmt.Errorf("active key version %d is not configured", k.active)
+	}
+
+	return k, nil
+}
+
+// Enabled reports whether values are encrypted
+func (k *Keyring) Enabled() bool {
+	return k != nil
+}
+
+// ActiveVersion is the key version new values are encrypted with
+func (k *Keyring) ActiveVersion() int {
+	if k == nil {
+		return 0
+	}
+
+	return k.active
+}
+
+// Encrypt encrypts a value with the active key. Empty values stay empty.
+func (k *Keyring) Encrypt(value string) (string, error) {
+	if k == nil || value == "" {
+		return value, nil
+	}
+
+	aead := k.keys[k.active]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(value), nil)
+	return prefix + strconv.Itoa(k.active) + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the plain value. Values that were never encrypted, e.g.
+// rows written before encryption was turned on, are returned as they are.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	version, payload, ok := parse(value)
+	if !ok {
+		return value, nil
+	}
+
+	if k == nil {
+		return "", errors.New("encrypted value found but no keys are configured")
+	}
+
+	aead, ok := k.keys[version]
+	if !ok {
+		return "", fmt.Errorf("key version %d is not configured", version)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("encrypted value is malformed")
+	}
+
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("encrypted value cannot be decrypted with key version %d", version)
+	}
+
+	return string(plain), nil
+}
+
+// NeedsRotation reports whether a value is plain or encrypted with another
+// version than the active one
+func (k *Keyring) NeedsRotation(value string) bool {
+	if k == nil || value == "" {
+		return false
+	}
+
+	version, _, ok := parse(value)
+	return !ok || version != k.active
+}
+
+// IsEncrypted reports whether a value was written by Encrypt
+func IsEncrypted(value string) bool {
+	_, _, ok := parse(value)
+	return ok
+}
+
+func parse(value string) (int, string, bool) {
+	if !strings.HasPrefix(value, prefix) {
+		return 0, "", false
+	}
+
+	version, payload, ok := strings.Cut(value[len(prefix):], ":")
+	if !ok {
+		return 0, "", false
+	}
+
+	v, err := strconv.Atoi(version)
+	if err != nil {
+		return 0, "", false
+	}
+
+	return v, payload, true
+}
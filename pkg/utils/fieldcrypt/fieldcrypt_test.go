@@ -0,0 +1,182 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
+
+func mustKeyring(t *testing.T, keys map[int][]byte, active int) *Keyring {
+	t.Helper()
+
+	keyring, err := New(keys, active)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	return keyring
+}
+
+func TestKeyringRoundTrip(t *testing.T) {
+	keyring := mustKeyring(t, map[int][]byte{1: testKey(1), 2: testKey(2)}, 0)
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "address", value: "Jl. Sudirman 1, Jakarta"},
+		{name: "unicode", value: "Straße 5, München"},
+		{name: "empty stays empty", value: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := keyring.Encrypt(tt.value)
+			if err != nil {
+				t.Fatalf("Encrypt: %v", err)
+			}
+
+			if tt.value != "" && (encrypted == tt.value || !strings.HasPrefix(encrypted, "enc:v2:")) {
+				t.Fatalf("Encrypt = %q, want a value encrypted with version 2", encrypted)
+			}
+
+			decrypted, err := keyring.Decrypt(encrypted)
+			if err != nil {
+				t.Fatalf("Decrypt: %v", err)
+			}
+
+			if decrypted != tt.value {
+				t.Errorf("Decrypt = %q, want %q", decrypted, tt.value)
+			}
+		})
+	}
+}
+
+func TestKeyringRotation(t *testing.T) {
+	old := mustKeyring(t, map[int][]byte{1: testKey(1)}, 0)
+	rotated := mustKeyring(t, map[int][]byte{1: testKey(1), 2: testKey(2)}, 2)
+	other := mustKeyring(t, map[int][]byte{1: testKey(9)}, 0)
+
+	encrypted, err := old.Encrypt("secret")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		keyring *Keyring
+		value   string
+		want    string
+		err     string
+	}{
+		{name: "old version is still read", keyring: rotated, value: encrypted, want: "secret"},
+		{name: "plain value passes through", keyring: rotated, value: "plain", want: "plain"},
+		{name: "plain value without keys", keyring: nil, value: "plain", want: "plain"},
+		{name: "encrypted value without keys", keyring: nil, value: encrypted, err: "no keys are configured"},
+		{name: "unknown version", keyring: mustKeyring(t, map[int][]byte{3: testKey(3)}, 0), value: encrypted, err: "key version 1 is not configured"},
+		{name: "wrong key", keyring: other, value: encrypted, err: "cannot be decrypted"},
+		{name: "malformed", keyring: rotated, value: "enc:v1:%%%", err: "malformed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.keyring.Decrypt(tt.value)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Decrypt = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyringNeedsRotation(t *testing.T) {
+	old := mustKeyring(t, map[int][]byte{1: testKey(1)}, 0)
+	keyring := mustKeyring(t, map[int][]byte{1: testKey(1), 2: testKey(2)}, 0)
+
+	oldValue, _ := old.Encrypt("secret")
+	activeValue, _ := keyring.Encrypt("secret")
+
+	tests := []struct {
+		name    string
+		keyring *Keyring
+		value   string
+		want    bool
+	}{
+		{name: "older version", keyring: keyring, value: oldValue, want: true},
+		{name: "active version", keyring: keyring, value: activeValue, want: false},
+		{name: "plain value", keyring: keyring, value: "plain", want: true},
+		{name: "empty value", keyring: keyring, value: "", want: false},
+		{name: "encryption off", keyring: nil, value: "plain", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.keyring.NeedsRotation(tt.value); got != tt.want {
+				t.Errorf("NeedsRotation = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNilKeyringEncrypt(t *testing.T) {
+	var keyring *Keyring
+
+	got, err := keyring.Encrypt("plain")
+	if err != nil || got != "plain" {
+		t.Errorf("Encrypt = %q, %v, want the plain value", got, err)
+	}
+
+	if keyring.Enabled() {
+		t.Error("Enabled = true for a nil keyring")
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name   string
+		keys   map[int][]byte
+		active int
+		want   int
+		err    string
+	}{
+		{name: "highest version is active", keys: map[int][]byte{1: testKey(1), 3: testKey(3)}, want: 3},
+		{name: "explicit active version", keys: map[int][]byte{1: testKey(1), 3: testKey(3)}, active: 1, want: 1},
+		{name: "no keys", keys: map[int][]byte{}, err: "at least one key"},
+		{name: "short key", keys: map[int][]byte{1: []byte("short")}, err: "must be 32 bytes"},
+		{name: "unknown active version", keys: map[int][]byte{1: testKey(1)}, active: 2, err: "active key version 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyring, err := New(tt.keys, tt.active)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if keyring.ActiveVersion() != tt.want {
+				t.Errorf("ActiveVersion = %d, want %d", keyring.ActiveVersion(), tt.want)
+			}
+		})
+	}
+}
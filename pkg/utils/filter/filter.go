@@ -0,0 +1,354 @@
+package filter
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldType describes how a query value is parsed before it is bound to SQL
+type FieldType int
+
+const (
+	String FieldType = iota
+	Integer
+	Number
+	Bool
+	Time
+)
+
+// Operator is a comparison supported in the query string, e.g. name[contains]=shirt
+type Operator string
+
+const (
+	Eq       Operator = "eq"
+	In       Operator = "in"
+	Contains Operator = "contains"
+	Gt       Operator = "gt"
+	Gte      Operator = "gte"
+	Lt       Operator = "lt"
+	Lte      Operator = "lte"
+	Null     Operator = "null"
+)
+
+// maxInValues limits the size of an "in" list
+const maxInValues = 100
+
+// reserved query keys that are never treated as filters
+var reservedKeys = map[string]bool{
+	"limit": true,
+	"page":  true,
+	"sort":  true,
+}
+
+// Field is a whitelisted filter/sort field of an entity
+type Field struct {
+	Column string
+	Type   FieldType
+}
+
+// Spec whitelists the fields of an entity that can be filtered and sorted.
+// Dynamic resolves filter fields that are not known up front, such as
+// attributes stored in a JSON column, it may be nil.
+type Spec struct {
+	Fields      map[string]Field
+	DefaultSort []Sort
+	Dynamic     func(name string, op Operator) (Field, bool)
+}
+
+// Condition is a single parsed filter
+type Condition struct {
+	Column   string
+	Operator Operator
+	Values   []interface{}
+}
+
+// Sort is a single parsed sort key
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// Params holds the filters and sorting parsed from a list request
+type Params struct {
+	Conditions []Condition
+	Sorts      []Sort
+}
+
+// Parse turns query parameters into Params using the whitelist in spec.
+//
+// Supported forms:
+//
+//	?name=shirt                  equal
+//	?unit[in]=pcs,box            in list
+//	?name[contains]=shirt        case-insensitive substring
+//	?weight[gte]=1&weight[lt]=5  range (gt, gte, lt, lte)
+//	?created_at[gte]=2024-01-01  date range on timestamp fields
+//	?parent_id[null]=true        IS NULL / IS NOT NULL
+//	?sort=-created_at,name       multi-field sort, "-" for descending
+//
+// Dynamic fields can be filtered but not sorted on.
+func Parse(values url.Values, spec Spec) (*Params, error) {
+	params := &Params{}
+
+	for key, rawValues := range values {
+		if reservedKeys[key] {
+			continue
+		}
+
+		name, op, bracketed, err := splitKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		field, ok := spec.Fields[name]
+		if !ok && spec.Dynamic != nil {
+			field, ok = spec.Dynamic(name, op)
+		}
+		if !ok {
+			// plain keys may belong to other handlers (e.g. export format),
+			// only bracketed keys are unambiguously filters
+			if bracketed {
+				return nil, fmt.Errorf("filter on field %q is not allowed", name)
+			}
+			continue
+		}
+
+		for _, raw := range rawValues {
+			condition, err := parseCondition(field, op, raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter %s: %w", key, err)
+			}
+			params.Conditions = append(params.Conditions, condition)
+		}
+	}
+
+	sorts, err := parseSort(values.Get("sort"), spec)
+	if err != nil {
+		return nil, err
+	}
+	params.Sorts = sorts
+
+	return params, nil
+}
+
+// splitKey splits "name[op]" into its field name and operator
+func splitKey(key string) (string, Operator, bool, error) {
+	open := strings.Index(key, "[")
+	if open < 0 {
+		return key, Eq, false, nil
+	}
+
+	if !strings.HasSuffix(key, "]") || open == 0 {
+		return "", "", false, fmt.Errorf("invalid filter key %q", key)
+	}
+
+	name := key[:open]
+	op := Operator(key[open+1 : len(key)-1])
+	switch op {
+	case Eq, In, Contains, Gt, Gte, Lt, Lte, Null:
+		return name, op, true, nil
+	}
+
+	return "", "", false, fmt.Errorf("unsupported filter operator %q", op)
+}
+
+func parseCondition(field Field, op Operator, raw string) (Condition, error) {
+	condition := Condition{Column: field.Column, Operator: op}
+
+	switch op {
+	case Contains:
+		if field.Type != String {
+			return condition, fmt.Errorf("contains is only supported on text fields")
+		}
+		condition.Values = []interface{}{"%" + escapeLike(raw) + "%"}
+		return condition, nil
+	case In:
+		parts := strings.Split(raw, ",")
+		if len(parts) > maxInValues {
+			return condition, fmt.Errorf("at most %d values are allowed", maxInValues)
+		}
+		for _, part := range parts {
+			value, err := parseValue(field.Type, strings.TrimSpace(part))
+			if err != nil {
+				return condition, err
+			}
+			condition.Values = append(condition.Values, value)
+		}
+		return condition, nil
+	case Null:
+		isNull, err := strconv.ParseBool(raw)
+		if err != nil {
+			return condition, fmt.Errorf("%q is not a boolean", raw)
+		}
+		condition.Values = []interface{}{isNull}
+		return condition, nil
+	case Gt, Gte, Lt, Lte:
+		if field.Type == Bool {
+			return condition, fmt.Errorf("range is not supported on boolean fields")
+		}
+	}
+
+	value, err := parseValue(field.Type, raw)
+	if err != nil {
+		return condition, err
+	}
+
+	// a date-only upper bound covers the whole day
+	if field.Type == Time && op == Lte && isDateOnly(raw) {
+		condition.Operator = Lt
+		value = value.(time.Time).AddDate(0, 0, 1)
+	}
+
+	condition.Values = []interface{}{value}
+	return condition, nil
+}
+
+func parseValue(fieldType FieldType, raw string) (interface{}, error) {
+	switch fieldType {
+	case Integer:
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", raw)
+		}
+		return value, nil
+	case Number:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", raw)
+		}
+		return value, nil
+	case Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", raw)
+		}
+		return value, nil
+	case Time:
+		if isDateOnly(raw) {
+			value, err := time.Parse("2006-01-02", raw)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a valid date", raw)
+			}
+			return value, nil
+		}
+		value, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid date, use YYYY-MM-DD or RFC3339", raw)
+		}
+		return value, nil
+	}
+
+	return raw, nil
+}
+
+func isDateOnly(raw string) bool {
+	return len(raw) == len("2006-01-02")
+}
+
+func escapeLike(raw string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(raw)
+}
+
+func parseSort(raw string, spec Spec) ([]Sort, error) {
+	if raw == "" {
+		return spec.DefaultSort, nil
+	}
+
+	var sorts []Sort
+	for _, key := range strings.Split(raw, ",") {
+		key = strings.TrimSpace(key)
+		desc := strings.HasPrefix(key, "-")
+		key = strings.TrimPrefix(key, "-")
+
+		field, ok := spec.Fields[key]
+		if !ok {
+			return nil, fmt.Errorf("sort on field %q is not allowed", key)
+		}
+
+		sorts = append(sorts, Sort{Column: field.Column, Desc: desc})
+	}
+
+	return sorts, nil
+}
+
+// Where renders the conditions as " AND ..." clauses with placeholders
+// starting at $argStart, so it can be appended to an existing WHERE.
+func (p *Params) Where(argStart int) (string, []interface{}) {
+	if p == nil {
+		return "", nil
+	}
+
+	var builder strings.Builder
+	var args []interface{}
+	next := argStart
+
+	for _, condition := range p.Conditions {
+		switch condition.Operator {
+		case In:
+			placeholders := make([]string, len(condition.Values))
+			for i := range condition.Values {
+				placeholders[i] = fmt.Sprintf("$%d", next)
+				next++
+			}
+			fmt.Fprintf(&builder, " AND %s IN (%s)", condition.Column, strings.Join(placeholders, ", "))
+		case Contains:
+			fmt.Fprintf(&builder, " AND %s ILIKE $%d", condition.Column, next)
+			next++
+		case Null:
+			if condition.Values[0].(bool) {
+				fmt.Fprintf(&builder, " AND %s IS NULL", condition.Column)
+			} else {
+				fmt.Fprintf(&builder, " AND %s IS NOT NULL", condition.Column)
+			}
+			continue
+		default:
+			fmt.Fprintf(&builder, " AND %s %s $%d", condition.Column, sqlOperators[condition.Operator], next)
+			next++
+		}
+
+		args = append(args, condition.Values...)
+	}
+
+	return builder.String(), args
+}
+
+var sqlOperators = map[Operator]string{
+	Eq:  "=",
+	Gt:  ">",
+	Gte: ">=",
+	Lt:  "<",
+	Lte: "<=",
+}
+
+// OrderBy renders the sort keys as an ORDER BY clause, falling back to
+// fallback when no sort was requested. The id column is always added
+// last so pagination stays stable.
+func (p *Params) OrderBy(fallback ...Sort) string {
+	sorts := fallback
+	if p != nil && len(p.Sorts) > 0 {
+		sorts = p.Sorts
+	}
+
+	keys := make([]string, 0, len(sorts)+1)
+	hasID := false
+	for _, sort := range sorts {
+		direction := "ASC"
+		if sort.Desc {
+			direction = "DESC"
+		}
+		if sort.Column == "id" {
+			hasID = true
+		}
+		keys = append(keys, sort.Column+" "+direction)
+	}
+
+	if !hasID {
+		keys = append(keys, "id DESC")
+	}
+
+	return " ORDER BY " + strings.Join(keys, ", ")
+}
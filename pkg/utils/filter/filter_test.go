@@ -0,0 +1,155 @@
+package filter
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSpec = Spec{
+	Fields: map[string]Field{
+		"id":         {Column: "id", Type: Integer},
+		"name":       {Column: "name", Type: String},
+		"weight":     {Column: "weight", Type: Number},
+		"is_active":  {Column: "is_active", Type: Bool},
+		"parent_id":  {Column: "parent_id", Type: Integer},
+		"created_at": {Column: "created_at", Type: Time},
+	},
+	DefaultSort: []Sort{{Column: "created_at", Desc: true}},
+	Dynamic: func(name string, op Operator) (Field, bool) {
+		if !strings.HasPrefix(name, "attr.") {
+			return Field{}, false
+		}
+		return Field{Column: "attributes->>'" + strings.TrimPrefix(name, "attr.") + "'", Type: String}, true
+	},
+}
+
+func TestParse(t *testing.T) {
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		query      string
+		conditions []Condition
+		sorts      []Sort
+		err        string
+	}{
+		{
+			name:  "no filters uses the default sort",
+			query: "",
+			sorts: []Sort{{Column: "created_at", Desc: true}},
+		},
+		{
+			name:       "equal",
+			query:      "name=shirt",
+			conditions: []Condition{{Column: "name", Operator: Eq, Values: []interface{}{"shirt"}}},
+		},
+		{
+			name:       "in list",
+			query:      "id[in]=1, 2,3",
+			conditions: []Condition{{Column: "id", Operator: In, Values: []interface{}{1, 2, 3}}},
+		},
+		{
+			name:       "contains escapes like wildcards",
+			query:      "name[contains]=50%25_off",
+			conditions: []Condition{{Column: "name", Operator: Contains, Values: []interface{}{`%50\%\_off%`}}},
+		},
+		{
+			name:       "range",
+			query:      "weight[gte]=1.5",
+			conditions: []Condition{{Column: "weight", Operator: Gte, Values: []interface{}{1.5}}},
+		},
+		{
+			name:       "date lower bound",
+			query:      "created_at[gte]=2024-01-02",
+			conditions: []Condition{{Column: "created_at", Operator: Gte, Values: []interface{}{day}}},
+		},
+		{
+			name:       "date upper bound covers the whole day",
+			query:      "created_at[lte]=2024-01-02",
+			conditions: []Condition{{Column: "created_at", Operator: Lt, Values: []interface{}{day.AddDate(0, 0, 1)}}},
+		},
+		{
+			name:       "RFC 3339 time",
+			query:      "created_at[lt]=2024-01-02T10:00:00Z",
+			conditions: []Condition{{Column: "created_at", Operator: Lt, Values: []interface{}{day.Add(10 * time.Hour)}}},
+		},
+		{
+			name:       "null",
+			query:      "parent_id[null]=true",
+			conditions: []Condition{{Column: "parent_id", Operator: Null, Values: []interface{}{true}}},
+		},
+		{
+			name:       "boolean",
+			query:      "is_active=false",
+			conditions: []Condition{{Column: "is_active", Operator: Eq, Values: []interface{}{false}}},
+		},
+		{
+			name:       "dynamic field",
+			query:      "attr.plug=EU",
+			conditions: []Condition{{Column: "attributes->>'plug'", Operator: Eq, Values: []interface{}{"EU"}}},
+		},
+		{
+			name:       "repeated key adds a condition per value",
+			query:      "weight[gt]=1&weight[gt]=2",
+			conditions: []Condition{{Column: "weight", Operator: Gt, Values: []interface{}{1.0}}, {Column: "weight", Operator: Gt, Values: []interface{}{2.0}}},
+		},
+		{
+			name:  "reserved and unknown plain keys are ignored",
+			query: "limit=10&page=2&format=csv",
+		},
+		{
+			name:  "multi-field sort",
+			query: "sort=-weight, name",
+			sorts: []Sort{{Column: "weight", Desc: true}, {Column: "name"}},
+		},
+		{name: "unknown bracketed field", query: "secret[eq]=1", err: `filter on field "secret" is not allowed`},
+		{name: "unsupported operator", query: "name[like]=a", err: `unsupported filter operator "like"`},
+		{name: "malformed key", query: "name[eq=a", err: `invalid filter key`},
+		{name: "not an integer", query: "id=abc", err: `"abc" is not an integer`},
+		{name: "not a number", query: "weight[lt]=heavy", err: `"heavy" is not a number`},
+		{name: "invalid date", query: "created_at[gte]=yesterday", err: "is not a valid date"},
+		{name: "contains on a number", query: "weight[contains]=1", err: "only supported on text fields"},
+		{name: "range on a boolean", query: "is_active[gt]=true", err: "not supported on boolean fields"},
+		{name: "null is not a boolean", query: "parent_id[null]=maybe", err: `"maybe" is not a boolean`},
+		{name: "too many in values", query: "id[in]=" + strings.Repeat("1,", maxInValues) + "1", err: "at most 100 values"},
+		{name: "sort on unknown field", query: "sort=secret", err: `sort on field "secret" is not allowed`},
+		{name: "sort on dynamic field", query: "sort=attr.plug", err: `sort on field "attr.plug" is not allowed`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery: %v", err)
+			}
+
+			params, err := Parse(values, testSpec)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(params.Conditions, tt.conditions) {
+				t.Errorf("conditions = %#v, want %#v", params.Conditions, tt.conditions)
+			}
+
+			sorts := tt.sorts
+			if sorts == nil {
+				sorts = testSpec.DefaultSort
+			}
+
+			if !reflect.DeepEqual(params.Sorts, sorts) {
+				t.Errorf("sorts = %#v, want %#v", params.Sorts, sorts)
+			}
+		})
+	}
+}
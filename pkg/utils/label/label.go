@@ -0,0 +1,59 @@
+package label
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/datamatrix"
+	"github.com/boombuler/barcode/qr"
+)
+
+// Label is the content of a single label, Fields fill the {placeholders}
+// of the template lines, e.g. {code} and {zone}
+type Label struct {
+	Barcode string
+	Fields  map[string]string
+}
+
+// Lines returns the human-readable lines of the label
+func (l Label) Lines(template Template) []string {
+	pairs := make([]string, 0, len(l.Fields)*2)
+	for key, value := range l.Fields {
+		pairs = append(pairs, "{"+key+"}", value)
+	}
+
+	replacer := strings.NewReplacer(pairs...)
+	lines := make([]string, len(template.Lines))
+	for i, line := range template.Lines {
+		lines[i] = replacer.Replace(line)
+	}
+
+	return lines
+}
+
+// Render writes the labels in format to w
+func Render(w io.Writer, format Format, template Template, labels []Label) error {
+	switch format {
+	case ZPL:
+		return RenderZPL(w, template, labels)
+	case PDF:
+		return RenderPDF(w, template, labels)
+	}
+
+	return errors.New("format must be zpl or pdf")
+}
+
+// encode builds the barcode of content, its bounds are the number of modules
+func encode(symbology Symbology, content string) (barcode.Barcode, error) {
+	switch symbology {
+	case QR:
+		return qr.Encode(content, qr.M, qr.Auto)
+	case DataMatrix:
+		return datamatrix.Encode(content)
+	}
+
+	return code128.Encode(content)
+}
@@ -0,0 +1,72 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"strconv"
+
+	"github.com/boombuler/barcode"
+	"github.com/go-pdf/fpdf"
+)
+
+// pixelsPerModule is the size of a barcode module in the embedded images,
+// the image is scaled to the barcode size by the PDF
+const pixelsPerModule = 4
+
+// RenderPDF writes the labels to a PDF with one label per page
+func RenderPDF(w io.Writer, template Template, labels []Label) error {
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		UnitStr: "mm",
+		Size:    fpdf.SizeType{Wd: template.Width, Ht: template.Height},
+	})
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetFont("Helvetica", "", template.FontSize)
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+
+	for i, label := range labels {
+		code, err := encode(template.Symbology, label.Barcode)
+		if err != nil {
+			return fmt.Errorf("label %s: %w", label.Barcode, err)
+		}
+
+		width, height := template.Width-2*margin, template.BarcodeSize
+		if template.is2D() {
+			width = template.BarcodeSize
+		}
+
+		bounds := code.Bounds()
+		scaled, err := barcode.Scale(code, bounds.Dx()*pixelsPerModule, bounds.Dy()*pixelsPerModule)
+		if err != nil {
+			return fmt.Errorf("label %s: %w", label.Barcode, err)
+		}
+
+		// fpdf only reads 8-bit PNGs, the barcodes are drawn in 16-bit gray
+		gray := image.NewGray(scaled.Bounds())
+		draw.Draw(gray, gray.Bounds(), scaled, scaled.Bounds().Min, draw.Src)
+
+		var encoded bytes.Buffer
+		if err := png.Encode(&encoded, gray); err != nil {
+			return err
+		}
+
+		name := "barcode-" + strconv.Itoa(i)
+		pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{ImageType: "PNG"}, &encoded)
+
+		pdf.AddPage()
+		pdf.ImageOptions(name, margin, margin, width, height, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+		if template.TextVisible() {
+			pdf.SetXY(margin, margin+template.BarcodeSize+margin)
+			for _, line := range label.Lines(template) {
+				pdf.CellFormat(template.Width-2*margin, template.lineHeight(), translate(line), "", 2, "L", false, 0, "")
+			}
+		}
+	}
+
+	return pdf.Output(w)
+}
@@ -0,0 +1,186 @@
+package label
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Symbology is the kind of barcode printed on a label
+type Symbology string
+
+const (
+	Code128    Symbology = "code128"
+	QR         Symbology = "qr"
+	DataMatrix Symbology = "datamatrix"
+)
+
+// Format is the output format of a label sheet
+type Format string
+
+const (
+	ZPL Format = "zpl" // for Zebra printers
+	PDF Format = "pdf" // one label per page for office printers
+)
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	if f == PDF {
+		return "application/pdf"
+	}
+
+	return "application/zpl"
+}
+
+// Template describes the size and layout of a label
+type Template struct {
+	Name        string    `json:"name"`
+	Width       float64   `json:"width"`  // mm
+	Height      float64   `json:"height"` // mm
+	Symbology   Symbology `json:"symbology"`
+	BarcodeSize float64   `json:"barcode_size"` // mm, the bar height of a Code128 or the side of a 2D code
+	ShowText    *bool     `json:"show_text"`    // print the human-readable lines, defaults to true
+	FontSize    float64   `json:"font_size"`    // pt
+	Lines       []string  `json:"lines"`        // human-readable lines with {placeholders}, see Label
+	DPI         int       `json:"dpi"`          // printer resolution used for ZPL, defaults to 203
+}
+
+// margin around the content of a label in mm
+const margin = 2.0
+
+// Templates are the predefined label templates by name
+var Templates = map[string]Template{
+	"bin": {
+		Name: "bin", Width: 50, Height: 25, Symbology: Code128, BarcodeSize: 10, FontSize: 12,
+		Lines: []string{"{code}"},
+	},
+	"rack": {
+		Name: "rack", Width: 100, Height: 50, Symbology: Code128, BarcodeSize: 20, FontSize: 24,
+		Lines: []string{"{code}", "Zone {zone}"},
+	},
+	"bin-qr": {
+		Name: "bin-qr", Width: 50, Height: 30, Symbology: QR, BarcodeSize: 18, FontSize: 10,
+		Lines: []string{"{code}"},
+	},
+	"bin-datamatrix": {
+		Name: "bin-datamatrix", Width: 40, Height: 25, Symbology: DataMatrix, BarcodeSize: 14, FontSize: 9,
+		Lines: []string{"{code}"},
+	},
+}
+
+// DefaultTemplate is used when a request names no template
+const DefaultTemplate = "bin"
+
+// ListTemplates returns the predefined templates ordered by name
+func ListTemplates() []Template {
+	templates := make([]Template, 0, len(Templates))
+	for _, template := range Templates {
+		templates = append(templates, template)
+	}
+
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates
+}
+
+// Resolve returns the predefined template name, or custom when it is set.
+// Unset fields of a custom template fall back to the named template.
+func Resolve(name string, custom *Template) (Template, error) {
+	if name == "" {
+		name = DefaultTemplate
+	}
+
+	base, ok := Templates[name]
+	if !ok {
+		return Template{}, fmt.Errorf("unknown label template %q", name)
+	}
+
+	if custom == nil {
+		return base, base.Validate()
+	}
+
+	template := *custom
+	if template.Name == "" {
+		template.Name = "custom"
+	}
+	if template.Width == 0 {
+		template.Width = base.Width
+	}
+	if template.Height == 0 {
+		template.Height = base.Height
+	}
+	if template.Symbology == "" {
+		template.Symbology = base.Symbology
+	}
+	if template.BarcodeSize == 0 {
+		template.BarcodeSize = base.BarcodeSize
+	}
+	if template.FontSize == 0 {
+		template.FontSize = base.FontSize
+	}
+	if template.Lines == nil {
+		template.Lines = base.Lines
+	}
+
+	return template, template.Validate()
+}
+
+// Validate checks that the barcode and the text fit on the label
+func (t Template) Validate() error {
+	switch t.Symbology {
+	case Code128, QR, DataMatrix:
+	default:
+		return errors.New("symbology must be code128, qr or datamatrix")
+	}
+
+	if t.Width <= 2*margin || t.Height <= 2*margin {
+		return errors.New("label width and height must be larger than 4mm")
+	}
+
+	if t.BarcodeSize <= 0 || t.FontSize <= 0 {
+		return errors.New("barcode_size and font_size must be greater than 0")
+	}
+
+	if t.DPI < 0 || t.DPI > 600 {
+		return errors.New("dpi must be between 0 and 600")
+	}
+
+	if t.BarcodeSize+t.textHeight() > t.Height-2*margin {
+		return errors.New("barcode and text do not fit on the label")
+	}
+
+	if t.is2D() && t.BarcodeSize > t.Width-2*margin {
+		return errors.New("barcode does not fit on the label")
+	}
+
+	return nil
+}
+
+// TextVisible reports whether the human-readable lines are printed
+func (t Template) TextVisible() bool {
+	return (t.ShowText == nil || *t.ShowText) && len(t.Lines) > 0
+}
+
+func (t Template) dpi() int {
+	if t.DPI == 0 {
+		return 203
+	}
+
+	return t.DPI
+}
+
+func (t Template) is2D() bool {
+	return t.Symbology == QR || t.Symbology == DataMatrix
+}
+
+// lineHeight is the height of a text line in mm
+func (t Template) lineHeight() float64 {
+	return t.FontSize * 25.4 / 72 * 1.2
+}
+
+func (t Template) textHeight() float64 {
+	if !t.TextVisible() {
+		return 0
+	}
+
+	return margin + t.lineHeight()*float64(len(t.Lines))
+}
@@ -0,0 +1,67 @@
+package label
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// zplEscaper hex-escapes the characters that ZPL treats as commands, fields
+// are written with ^FH so the printer decodes them again
+var zplEscaper = strings.NewReplacer(`\`, `\5C`, "^", `\5E`, "~", `\7E`)
+
+// RenderZPL writes one ZPL label format per label
+func RenderZPL(w io.Writer, template Template, labels []Label) error {
+	buffer := bufio.NewWriter(w)
+	dots := func(mm float64) int {
+		return int(math.Round(mm * float64(template.dpi()) / 25.4))
+	}
+
+	for _, label := range labels {
+		code, err := encode(template.Symbology, label.Barcode)
+		if err != nil {
+			return fmt.Errorf("label %s: %w", label.Barcode, err)
+		}
+
+		modules := code.Bounds().Dx()
+		size := dots(template.BarcodeSize)
+
+		fmt.Fprintf(buffer, "^XA\n^CI28\n^PW%d\n^LL%d\n", dots(template.Width), dots(template.Height))
+		fmt.Fprintf(buffer, "^FO%d,%d\n", dots(margin), dots(margin))
+
+		switch template.Symbology {
+		case QR:
+			// the quiet zone of 4 modules on both sides is part of the printed size
+			fmt.Fprintf(buffer, "^BQN,2,%d\n^FH^FDMA,%s^FS\n", moduleDots(size, modules+8, 10), zplEscaper.Replace(label.Barcode))
+		case DataMatrix:
+			fmt.Fprintf(buffer, "^BXN,%d,200\n^FH^FD%s^FS\n", moduleDots(size, modules, 50), zplEscaper.Replace(label.Barcode))
+		default:
+			fmt.Fprintf(buffer, "^BY%d\n^BCN,%d,N,N,N\n^FH^FD%s^FS\n", moduleDots(dots(template.Width-2*margin), modules, 10), size, zplEscaper.Replace(label.Barcode))
+		}
+
+		if template.TextVisible() {
+			font := dots(template.FontSize * 25.4 / 72)
+			y := margin + template.BarcodeSize + margin
+			for _, line := range label.Lines(template) {
+				fmt.Fprintf(buffer, "^FO%d,%d\n^A0N,%d,%d\n^FH^FD%s^FS\n", dots(margin), dots(y), font, font, zplEscaper.Replace(line))
+				y += template.lineHeight()
+			}
+		}
+
+		fmt.Fprint(buffer, "^XZ\n")
+	}
+
+	return buffer.Flush()
+}
+
+// moduleDots returns how many dots wide a barcode module can be so modules
+// fit in size dots, clamped to what the printer accepts
+func moduleDots(size, modules, maximum int) int {
+	if modules <= 0 {
+		return 1
+	}
+
+	return max(1, min(maximum, size/modules))
+}
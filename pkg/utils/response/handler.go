@@ -40,6 +40,18 @@ func Created(c *gin.Context, data interface{}, message string) {
 	c.JSON(http.StatusCreated, resp)
 }
 
+func Accepted(c *gin.Context, data interface{}, message string) {
+	resp := Response{
+		Meta: Meta{
+			Message: message,
+			Status:  "success",
+			Code:    http.StatusAccepted,
+		},
+		Data: data,
+	}
+	c.JSON(http.StatusAccepted, resp)
+}
+
 func NoContent(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
@@ -69,6 +81,11 @@ func NotFound(c *gin.Context, message string) {
 	c.JSON(http.StatusNotFound, resp)
 }
 
+func Conflict(c *gin.Context, message string) {
+	resp := ConflictError(message)
+	c.JSON(http.StatusConflict, resp)
+}
+
 func Server(c *gin.Context, message string) {
 	resp := ServerError(message)
 	c.JSON(http.StatusInternalServerError, resp)
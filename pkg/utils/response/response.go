@@ -82,6 +82,13 @@ func ForbiddenError(message string) Response {
 	return NewErrorResponse(403, message)
 }
 
+func ConflictError(message string) Response {
+	if message == "" {
+		message = "Resource already exists"
+	}
+	return NewErrorResponse(409, message)
+}
+
 func ServerError(message string) Response {
 	if message == "" {
 		message = "Internal server error"
@@ -0,0 +1,148 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Record is a single data row keyed by the normalized header name
+type Record struct {
+	Line   int
+	Values map[string]string
+}
+
+// Format is a supported spreadsheet file format
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+)
+
+// DetectFormat returns the format of a file based on its extension
+func DetectFormat(filename string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return CSV, nil
+	case ".xlsx":
+		return XLSX, nil
+	}
+
+	return "", fmt.Errorf("unsupported file type %q, please upload a .csv or .xlsx file", filepath.Ext(filename))
+}
+
+// Read reads all data rows of a CSV or XLSX file. The first row is the
+// header; header names are trimmed and lowercased. Blank rows are skipped.
+func Read(r io.Reader, format Format) ([]Record, error) {
+	var rows [][]string
+	var lines []int
+	var err error
+
+	switch format {
+	case CSV:
+		rows, lines, err = readCSV(r)
+	case XLSX:
+		rows, lines, err = readXLSX(r)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	header := make([]string, len(rows[0]))
+	for i, name := range rows[0] {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+	}
+
+	records := []Record{}
+	for i, row := range rows[1:] {
+		if isBlank(row) {
+			continue
+		}
+
+		values := make(map[string]string, len(header))
+		for j, name := range header {
+			if name == "" || j >= len(row) {
+				continue
+			}
+			values[name] = strings.TrimSpace(row[j])
+		}
+
+		records = append(records, Record{Line: lines[i+1], Values: values})
+	}
+
+	return records, nil
+}
+
+// readCSV returns the rows with their 1-based line numbers, the csv
+// reader silently skips empty lines
+func readCSV(r io.Reader) ([][]string, []int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows [][]string
+	var lines []int
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid csv file: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, row)
+		lines = append(lines, line)
+	}
+
+	return rows, lines, nil
+}
+
+func readXLSX(r io.Reader) ([][]string, []int, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid xlsx file: %w", err)
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, nil, errors.New("xlsx file has no sheets")
+	}
+
+	// only the first sheet is imported
+	rows, err := file.GetRows(sheets[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid xlsx file: %w", err)
+	}
+
+	lines := make([]int, len(rows))
+	for i := range rows {
+		lines[i] = i + 1
+	}
+
+	return rows, lines, nil
+}
+
+func isBlank(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,244 @@
+package spreadsheet
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// NDJSON writes one JSON object per line, it is only supported for export
+const NDJSON Format = "ndjson"
+
+// flushEvery is the number of rows after which streamed output is flushed
+const flushEvery = 500
+
+// ParseFormat parses an export format name
+func ParseFormat(name string) (Format, error) {
+	switch Format(name) {
+	case CSV, XLSX, NDJSON:
+		return Format(name), nil
+	}
+
+	return "", fmt.Errorf("unsupported format %q, use csv, xlsx or ndjson", name)
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case NDJSON:
+		return "application/x-ndjson"
+	}
+
+	return "text/csv"
+}
+
+// Valuer is implemented by models that can be exported
+type Valuer interface {
+	ExportValue(column string) interface{}
+}
+
+// Writer writes rows of values in the order of its columns
+type Writer interface {
+	Write(values []interface{}) error
+	Close() error
+}
+
+// NewWriter creates a writer for format that writes to w. The header row is
+// written right away for csv and xlsx.
+func NewWriter(w io.Writer, format Format, columns []string) (Writer, error) {
+	switch format {
+	case CSV:
+		return newCSVWriter(w, columns)
+	case XLSX:
+		return newXLSXWriter(w, columns)
+	case NDJSON:
+		return &ndjsonWriter{out: w, buf: bufio.NewWriter(w), columns: columns}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// Values returns the values of item for columns
+func Values(item Valuer, columns []string) []interface{} {
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		values[i] = item.ExportValue(column)
+	}
+
+	return values
+}
+
+type csvWriter struct {
+	out  io.Writer
+	csv  *csv.Writer
+	rows int
+}
+
+func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+	writer := &csvWriter{out: w, csv: csv.NewWriter(w)}
+	if err := writer.csv.Write(columns); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+func (w *csvWriter) Write(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatValue(value)
+	}
+
+	if err := w.csv.Write(record); err != nil {
+		return err
+	}
+
+	w.rows++
+	if w.rows%flushEvery == 0 {
+		w.csv.Flush()
+		flush(w.out)
+	}
+
+	return w.csv.Error()
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// xlsxWriter uses the excelize stream writer, which keeps rows on disk
+// instead of in memory; the file itself can only be written at the end
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer, columns []string) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter(file.GetSheetName(0))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+
+	writer := &xlsxWriter{out: w, file: file, stream: stream, row: 1}
+	if err := writer.Write(header); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+func (w *xlsxWriter) Write(values []interface{}) error {
+	cells := make([]interface{}, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+			cells[i] = nil
+		case time.Time:
+			cells[i] = formatValue(v)
+		default:
+			cells[i] = v
+		}
+	}
+
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+	w.row++
+
+	return w.stream.SetRow(cell, cells)
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.file.Close()
+
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+
+	return w.file.Write(w.out)
+}
+
+type ndjsonWriter struct {
+	out     io.Writer
+	buf     *bufio.Writer
+	columns []string
+	rows    int
+}
+
+// Write writes the values as a JSON object keeping the column order
+func (w *ndjsonWriter) Write(values []interface{}) error {
+	w.buf.WriteByte('{')
+	for i, column := range w.columns {
+		if i > 0 {
+			w.buf.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(column)
+		value, err := json.Marshal(values[i])
+		if err != nil {
+			return err
+		}
+
+		w.buf.Write(key)
+		w.buf.WriteByte(':')
+		w.buf.Write(value)
+	}
+	w.buf.WriteString("}\n")
+
+	w.rows++
+	if w.rows%flushEvery == 0 {
+		if err := w.buf.Flush(); err != nil {
+			return err
+		}
+		flush(w.out)
+	}
+
+	return nil
+}
+
+func (w *ndjsonWriter) Close() error {
+	return w.buf.Flush()
+}
+
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// flush pushes buffered output to the client when streaming over HTTP
+func flush(w io.Writer) {
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}